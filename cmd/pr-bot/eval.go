@@ -0,0 +1,320 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/go-github/v50/github"
+	"github.com/marqeta/pr-bot/oci"
+	"github.com/marqeta/pr-bot/opa"
+	"github.com/marqeta/pr-bot/opa/client"
+	"github.com/marqeta/pr-bot/opa/evaluation"
+	"github.com/marqeta/pr-bot/opa/input"
+	"github.com/marqeta/pr-bot/opa/types"
+)
+
+const (
+	evalCommand    = "eval"
+	evalDeliveryID = "pr-bot-eval"
+)
+
+var (
+	errNoBundle        = errors.New("-bundle is required")
+	errNoEvalInput     = errors.New("one of -event or -report is required")
+	errEvalInput       = errors.New("only one of -event or -report can be set")
+	errUnsupportedType = errors.New("unsupported webhook event, expected pull_request or pull_request_review")
+	errReportNoInput   = errors.New("report does not contain an input")
+	errInvalidFormat   = errors.New("format must be text or json")
+)
+
+type evalArgs struct {
+	bundle  string
+	event   string
+	name    string
+	report  string
+	plugins string
+	format  string
+	timeout time.Duration
+}
+
+// evalOutput is printed by the eval command.
+type evalOutput struct {
+	Modules   []string              `json:"modules"`
+	Breakdown map[string]evalResult `json:"breakdown"`
+	Outcome   evalResult            `json:"outcome"`
+	Input     *input.Model          `json:"input,omitempty"`
+}
+
+type evalResult struct {
+	Result types.Result `json:"result"`
+	Err    string       `json:"err,omitempty"`
+}
+
+// savedPlugin replays a plugin message which was saved with a report or passed with -plugins.
+type savedPlugin struct {
+	name string
+	msg  json.RawMessage
+}
+
+func (p *savedPlugin) GetInputMsg(_ context.Context, _ input.GHE) (json.RawMessage, error) {
+	return p.msg, nil
+}
+
+func (p *savedPlugin) Name() string {
+	return p.name
+}
+
+// runEval evaluates a local bundle against a saved event, without access to AWS or GitHub.
+// returns the exit code of the command.
+func runEval(argv []string, stdout, stderr io.Writer) int {
+	args, err := parseEvalArgs(argv, stderr)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	out, err := eval(context.Background(), args)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	if args.format == "json" {
+		err = printEvalJSON(stdout, out)
+	} else {
+		err = printEvalText(stdout, out)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func parseEvalArgs(argv []string, stderr io.Writer) (evalArgs, error) {
+	var args evalArgs
+	f := flag.NewFlagSet("pr-bot eval", flag.ContinueOnError)
+	f.SetOutput(stderr)
+	f.StringVar(&args.bundle, "bundle", "", "file path to the OPA bundle tarball")
+	f.StringVar(&args.event, "event", "", "file path to a saved webhook payload")
+	f.StringVar(&args.name, "event-name", "pull_request", "X-GitHub-Event of the saved webhook payload")
+	f.StringVar(&args.report, "report", "", "file path to a saved evaluation report, its input is evaluated")
+	f.StringVar(&args.plugins, "plugins", "", "file path to a JSON object of plugin name to plugin input, used with -event")
+	f.StringVar(&args.format, "format", "text", "output format, text or json")
+	f.DurationVar(&args.timeout, "timeout", time.Minute, "how long to wait for the bundle to be activated")
+	f.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: pr-bot eval -bundle <bundle.tar.gz> (-event <payload.json> | -report <report.json>)")
+		f.PrintDefaults()
+	}
+	err := f.Parse(argv)
+	if err != nil {
+		return args, err
+	}
+	switch {
+	case args.bundle == "":
+		return args, errNoBundle
+	case args.event == "" && args.report == "":
+		return args, errNoEvalInput
+	case args.event != "" && args.report != "":
+		return args, errEvalInput
+	case args.format != "text" && args.format != "json":
+		return args, errInvalidFormat
+	}
+	return args, nil
+}
+
+func eval(ctx context.Context, args evalArgs) (*evalOutput, error) {
+	ghe, plugins, err := loadEvalInput(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	bundlePath, err := filepath.Abs(args.bundle)
+	if err != nil {
+		return nil, err
+	}
+	reader := oci.NewReader()
+	dirs, err := reader.ListDirs(ctx, bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading OPA bundle %v: %w", bundlePath, err)
+	}
+	modules := reader.FilterModules(ctx, dirs)
+	if len(modules) == 0 {
+		return nil, fmt.Errorf("%w: %v", opa.ErrNoModules, bundlePath)
+	}
+	// evaluate in a stable order, so that runs are comparable
+	slices.Sort(modules)
+
+	opaClient, err := client.NewBundleClient(ctx, evalDeliveryID,
+		map[string]string{"app": "pr-bot", "environment": "local"}, bundlePath, args.timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer opaClient.Stop(ctx)
+
+	manager := evaluation.NewInMemoryManager(filepath.Base(bundlePath))
+	evaluator := opa.NewEvaluator(modules, setUpOPAPolicies(opaClient), input.NewFactory(plugins...), manager)
+	ctx = evaluation.SetDeliveryID(ctx, evalDeliveryID)
+	// evaluation errors are recorded in the report
+	_, _ = evaluator.Evaluate(ctx, ghe)
+
+	pr := fmt.Sprintf("%s/%d", ghe.Repository.GetFullName(), ghe.PullRequest.GetNumber())
+	report, err := manager.GetReport(ctx, pr, evalDeliveryID)
+	if err != nil {
+		return nil, err
+	}
+	return toEvalOutput(modules, report), nil
+}
+
+// loadEvalInput reads the event to evaluate and the plugin inputs to replay.
+func loadEvalInput(ctx context.Context, args evalArgs) (input.GHE, []input.Plugin, error) {
+	if args.report != "" {
+		// only the input is decoded, errors in the breakdown are not JSON decodable
+		var report struct {
+			Input *input.Model `json:"input"`
+		}
+		err := readJSONFile(args.report, &report)
+		if err != nil {
+			return input.GHE{}, nil, err
+		}
+		if report.Input == nil {
+			return input.GHE{}, nil, errReportNoInput
+		}
+		return input.GHE{
+			Event:        report.Input.Event,
+			Action:       report.Input.Action,
+			PullRequest:  report.Input.PullRequest,
+			Repository:   report.Input.Repository,
+			Organization: report.Input.Organization,
+		}, toSavedPlugins(report.Input.Plugins), nil
+	}
+
+	payload, err := os.ReadFile(args.event)
+	if err != nil {
+		return input.GHE{}, nil, err
+	}
+	event, err := github.ParseWebHook(args.name, payload)
+	if err != nil {
+		return input.GHE{}, nil, err
+	}
+	// adapter does not call GitHub to convert webhook events
+	adapter := input.NewAdapter(nil)
+	var ghe input.GHE
+	switch e := event.(type) {
+	case *github.PullRequestEvent:
+		ghe, err = adapter.PREventToGHE(ctx, e)
+	case *github.PullRequestReviewEvent:
+		ghe, err = adapter.PRReviewEventToGHE(ctx, e)
+	default:
+		return input.GHE{}, nil, fmt.Errorf("%w: %v", errUnsupportedType, args.name)
+	}
+	if err != nil {
+		return input.GHE{}, nil, err
+	}
+
+	plugins := make(map[string]json.RawMessage)
+	if args.plugins != "" {
+		err = readJSONFile(args.plugins, &plugins)
+		if err != nil {
+			return input.GHE{}, nil, err
+		}
+	}
+	return ghe, toSavedPlugins(plugins), nil
+}
+
+func toSavedPlugins(msgs map[string]json.RawMessage) []input.Plugin {
+	plugins := make([]input.Plugin, 0, len(msgs))
+	for name, msg := range msgs {
+		plugins = append(plugins, &savedPlugin{name: name, msg: msg})
+	}
+	return plugins
+}
+
+func readJSONFile(path string, v any) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(b, v)
+	if err != nil {
+		return fmt.Errorf("error parsing %v: %w", path, err)
+	}
+	return nil
+}
+
+func toEvalOutput(modules []string, report *evaluation.Report) *evalOutput {
+	out := &evalOutput{
+		Modules:   modules,
+		Breakdown: make(map[string]evalResult, len(report.Breakdown)),
+		Outcome:   toEvalResult(report.Outcome),
+		Input:     report.Input,
+	}
+	for module, result := range report.Breakdown {
+		out.Breakdown[module] = toEvalResult(result)
+	}
+	return out
+}
+
+func toEvalResult(r evaluation.Result) evalResult {
+	result := evalResult{Result: r.Result}
+	if r.Err != nil {
+		result.Err = r.Err.Error()
+	}
+	return result
+}
+
+func printEvalJSON(w io.Writer, out *evalOutput) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func printEvalText(w io.Writer, out *evalOutput) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "MODULE\tTRACK\tREVIEW\tBODY\tERROR")
+	for _, module := range out.Modules {
+		r, ok := out.Breakdown[module]
+		if !ok {
+			// disabled modules, and modules skipped on request after an aborted evaluation,
+			// have no result in the report
+			fmt.Fprintf(tw, "%v\t-\t-\tnot evaluated\t\n", module)
+			continue
+		}
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\n", module, r.Result.Track, r.Result.Review.Type,
+			oneLine(r.Result.Review.Body), r.Err)
+	}
+	err := tw.Flush()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w)
+	if out.Outcome.Err != "" {
+		fmt.Fprintf(w, "Outcome: evaluation failed: %v\n", out.Outcome.Err)
+		return nil
+	}
+	fmt.Fprintf(w, "Outcome: %v\n", out.Outcome.Result.Review.Type)
+	if out.Outcome.Result.Review.Body != "" {
+		fmt.Fprintf(w, "\n%v\n", out.Outcome.Result.Review.Body)
+	}
+	return nil
+}
+
+// oneLine collapses whitespace in s and truncates it to 60 characters.
+func oneLine(s string) string {
+	r := []rune(strings.Join(strings.Fields(s), " "))
+	if len(r) > 60 {
+		return string(r[:57]) + "..."
+	}
+	return string(r)
+}
@@ -0,0 +1,311 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/google/go-github/v50/github"
+	"github.com/marqeta/pr-bot/opa/input"
+	"github.com/marqeta/pr-bot/opa/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseEvalArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		argv    []string
+		want    evalArgs
+		wantErr error
+	}{
+		{
+			name: "Should parse event args with defaults",
+			argv: []string{"-bundle", "bundle.tar.gz", "-event", "event.json"},
+			want: evalArgs{bundle: "bundle.tar.gz", event: "event.json", name: "pull_request",
+				format: "text", timeout: time.Minute},
+		},
+		{
+			name: "Should parse report args",
+			argv: []string{"-bundle", "bundle.tar.gz", "-report", "report.json", "-format", "json",
+				"-timeout", "5s"},
+			want: evalArgs{bundle: "bundle.tar.gz", report: "report.json", name: "pull_request",
+				format: "json", timeout: 5 * time.Second},
+		},
+		{
+			name: "Should parse event name and plugins",
+			argv: []string{"-bundle", "bundle.tar.gz", "-event", "event.json",
+				"-event-name", "pull_request_review", "-plugins", "plugins.json"},
+			want: evalArgs{bundle: "bundle.tar.gz", event: "event.json", name: "pull_request_review",
+				plugins: "plugins.json", format: "text", timeout: time.Minute},
+		},
+		{
+			name:    "Should return error when bundle is not set",
+			argv:    []string{"-event", "event.json"},
+			wantErr: errNoBundle,
+		},
+		{
+			name:    "Should return error when neither event nor report is set",
+			argv:    []string{"-bundle", "bundle.tar.gz"},
+			wantErr: errNoEvalInput,
+		},
+		{
+			name:    "Should return error when both event and report are set",
+			argv:    []string{"-bundle", "bundle.tar.gz", "-event", "event.json", "-report", "report.json"},
+			wantErr: errEvalInput,
+		},
+		{
+			name:    "Should return error when format is invalid",
+			argv:    []string{"-bundle", "bundle.tar.gz", "-event", "event.json", "-format", "yaml"},
+			wantErr: errInvalidFormat,
+		},
+		{
+			name:    "Should return help error when help is requested",
+			argv:    []string{"-h"},
+			wantErr: flag.ErrHelp,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseEvalArgs(tt.argv, io.Discard)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_loadEvalInput(t *testing.T) {
+	ctx := context.TODO()
+	pr := &github.PullRequest{Number: aws.Int(1), Title: aws.String("random title")}
+	repo := &github.Repository{FullName: aws.String("owner/repo")}
+	plugins := map[string]json.RawMessage{"files_changed": json.RawMessage(`["main.go"]`)}
+	dir := t.TempDir()
+	prEvent := writeJSON(t, dir, "pr.json",
+		github.PullRequestEvent{Action: aws.String("opened"), PullRequest: pr, Repo: repo})
+	reviewEvent := writeJSON(t, dir, "review.json",
+		github.PullRequestReviewEvent{Action: aws.String("submitted"), PullRequest: pr, Repo: repo})
+	pluginsFile := writeJSON(t, dir, "plugins.json", plugins)
+	report := writeJSON(t, dir, "report.json", map[string]any{
+		"input": input.Model{Event: "pull_request", Action: "synchronize", PullRequest: pr,
+			Repository: repo, Plugins: plugins},
+		"breakdown": map[string]any{"module1": map[string]any{"Err": map[string]any{}}},
+	})
+	noInput := writeJSON(t, dir, "no_input.json", map[string]any{"breakdown": map[string]any{}})
+	invalid := filepath.Join(dir, "invalid.json")
+	assert.Nil(t, os.WriteFile(invalid, []byte(`{"input": `), 0o600))
+
+	tests := []struct {
+		name        string
+		args        evalArgs
+		want        input.GHE
+		wantPlugins map[string]json.RawMessage
+		wantErr     error
+	}{
+		{
+			name:        "Should load pull_request event without plugins",
+			args:        evalArgs{event: prEvent, name: "pull_request"},
+			want:        input.GHE{Event: "pull_request", Action: "opened", PullRequest: pr, Repository: repo},
+			wantPlugins: map[string]json.RawMessage{},
+		},
+		{
+			name: "Should load pull_request_review event with plugins",
+			args: evalArgs{event: reviewEvent, name: "pull_request_review", plugins: pluginsFile},
+			want: input.GHE{Event: "pull_request_review", Action: "submitted", PullRequest: pr,
+				Repository: repo},
+			wantPlugins: plugins,
+		},
+		{
+			name: "Should load input and plugins of report",
+			args: evalArgs{report: report},
+			want: input.GHE{Event: "pull_request", Action: "synchronize", PullRequest: pr,
+				Repository: repo},
+			wantPlugins: plugins,
+		},
+		{
+			name:    "Should return error when report has no input",
+			args:    evalArgs{report: noInput},
+			wantErr: errReportNoInput,
+		},
+		{
+			name:    "Should return error when event is not supported",
+			args:    evalArgs{event: prEvent, name: "issues"},
+			wantErr: errUnsupportedType,
+		},
+		{
+			name:    "Should return error when event file does not exist",
+			args:    evalArgs{event: filepath.Join(dir, "missing.json"), name: "pull_request"},
+			wantErr: os.ErrNotExist,
+		},
+		{
+			name:    "Should return error when plugins file cannot be parsed",
+			args:    evalArgs{event: prEvent, name: "pull_request", plugins: invalid},
+			wantErr: &json.SyntaxError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotPlugins, err := loadEvalInput(ctx, tt.args)
+			if tt.wantErr != nil {
+				var syntaxErr *json.SyntaxError
+				if errors.As(tt.wantErr, &syntaxErr) {
+					assert.ErrorAs(t, err, &syntaxErr)
+				} else {
+					assert.ErrorIs(t, err, tt.wantErr)
+				}
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, toJSON(t, tt.want), toJSON(t, got))
+			msgs := make(map[string]json.RawMessage, len(gotPlugins))
+			for _, p := range gotPlugins {
+				msg, err := p.GetInputMsg(ctx, got)
+				assert.Nil(t, err)
+				msgs[p.Name()] = msg
+			}
+			assert.Equal(t, tt.wantPlugins, msgs)
+		})
+	}
+}
+
+func Test_printEvalText(t *testing.T) {
+	approve := types.Result{Track: true, Review: types.Review{Type: types.Approve, Body: "LGTM"}}
+	tests := []struct {
+		name string
+		out  *evalOutput
+		want string
+	}{
+		{
+			name: "Should print breakdown and outcome",
+			out: &evalOutput{
+				Modules: []string{"module1", "module2", "module3"},
+				Breakdown: map[string]evalResult{
+					"module1": {Result: approve},
+					"module2": {Result: types.Result{Track: false}},
+				},
+				Outcome: evalResult{Result: approve},
+			},
+			want: "MODULE   TRACK  REVIEW   BODY           ERROR\n" +
+				"module1  true   APPROVE  LGTM           \n" +
+				"module2  false  SKIP                    \n" +
+				"module3  -      -        not evaluated  \n" +
+				"\n" +
+				"Outcome: APPROVE\n" +
+				"\n" +
+				"LGTM\n",
+		},
+		{
+			name: "Should print error of failed evaluation",
+			out: &evalOutput{
+				Modules: []string{"module1"},
+				Breakdown: map[string]evalResult{
+					"module1": {Err: "random error"},
+				},
+				Outcome: evalResult{Err: "random error"},
+			},
+			want: "MODULE   TRACK  REVIEW  BODY  ERROR\n" +
+				"module1  false  SKIP          random error\n" +
+				"\n" +
+				"Outcome: evaluation failed: random error\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var w bytes.Buffer
+			err := printEvalText(&w, tt.out)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, w.String())
+		})
+	}
+}
+
+func Test_printEvalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		out  *evalOutput
+		want string
+	}{
+		{
+			name: "Should print breakdown and outcome",
+			out: &evalOutput{
+				Modules: []string{"module1"},
+				Breakdown: map[string]evalResult{
+					"module1": {Result: types.Result{Track: true, Review: types.Review{Type: types.Approve}}},
+				},
+				Outcome: evalResult{Result: types.Result{Track: true, Review: types.Review{Type: types.Approve}}},
+			},
+			want: `{
+				"modules": ["module1"],
+				"breakdown": {"module1": {"result": {"Track": true, "Review": {"type": "APPROVE", "body": ""}}}},
+				"outcome": {"result": {"Track": true, "Review": {"type": "APPROVE", "body": ""}}}
+			}`,
+		},
+		{
+			name: "Should print error of failed evaluation",
+			out: &evalOutput{
+				Modules:   []string{"module1"},
+				Breakdown: map[string]evalResult{"module1": {Err: "random error"}},
+				Outcome:   evalResult{Err: "random error"},
+			},
+			want: `{
+				"modules": ["module1"],
+				"breakdown": {"module1": {"result": {"Track": false, "Review": {"type": "SKIP", "body": ""}},
+					"err": "random error"}},
+				"outcome": {"result": {"Track": false, "Review": {"type": "SKIP", "body": ""}},
+					"err": "random error"}
+			}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var w bytes.Buffer
+			err := printEvalJSON(&w, tt.out)
+			assert.Nil(t, err)
+			assert.JSONEq(t, tt.want, w.String())
+		})
+	}
+}
+
+func Test_oneLine(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{name: "Should collapse whitespace", s: "  line 1\n\n\tline 2 ", want: "line 1 line 2"},
+		{name: "Should not truncate 60 characters", s: strings.Repeat("a", 60),
+			want: strings.Repeat("a", 60)},
+		{name: "Should truncate long body", s: strings.Repeat("a", 61),
+			want: strings.Repeat("a", 57) + "..."},
+		{name: "Should truncate by character", s: strings.Repeat("é", 61),
+			want: strings.Repeat("é", 57) + "..."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, oneLine(tt.s))
+		})
+	}
+}
+
+func writeJSON(t *testing.T, dir, name string, v any) string {
+	path := filepath.Join(dir, name)
+	assert.Nil(t, os.WriteFile(path, toJSON(t, v), 0o600))
+	return path
+}
+
+func toJSON(t *testing.T, v any) []byte {
+	b, err := json.Marshal(v)
+	assert.Nil(t, err)
+	return b
+}
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"net/http"
@@ -9,7 +8,6 @@ import (
 
 	"github.com/google/go-github/v50/github"
 	"github.com/jonboulle/clockwork"
-	"github.com/open-policy-agent/opa/v1/sdk"
 	"github.com/rs/zerolog/log"
 	"github.com/shurcooL/githubv4"
 	"golang.org/x/oauth2"
@@ -28,6 +26,7 @@ import (
 	"github.com/marqeta/pr-bot/opa/input/plugins"
 	"github.com/marqeta/pr-bot/pullrequest"
 	"github.com/marqeta/pr-bot/pullrequest/review"
+	"github.com/marqeta/pr-bot/queue"
 	"github.com/marqeta/pr-bot/rate"
 	"github.com/marqeta/pr-bot/secrets"
 	"github.com/marqeta/pr-bot/ui"
@@ -42,6 +41,10 @@ const (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == evalCommand {
+		os.Exit(runEval(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	cfg, err := prbot.ParseConfigFiles()
 	if err != nil {
 		os.Exit(1)
@@ -55,27 +58,44 @@ func main() {
 		os.Exit(1)
 	}
 
-	downloadOCIArtifacts(svc, cfg)
+	bundles, loader := setUpOPABundles(svc, cfg)
+	watcher := setUpOPABundleWatcher(svc, cfg, bundles, loader)
 
 	ghAPI := setupGHAPI(svc, cfg)
-	eventHandler := setupEventHandler(svc, cfg, ghAPI)
+	store := setupDatastore(svc, cfg)
+	opaEvaluator := setUpOPAEvaluator(ghAPI, store, cfg, svc, bundles)
+	eventHandler := setupEventHandler(svc, cfg, ghAPI, opaEvaluator)
+
+	q, dlq := setupQueue(svc, cfg)
+	pool := setupWorkerPool(svc, cfg, ghAPI, eventHandler, opaEvaluator, bundles, q, dlq)
 
 	endpoints := make([]prbot.Endpoint, 0)
-	endpoints = append(endpoints, webhookEndpoint(svc, cfg, ghAPI, eventHandler))
+	endpoints = append(endpoints, webhookEndpoint(svc, cfg, q))
 	endpoints = append(endpoints, ui.NewEndpoint(svc.EvaluationManager, svc.Metrics))
-	endpoints = append(endpoints, dataEndpoint(svc, cfg, eventHandler))
+	endpoints = append(endpoints, dataEndpoint(svc, store, eventHandler))
 	svc.MountRoutes(healthcheck.NewEndpoint(svc.Metrics), endpoints)
 
 	srv := prbot.NewServer(cfg, svc.Router)
 	go srv.Start()
+	pool.Start()
+	if watcher != nil {
+		watcher.Start()
+	}
 	srv.WaitForGracefulShutdown()
+	if watcher != nil {
+		watcher.Close()
+	}
+	pool.Close()
 	svc.Close()
 }
 
-func dataEndpoint(svc *prbot.Service, cfg *prbot.Config, eh pullrequest.EventHandler) prbot.Endpoint {
-	log.Info().Msg("Setting up data endpoint")
-	dao := datastore.NewDynamoDao(svc.DDB, svc.Metrics, cfg.Datastore.TTL, cfg.Datastore.Table)
+func setupDatastore(svc *prbot.Service, cfg *prbot.Config) datastore.Dao {
+	log.Info().Msg("Setting up datastore")
+	return datastore.NewDynamoDao(svc.DDB, svc.Metrics, cfg.Datastore.TTL, cfg.Datastore.Table)
+}
 
+func dataEndpoint(svc *prbot.Service, dao datastore.Dao, eh pullrequest.EventHandler) prbot.Endpoint {
+	log.Info().Msg("Setting up data endpoint")
 	verifier := &identity.STSVerifier{
 		HTTPClient: http.DefaultClient,
 		Validator:  &identity.AllowAllValidator{},
@@ -84,88 +104,124 @@ func dataEndpoint(svc *prbot.Service, cfg *prbot.Config, eh pullrequest.EventHan
 	return data.NewEndpoint(dao, svc.Metrics, eh, verifier)
 }
 
-func setUpOPAClient(cfg *prbot.Config) client.Client {
-	log.Info().Msg("Setting up OPA client")
-	config := fmt.Sprintf(`
-	{
-	   "labels": {
-	      "app": "%s",
-	      "region": "us-east-2",
-	      "environment": "%s"
-	   },
-	   "bundles": {
-	      "local": {
-	         "resource": "file:///%s/%s"
-	      }
-	   }
-	}`, cfg.ServiceName, cfg.Env, cfg.OPA.Bundles.Root, cfg.OPA.Bundles.Filename)
-	// TODO this can block indefinitely, use channel to signal completion and set timeout
-	opaSDK, err := sdk.New(context.Background(), sdk.Options{
-		ID:     fmt.Sprintf("%s-%s", cfg.ServiceName, cfg.Env),
-		Config: bytes.NewReader([]byte(config)),
-	})
-	if err != nil {
-		log.Err(err).Msg("Error creating OPA SDK client")
-		os.Exit(1)
+func setUpOPAClient(cfg *prbot.Config) func(ctx context.Context, bundlePath string) (client.Client, error) {
+	labels := map[string]string{
+		"app":         cfg.ServiceName,
+		"region":      "us-east-2",
+		"environment": cfg.Env,
+	}
+	id := fmt.Sprintf("%s-%s", cfg.ServiceName, cfg.Env)
+	return func(ctx context.Context, bundlePath string) (client.Client, error) {
+		log.Info().Msgf("Setting up OPA client for %v", bundlePath)
+		return client.NewBundleClient(ctx, id, labels, bundlePath, cfg.OPA.Bundles.ReadyTimeout)
 	}
-	log.Info().Msg("Successfully created OPA SDK client")
-	return client.NewClient(opaSDK)
 }
 
-func setUpInputFactory(api gh.API) input.Factory {
+func setUpInputFactory(api gh.API, store datastore.Dao, cfg *prbot.Config) input.Factory {
 	log.Info().Msg("Setting up input factory")
 	branchProtection := plugins.NewBranchProtection(api)
 	// 100KB size limit
 	filesChanged := plugins.NewFilesChanged(api, 100*1000)
 	pullRequestReviewers := plugins.NewPullRequestReviewers(api)
-	return input.NewFactory(branchProtection, filesChanged, pullRequestReviewers)
+	checks := plugins.NewChecks(api, cfg.GHE.Checks.Name)
+	// 100KB size limit of commit messages
+	commits := plugins.NewCommits(api, 100*1000)
+	codeowners := plugins.NewCodeowners(api)
+	data := plugins.NewData(store)
+	membership := plugins.NewMembership(api, cfg.GHE.Membership.ServiceAccounts, cfg.GHE.Membership.CacheTTL, clockwork.NewRealClock())
+	// base and head of at most 20 manifests are fetched
+	dependencies := plugins.NewDependencies(api, 20)
+	return input.NewFactory(branchProtection, filesChanged, pullRequestReviewers, checks, commits, codeowners, data,
+		membership, dependencies)
 }
 
 func setUpOPAPolicies(opaClient client.Client) opa.Policy {
 	log.Info().Msg("Setting up OPA policies")
 	v1 := opa.NewV1Policy(opaClient)
+	v2 := opa.NewV2Policy(opaClient)
 	return opa.NewVersionedPolicy(
-		map[string]opa.Policy{"v1": v1},
+		map[string]opa.Policy{"v1": v1, "v2": v2},
 		opaClient,
 	)
 }
 
-func FindOPAModules(cfg *prbot.Config) []string {
-	log.Info().Msg("Finding OPA modules")
-	reader := oci.NewReader()
-	filepath := fmt.Sprintf("%s/%s", cfg.OPA.Bundles.Root, cfg.OPA.Bundles.Filename)
-	dirs, err := reader.ListDirs(context.Background(), filepath)
-	if err != nil {
-		log.Err(err).Msg("Error reading OPA bundle directories")
-		os.Exit(1)
+func setUpOPAEvaluator(api gh.API, store datastore.Dao, cfg *prbot.Config, svc *prbot.Service,
+	bundles *opa.BundleHolder) opa.Evaluator {
+	log.Info().Msg("Setting up OPA evaluator")
+	factory := setUpInputFactory(api, store, cfg)
+	modes := setUpOPAModuleModes(svc, cfg)
+	breaker := setUpOPACircuitBreaker(svc, cfg)
+	return opa.NewReloadableEvaluator(bundles, modes, breaker, factory, svc.EvaluationManager, opa.EvaluatorConfig{
+		Workers:       cfg.OPA.Evaluation.Workers,
+		ModuleTimeout: cfg.OPA.Evaluation.ModuleTimeout,
+	}, clockwork.NewRealClock(), svc.Metrics)
+}
+
+func setUpOPACircuitBreaker(svc *prbot.Service, cfg *prbot.Config) opa.CircuitBreaker {
+	if !cfg.OPA.CircuitBreaker.Enabled {
+		return opa.NewNoopCircuitBreaker()
 	}
-	modules := reader.FilterModules(context.Background(), dirs)
-	log.Info().Interface("Modules", modules).Msg("Found OPA modules")
-	return modules
+	log.Info().Msg("Setting up OPA module circuit breaker")
+	return opa.NewCircuitBreaker(opa.CircuitBreakerConfig{
+		Window:         cfg.OPA.CircuitBreaker.Window,
+		MinEvaluations: cfg.OPA.CircuitBreaker.MinEvaluations,
+		Threshold:      cfg.OPA.CircuitBreaker.Threshold,
+		Cooldown:       cfg.OPA.CircuitBreaker.Cooldown,
+	}, clockwork.NewRealClock(), svc.Metrics)
 }
 
-func setUpOPAEvaluator(api gh.API, cfg *prbot.Config, svc *prbot.Service) opa.Evaluator {
-	log.Info().Msg("Setting up OPA evaluator")
-	client := setUpOPAClient(cfg)
-	modules := FindOPAModules(cfg)
-	policy := setUpOPAPolicies(client)
-	factory := setUpInputFactory(api)
-	return opa.NewEvaluator(modules, policy, factory, svc.EvaluationManager)
+func setUpOPAModuleModes(svc *prbot.Service, cfg *prbot.Config) configstore.Getter[*opa.ModuleCfg] {
+	if !cfg.OPA.ModuleModes {
+		// all modules are enforced
+		modes, err := configstore.NewInMemoryStore(&opa.ModuleCfg{})
+		if err != nil {
+			log.Err(err).Msg("Error creating OPAModuleConfig store")
+			os.Exit(1)
+		}
+		return modes
+	}
+	csDao := configstore.NewDynamoDao[*opa.ModuleCfg](svc.DDB, svc.Metrics)
+	ticker := clockwork.NewRealClock().NewTicker(cfg.ConfigStore.Refresh)
+	modes, err := configstore.NewDBStore(csDao, "OPAModuleConfig", cfg.ConfigStore.Table, ticker, svc.Metrics)
+	if err != nil {
+		log.Err(err).Msg("Error creating OPAModuleConfig store")
+		os.Exit(1)
+	}
+	return modes
 }
 
-func downloadOCIArtifacts(svc *prbot.Service, cfg *prbot.Config) {
-	log.Info().Msg("Downloading OPA bundle from ECR")
+func setUpOPABundles(svc *prbot.Service, cfg *prbot.Config) (*opa.BundleHolder, opa.BundleLoader) {
+	log.Info().Msg("Loading OPA bundle from ECR")
 	puller := oci.NewECRPuller(oci.NewECRCredRetriever(svc.ECR))
-	err := puller.Pull(context.Background(), oci.ArtifactID{
+	loader := opa.NewOCIBundleLoader(puller, oci.NewReader(), oci.ArtifactID{
 		Registry: cfg.OPA.Bundles.ECR.Registry,
 		Repo:     cfg.OPA.Bundles.ECR.Repo,
-		Tag:      cfg.OPA.Bundles.ECR.Tag,
-	}, cfg.OPA.Bundles.Root)
+	}, cfg.OPA.Bundles.Root, cfg.OPA.Bundles.Filename, setUpOPAClient(cfg), setUpOPAPolicies)
+	bundle, err := loader.Load(context.Background(), cfg.OPA.Bundles.ECR.Tag)
 	if err != nil {
-		log.Err(err).Msg("Error pulling OPA bundle from ECR")
+		log.Err(err).Msg("Error loading OPA bundle from ECR")
 		os.Exit(1)
 	}
-	log.Info().Msg("Successfully pulled OPA bundle from ECR")
+	log.Info().Interface("Modules", bundle.Modules).Msgf("Successfully loaded OPA bundle %v", bundle.Version)
+	return opa.NewBundleHolder(bundle), loader
+}
+
+func setUpOPABundleWatcher(svc *prbot.Service, cfg *prbot.Config,
+	bundles *opa.BundleHolder, loader opa.BundleLoader) *opa.BundleWatcher {
+	if !cfg.OPA.Bundles.Watch.Enabled {
+		return nil
+	}
+	log.Info().Msg("Setting up OPA bundle watcher")
+	csDao := configstore.NewDynamoDao[*opa.BundleCfg](svc.DDB, svc.Metrics)
+	clock := clockwork.NewRealClock()
+	desired, err := configstore.NewDBStore(csDao, "OPABundleConfig",
+		cfg.ConfigStore.Table, clock.NewTicker(cfg.ConfigStore.Refresh), svc.Metrics)
+	if err != nil {
+		log.Err(err).Msg("Error creating OPABundleConfig store")
+		os.Exit(1)
+	}
+	return opa.NewBundleWatcher(bundles, loader, desired, clock,
+		cfg.OPA.Bundles.Watch.Interval, cfg.OPA.Bundles.Watch.Grace, svc.Metrics)
 }
 
 func setupReviewer(svc *prbot.Service, cfg *prbot.Config, api gh.API) review.Reviewer {
@@ -184,32 +240,80 @@ func setupReviewer(svc *prbot.Service, cfg *prbot.Config, api gh.API) review.Rev
 	return review.NewMutexReviewer(dedup, locker)
 }
 
-func webhookEndpoint(svc *prbot.Service, cfg *prbot.Config, api gh.API, handler pullrequest.EventHandler) prbot.Endpoint {
+func webhookEndpoint(svc *prbot.Service, cfg *prbot.Config, q queue.Queue) prbot.Endpoint {
 	log.Info().Msg("Setting up webhook endpoint")
 	ws, err := svc.Secrets.GetSecret(context.Background(), cfg.AWS.Secrets.Webhook)
 	if err != nil {
 		log.Err(err).Msg("Error retrieving webhook secret")
 		os.Exit(1)
 	}
-	filter := setupEventFilters(svc, cfg, api)
-	d := pullrequest.NewDispatcher(handler, filter, svc.Metrics)
 	p := webhook.NewGHEventsParser()
-	return webhook.NewEndpoint(ws, p, d, svc.Metrics)
+	return webhook.NewEndpoint(ws, p, q, svc.Metrics)
+}
+
+func setupQueue(svc *prbot.Service, cfg *prbot.Config) (queue.Queue, queue.DeadLetterStore) {
+	log.Info().Msg("Setting up webhook queue")
+	clock := clockwork.NewRealClock()
+	if cfg.Queue.InMemory {
+		return queue.NewInMemoryQueue(clock, cfg.Queue.VisibilityTimeout), queue.NewInMemoryDeadLetterStore()
+	}
+	q := queue.NewDynamoQueue(svc.DDB, clock, svc.Metrics, cfg.Queue.Table,
+		pullrequest.EventName, cfg.Queue.TTL, cfg.Queue.VisibilityTimeout)
+	dlq := queue.NewDynamoDeadLetterStore(svc.DDB, clock, svc.Metrics, cfg.Queue.DeadLetterTable,
+		pullrequest.EventName, cfg.Queue.TTL)
+	return q, dlq
+}
+
+func setupWorkerPool(svc *prbot.Service, cfg *prbot.Config, api gh.API, handler pullrequest.EventHandler,
+	opaEvaluator opa.Evaluator, bundles *opa.BundleHolder, q queue.Queue, dlq queue.DeadLetterStore) *queue.Pool {
+	log.Info().Msg("Setting up queue workers")
+	filter := setupEventFilters(svc, cfg, api)
+	commands := pullrequest.NewCommandHandler(api, handler, opaEvaluator, svc.EvaluationManager, bundles, svc.Metrics)
+	d := pullrequest.NewDispatcher(handler, filter, commands, api, svc.Metrics, cfg.GHE.ServiceAccount,
+		cfg.GHE.App.ID, cfg.GHE.Checks.Name)
+	p := webhook.NewProcessor(webhook.NewGHEventsParser(), d)
+	return queue.NewPool(q, dlq, p, queue.PoolConfig{
+		Workers:      cfg.Queue.Workers,
+		MaxAttempts:  cfg.Queue.MaxAttempts,
+		PollInterval: cfg.Queue.PollInterval,
+		BaseBackoff:  cfg.Queue.BaseBackoff,
+		MaxBackoff:   cfg.Queue.MaxBackoff,
+		Timeout:      cfg.Queue.Timeout,
+	}, clockwork.NewRealClock(), svc.Metrics)
 }
 
 func setupGHAPI(svc *prbot.Service, cfg *prbot.Config) gh.API {
-	v3, v4 := setupGHEClients(svc.Secrets, cfg)
-	prDao := gh.NewAPI(cfg.Server.Host, cfg.Server.Port, v3, v4, svc.Metrics)
+	clients := setupGHEClients(svc, cfg)
+	prDao := gh.NewAPI(cfg.Server.Host, cfg.Server.Port, clients, svc.Metrics, cfg.GHE.Pagination.MaxItems)
 	return prDao
 }
 
-func setupEventHandler(svc *prbot.Service, cfg *prbot.Config, api gh.API) pullrequest.EventHandler {
+func setupEventHandler(svc *prbot.Service, cfg *prbot.Config, api gh.API,
+	opaEvaluator opa.Evaluator) pullrequest.EventHandler {
 	log.Info().Msg("Setting up event handler")
-	opaEvaluator := setUpOPAEvaluator(api, cfg, svc)
 	reviewer := setupReviewer(svc, cfg, api)
 	adapter := input.NewAdapter(api)
 
-	handler := pullrequest.NewEventHandler(opaEvaluator, reviewer, svc.Metrics, adapter)
+	labeler := pullrequest.NewLabeler(api, svc.Metrics)
+	publishers := make([]pullrequest.Publisher, 0)
+	if cfg.GHE.Checks.Enabled {
+		publishers = append(publishers,
+			pullrequest.NewCheckPublisher(api, svc.EvaluationManager, svc.Metrics, cfg.GHE.Checks.Name))
+	}
+	switch cfg.GHE.Comments.Mode {
+	case "summary":
+		publishers = append(publishers,
+			pullrequest.NewSummaryCommentPublisher(api, svc.EvaluationManager, svc.Metrics, cfg.GHE.ServiceAccount))
+	case "errors":
+		publishers = append(publishers, pullrequest.NewErrorCommentPublisher(api))
+	case "", "none":
+	default:
+		log.Error().Msgf("Unknown comments mode %v, expected one of none|errors|summary", cfg.GHE.Comments.Mode)
+		os.Exit(1)
+	}
+
+	handler := pullrequest.NewEventHandler(opaEvaluator, reviewer, labeler, pullrequest.NewPublishers(publishers...),
+		svc.EvaluationManager, svc.Metrics, adapter)
 	return handler
 }
 
@@ -263,8 +367,43 @@ func setupEventFilters(svc *prbot.Service, cfg *prbot.Config, api gh.API) pullre
 	return pullrequest.NewRepoFilter(configstore, api)
 }
 
-func setupGHEClients(sm secrets.Manager, cfg *prbot.Config) (*github.Client, *githubv4.Client) {
-	log.Info().Msg("Setting up GHE clients")
+func setupGHEClients(svc *prbot.Service, cfg *prbot.Config) gh.Clients {
+	a := fmt.Sprintf(APIURL, cfg.GHE.Hostname)
+	u := fmt.Sprintf(UploadURL, cfg.GHE.Hostname)
+	g := fmt.Sprintf(GraphqlURL, cfg.GHE.Hostname)
+
+	if cfg.GHE.App.ID == 0 {
+		return setupGHEPATClients(svc.Secrets, cfg, a, u, g)
+	}
+
+	log.Info().Msgf("Setting up GHE clients for app %d", cfg.GHE.App.ID)
+	pem, err := svc.Secrets.GetSecret(context.Background(), cfg.AWS.Secrets.AppKey)
+	if err != nil {
+		log.Err(err).Msg("Error retrieving github app private key")
+		os.Exit(1)
+	}
+	key, err := gh.ParsePrivateKey(pem)
+	if err != nil {
+		log.Err(err).Msg("Error parsing github app private key")
+		os.Exit(1)
+	}
+	clients, err := gh.NewAppClients(gh.AppConfig{
+		ID:            cfg.GHE.App.ID,
+		PrivateKey:    key,
+		APIURL:        a,
+		UploadURL:     u,
+		GraphqlURL:    g,
+		RefreshBefore: cfg.GHE.App.RefreshBefore,
+	}, clockwork.NewRealClock(), svc.Metrics)
+	if err != nil {
+		log.Err(err).Msg("Error creating github app client")
+		os.Exit(1)
+	}
+	return clients
+}
+
+func setupGHEPATClients(sm secrets.Manager, cfg *prbot.Config, a, u, g string) gh.Clients {
+	log.Info().Msg("Setting up GHE clients with personal access token")
 	tok, err := sm.GetSecret(context.Background(), cfg.AWS.Secrets.Token)
 	if err != nil {
 		log.Err(err).Msg("Error retrieving github token")
@@ -275,15 +414,11 @@ func setupGHEClients(sm secrets.Manager, cfg *prbot.Config) (*github.Client, *gi
 	)
 	httpClient := oauth2.NewClient(context.Background(), ts)
 
-	a := fmt.Sprintf(APIURL, cfg.GHE.Hostname)
-	u := fmt.Sprintf(UploadURL, cfg.GHE.Hostname)
-	g := fmt.Sprintf(GraphqlURL, cfg.GHE.Hostname)
-
 	v3, err := github.NewEnterpriseClient(a, u, httpClient)
 	if err != nil {
 		log.Err(err).Msg("Error creating github client")
 		os.Exit(1)
 	}
 	v4 := githubv4.NewEnterpriseClient(g, httpClient)
-	return v3, v4
+	return gh.NewStaticClients(v3, v4)
 }
@@ -32,6 +32,7 @@ type Config struct {
 		Secrets struct {
 			Webhook string `yaml:"Webhook" env:"WEBHOOK" env-default:"/ci/pr-bot/webhook"`
 			Token   string `yaml:"Token" env:"TOKEN" env-default:"/ci/pr-bot/token"`
+			AppKey  string `yaml:"AppKey" env:"APP_KEY" env-default:"/ci/pr-bot/app-key"`
 		} `yaml:"Secrets" env-prefix:"SECRETS_"`
 	} `yaml:"AWS" env-prefix:"AWS_"`
 	OPA struct {
@@ -43,7 +44,25 @@ type Config struct {
 				Repo     string `yaml:"Repo" env:"REPO"`
 				Tag      string `yaml:"Tag" env:"TAG"`
 			} `yaml:"ECR" env-prefix:"ECR_"`
+			ReadyTimeout time.Duration `yaml:"ReadyTimeout" env:"READY_TIMEOUT" env-default:"1m" env-description:"How long to wait for a bundle to be activated by the OPA SDK"`
+			Watch        struct {
+				Enabled  bool          `yaml:"Enabled" env:"ENABLED" env-default:"false" env-description:"Hot reload the bundle tag set in the OPABundleConfig dynamic config"`
+				Interval time.Duration `yaml:"Interval" env:"INTERVAL" env-default:"1m"`
+				Grace    time.Duration `yaml:"Grace" env:"GRACE" env-default:"5m" env-description:"How long the previous bundle is kept running at most for evaluations in flight after a reload"`
+			} `yaml:"Watch" env-prefix:"WATCH_"`
 		} `yaml:"Bundles" env-prefix:"BUNDLES_"`
+		ModuleModes    bool `yaml:"ModuleModes" env:"MODULE_MODES" env-default:"false" env-description:"Read the mode and failure mode of modules from the OPAModuleConfig dynamic config"`
+		CircuitBreaker struct {
+			Enabled        bool          `yaml:"Enabled" env:"ENABLED" env-default:"false" env-description:"Stop evaluating modules whose error rate crosses the threshold"`
+			Window         int           `yaml:"Window" env:"WINDOW" env-default:"20"`
+			MinEvaluations int           `yaml:"MinEvaluations" env:"MIN_EVALUATIONS" env-default:"10"`
+			Threshold      float64       `yaml:"Threshold" env:"THRESHOLD" env-default:"0.5"`
+			Cooldown       time.Duration `yaml:"Cooldown" env:"COOLDOWN" env-default:"5m"`
+		} `yaml:"CircuitBreaker" env-prefix:"CIRCUIT_BREAKER_"`
+		Evaluation struct {
+			Workers       int           `yaml:"Workers" env:"WORKERS" env-default:"4" env-description:"Number of policy modules evaluated concurrently"`
+			ModuleTimeout time.Duration `yaml:"ModuleTimeout" env:"MODULE_TIMEOUT" env-default:"10s" env-description:"Timeout of evaluating a single policy module, 0 disables the timeout"`
+		} `yaml:"Evaluation" env-prefix:"EVALUATION_"`
 		EvaluationReport struct {
 			TTL       time.Duration `yaml:"TTL" env:"TTL"`
 			TableName string        `yaml:"TableName" env:"TABLE_NAME"`
@@ -57,6 +76,24 @@ type Config struct {
 	GHE struct {
 		ServiceAccount string `yaml:"ServiceAccount" env:"SERVICE_ACCOUNT"`
 		Hostname       string `yaml:"Hostname" env:"HOSTNAME" env-default:"github.com"`
+		App            struct {
+			ID            int64         `yaml:"ID" env:"ID" env-description:"GitHub App ID, personal access token is used when unset"`
+			RefreshBefore time.Duration `yaml:"RefreshBefore" env:"REFRESH_BEFORE" env-default:"10m"`
+		} `yaml:"App" env-prefix:"APP_"`
+		Checks struct {
+			Enabled bool   `yaml:"Enabled" env:"ENABLED" env-default:"false" env-description:"Publish every evaluation as a check run on the head commit, needs a GitHub App"`
+			Name    string `yaml:"Name" env:"NAME" env-default:"pr-bot" env-description:"Name of the check run"`
+		} `yaml:"Checks" env-prefix:"CHECKS_"`
+		Comments struct {
+			Mode string `yaml:"Mode" env:"MODE" env-default:"none" env-description:"Comments posted on PRs; one of none|errors|summary. errors posts a new comment for every failed evaluation, summary keeps a single comment edited on every evaluation"`
+		} `yaml:"Comments" env-prefix:"COMMENTS_"`
+		Pagination struct {
+			MaxItems int `yaml:"MaxItems" env:"MAX_ITEMS" env-default:"3000" env-description:"Cap on the items listed by every paged GitHub API call, 0 lists every item"`
+		} `yaml:"Pagination" env-prefix:"PAGINATION_"`
+		Membership struct {
+			CacheTTL        time.Duration `yaml:"CacheTTL" env:"CACHE_TTL" env-default:"10m" env-description:"How long the org role, teams and repo permission of a user are cached by the membership plugin"`
+			ServiceAccounts []string      `yaml:"ServiceAccounts" env:"SERVICE_ACCOUNTS" env-description:"Logins of the users flagged as service accounts by the membership plugin"`
+		} `yaml:"Membership" env-prefix:"MEMBERSHIP_"`
 	} `yaml:"GHE" env-prefix:"GHE_"`
 	ConfigStore struct {
 		Table   string        `yaml:"Table" env:"TABLE"`
@@ -72,6 +109,19 @@ type Config struct {
 		Table string        `yaml:"Table" env:"TABLE"`
 		TTL   time.Duration `yaml:"TTL" env:"TTL"`
 	} `yaml:"Datastore" env-prefix:"DATASTORE_"`
+	Queue struct {
+		InMemory          bool          `yaml:"InMemory" env:"IN_MEMORY" env-default:"false" env-description:"Use a non durable in memory queue, for local runs"`
+		Table             string        `yaml:"Table" env:"TABLE"`
+		DeadLetterTable   string        `yaml:"DeadLetterTable" env:"DEAD_LETTER_TABLE"`
+		TTL               time.Duration `yaml:"TTL" env:"TTL" env-default:"336h"`
+		VisibilityTimeout time.Duration `yaml:"VisibilityTimeout" env:"VISIBILITY_TIMEOUT" env-default:"5m"`
+		Workers           int           `yaml:"Workers" env:"WORKERS" env-default:"4"`
+		MaxAttempts       int           `yaml:"MaxAttempts" env:"MAX_ATTEMPTS" env-default:"5"`
+		PollInterval      time.Duration `yaml:"PollInterval" env:"POLL_INTERVAL" env-default:"1s"`
+		BaseBackoff       time.Duration `yaml:"BaseBackoff" env:"BASE_BACKOFF" env-default:"10s"`
+		MaxBackoff        time.Duration `yaml:"MaxBackoff" env:"MAX_BACKOFF" env-default:"10m"`
+		Timeout           time.Duration `yaml:"Timeout" env:"TIMEOUT" env-default:"2m"`
+	} `yaml:"Queue" env-prefix:"QUEUE_"`
 	Identity struct {
 		AllowedCallerArns     []string `yaml:"AllowedCallerArns" env:"ALLOWED_CALLER_ARNS"`
 		AllowedCallerAccounts []string `yaml:"AllowedCallerAccounts" env:"ALLOWED_CALLER_ACCOUNTS"`
@@ -10,6 +10,7 @@ import (
 	"github.com/go-chi/httplog"
 	"github.com/go-chi/render"
 	pe "github.com/marqeta/pr-bot/errors"
+	gh "github.com/marqeta/pr-bot/github"
 	"github.com/marqeta/pr-bot/opa/evaluation"
 )
 
@@ -28,6 +29,8 @@ func (c *controller) HandleEvent(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	reqID := middleware.GetReqID(ctx)
 	ctx = evaluation.SetDeliveryID(r.Context(), uuid.NewString())
+	// files changed in the PR are listed once per delivery
+	ctx = gh.WithFilesChanged(ctx)
 	oplog := httplog.LogEntry(ctx)
 
 	callerArn, err := c.verifier.Verify(ctx, r)
@@ -11,8 +11,10 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/marqeta/pr-bot/id"
 	"github.com/marqeta/pr-bot/metrics"
 )
 
@@ -25,9 +27,17 @@ type record struct {
 	CreatedAt int64  `json:"created_at"`
 }
 
+// StoredPayload is a payload stored by a job of a service.
+type StoredPayload struct {
+	Metadata
+	Payload json.RawMessage
+}
+
 //go:generate mockery --name Dao
 type Dao interface {
 	GetPayload(ctx context.Context, m *Metadata) (json.RawMessage, error)
+	// ListPayloads returns the payloads stored for the head and base sha of a PR, by every service and job.
+	ListPayloads(ctx context.Context, pr id.PR, head, base string) ([]StoredPayload, error)
 	StorePayload(ctx context.Context, m *Metadata, payload json.RawMessage) error
 	ToMetadata(ctx context.Context, r *http.Request) (*Metadata, error)
 }
@@ -73,6 +83,47 @@ func (d *dynamo) GetPayload(ctx context.Context, m *Metadata) (json.RawMessage,
 	return []byte(record.Payload), nil
 }
 
+// ListPayloads implements Dao
+func (d *dynamo) ListPayloads(ctx context.Context, pr id.PR, head, base string) ([]StoredPayload, error) {
+	oplog := httplog.LogEntry(ctx)
+	oplog.Info().Interface("pr", pr).Str("head", head).Str("base", base).Msg("listing payloads")
+	payloads := make([]StoredPayload, 0)
+	keyEx := expression.Key("pk").Equal(expression.Value(prKey(pr)))
+	filter := expression.Name("head").Equal(expression.Value(head)).
+		And(expression.Name("base").Equal(expression.Value(base)))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyEx).WithFilter(filter).Build()
+	if err != nil {
+		d.emitError(ctx, "ListPayloads", "MarshalError")
+		return nil, err
+	}
+
+	paginator := dynamodb.NewQueryPaginator(d.client, &dynamodb.QueryInput{
+		TableName:                 &d.table,
+		ConsistentRead:            aws.Bool(true),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		FilterExpression:          expr.Filter(),
+	})
+	for paginator.HasMorePages() {
+		resp, err := paginator.NextPage(ctx)
+		if err != nil {
+			d.emitError(ctx, "ListPayloads", "DDBQueryError")
+			return nil, err
+		}
+		var records []record
+		err = attributevalue.UnmarshalListOfMapsWithOptions(resp.Items, &records, useJSONTagForDecoding)
+		if err != nil {
+			d.emitError(ctx, "ListPayloads", "UnMarshalError")
+			return nil, err
+		}
+		for _, r := range records {
+			payloads = append(payloads, StoredPayload{Metadata: r.Metadata, Payload: []byte(r.Payload)})
+		}
+	}
+	return payloads, nil
+}
+
 // StorePayload implements Dao
 func (d *dynamo) StorePayload(ctx context.Context, m *Metadata, payload json.RawMessage) error {
 	oplog := httplog.LogEntry(ctx)
@@ -133,9 +184,12 @@ func keyAttributeValue(m *Metadata) (map[string]types.AttributeValue, error) {
 }
 
 func keys(m *Metadata) (string, string) {
-	pk := fmt.Sprintf("%s/%s/%d", m.Owner, m.Repo, m.Number)
 	sk := fmt.Sprintf("%s/%s/%s/%s", m.Service, m.Job, m.Head, m.Base)
-	return pk, sk
+	return prKey(m.PR), sk
+}
+
+func prKey(pr id.PR) string {
+	return fmt.Sprintf("%s/%s/%d", pr.Owner, pr.Repo, pr.Number)
 }
 
 func (d dynamo) toRecord(m *Metadata, payload json.RawMessage) *record {
@@ -6,6 +6,8 @@ import (
 	context "context"
 	http "net/http"
 
+	id "github.com/marqeta/pr-bot/id"
+
 	json "encoding/json"
 
 	mock "github.com/stretchr/testify/mock"
@@ -83,6 +85,67 @@ func (_c *MockDao_GetPayload_Call) RunAndReturn(run func(context.Context, *Metad
 	return _c
 }
 
+// ListPayloads provides a mock function with given fields: ctx, pr, head, base
+func (_m *MockDao) ListPayloads(ctx context.Context, pr id.PR, head string, base string) ([]StoredPayload, error) {
+	ret := _m.Called(ctx, pr, head, base)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPayloads")
+	}
+
+	var r0 []StoredPayload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, string, string) ([]StoredPayload, error)); ok {
+		return rf(ctx, pr, head, base)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, string, string) []StoredPayload); ok {
+		r0 = rf(ctx, pr, head, base)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]StoredPayload)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, id.PR, string, string) error); ok {
+		r1 = rf(ctx, pr, head, base)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDao_ListPayloads_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPayloads'
+type MockDao_ListPayloads_Call struct {
+	*mock.Call
+}
+
+// ListPayloads is a helper method to define mock.On call
+//   - ctx context.Context
+//   - pr id.PR
+//   - head string
+//   - base string
+func (_e *MockDao_Expecter) ListPayloads(ctx interface{}, pr interface{}, head interface{}, base interface{}) *MockDao_ListPayloads_Call {
+	return &MockDao_ListPayloads_Call{Call: _e.mock.On("ListPayloads", ctx, pr, head, base)}
+}
+
+func (_c *MockDao_ListPayloads_Call) Run(run func(ctx context.Context, pr id.PR, head string, base string)) *MockDao_ListPayloads_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(id.PR), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockDao_ListPayloads_Call) Return(_a0 []StoredPayload, _a1 error) *MockDao_ListPayloads_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDao_ListPayloads_Call) RunAndReturn(run func(context.Context, id.PR, string, string) ([]StoredPayload, error)) *MockDao_ListPayloads_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // StorePayload provides a mock function with given fields: ctx, m, payload
 func (_m *MockDao) StorePayload(ctx context.Context, m *Metadata, payload json.RawMessage) error {
 	ret := _m.Called(ctx, m, payload)
@@ -29,6 +29,17 @@ More details on PR bot policy evaluation: <a href="%v">link</a>
 </details>
 `
 
+	// AnnotationTemplate is used for reviews with inline comments, the summary is not collapsed
+	// since it lists the comments which could not be placed on the diff.
+	AnnotationTemplate = `
+%v
+
+More details on PR bot policy evaluation: <a href="%v">link</a>
+` + annotationMarker + ` %v -->
+`
+	// annotationMarker tells reviews with inline comments apart from the reviews deciding on the PR.
+	annotationMarker = "<!-- request_id:"
+
 	ErrorTemplate = `
 <details>
 	<summary>:warning: %v :warning: </summary>
@@ -41,21 +52,45 @@ More details on PR bot policy evaluation: <a href="%v">link</a>
 `
 )
 
+// API lists every page of paged API calls up to a cap of items,
+// lists longer than the cap are returned with an error wrapping ErrTruncated.
+//
 //go:generate mockery --name API
 type API interface {
 	ListReviews(ctx context.Context, id id.PR) ([]*github.PullRequestReview, error)
 	AddReview(ctx context.Context, id id.PR, summary, event string) error
+	AddReviewComments(ctx context.Context, id id.PR, summary string, comments []*github.DraftReviewComment) error
+	ListReviewComments(ctx context.Context, id id.PR) ([]*github.PullRequestComment, error)
 	DismissReview(ctx context.Context, id id.PR, reviewID int64, message string) error
 	EnableAutoMerge(ctx context.Context, id id.PR, method githubv4.PullRequestMergeMethod) error
+	DisableAutoMerge(ctx context.Context, id id.PR) error
 	IssueComment(ctx context.Context, id id.PR, comment string) error
 	IssueCommentForError(ctx context.Context, id id.PR, err pe.APIError) error
+	ListIssueComments(ctx context.Context, id id.PR) ([]*github.IssueComment, error)
+	EditIssueComment(ctx context.Context, id id.PR, commentID int64, comment string) error
 	ListAllTopics(ctx context.Context, id id.PR) ([]string, error)
 	ListRequiredStatusChecks(ctx context.Context, id id.PR, branch string) ([]string, error)
 	ListFilesInRootDir(ctx context.Context, id id.PR, branch string) ([]string, error)
+	GetFileContent(ctx context.Context, id id.PR, path, ref string) (string, error)
 	ListFilesChangedInPR(ctx context.Context, id id.PR) ([]*github.CommitFile, error)
+	ListCommits(ctx context.Context, id id.PR) ([]*github.RepositoryCommit, error)
 	GetBranchProtection(ctx context.Context, id id.PR, branch string) (*github.Protection, error)
 	ListNamesOfFilesChangedInPR(ctx context.Context, id id.PR) ([]string, error)
 	GetPullRequest(ctx context.Context, id id.PR) (*github.PullRequest, error)
 	GetRepository(ctx context.Context, id id.PR) (*github.Repository, error)
 	GetOrganization(ctx context.Context, id id.PR) (*github.Organization, error)
+	ListPullRequestsWithCommit(ctx context.Context, id id.PR, sha string) ([]*github.PullRequest, error)
+	ListCheckRunsForRef(ctx context.Context, id id.PR, ref string) ([]*github.CheckRun, error)
+	GetCombinedStatus(ctx context.Context, id id.PR, ref string) (*github.CombinedStatus, error)
+	GetPermissionLevel(ctx context.Context, id id.PR, user string) (string, error)
+	GetOrgRole(ctx context.Context, id id.PR, user string) (string, error)
+	IsCollaborator(ctx context.Context, id id.PR, user string) (bool, error)
+	ListTeamsOfUser(ctx context.Context, id id.PR, user string) ([]string, error)
+	AddLabels(ctx context.Context, id id.PR, labels []string) error
+	RemoveLabel(ctx context.Context, id id.PR, label string) error
+	ListRequestedReviewers(ctx context.Context, id id.PR) (*github.Reviewers, error)
+	RequestReviewers(ctx context.Context, id id.PR, users, teams []string) error
+	CreateCheckRun(ctx context.Context, id id.PR, opts github.CreateCheckRunOptions) error
+	UpdateCheckRun(ctx context.Context, id id.PR, checkRunID int64, opts github.UpdateCheckRunOptions) error
+	UI(id id.PR) string
 }
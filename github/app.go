@@ -0,0 +1,302 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v50/github"
+	"github.com/jonboulle/clockwork"
+	"github.com/marqeta/pr-bot/metrics"
+	"github.com/shurcooL/githubv4"
+)
+
+var ErrInvalidPrivateKey = errors.New("app private key is not a PEM encoded RSA key")
+
+// jwtTTL is the lifetime of app JWTs, GitHub allows at most 10 minutes.
+const jwtTTL = 9 * time.Minute
+
+// Clients returns the v3 and v4 clients to use for the repos of an owner.
+//
+//go:generate mockery --name Clients
+type Clients interface {
+	V3(owner string) *github.Client
+	V4(owner string) *githubv4.Client
+}
+
+type staticClients struct {
+	v3 *github.Client
+	v4 *githubv4.Client
+}
+
+// NewStaticClients returns the same clients for all owners,
+// used when authenticating with a personal access token.
+func NewStaticClients(v3 *github.Client, v4 *githubv4.Client) Clients {
+	return &staticClients{v3: v3, v4: v4}
+}
+
+// V3 implements Clients.
+func (s *staticClients) V3(_ string) *github.Client {
+	return s.v3
+}
+
+// V4 implements Clients.
+func (s *staticClients) V4(_ string) *githubv4.Client {
+	return s.v4
+}
+
+// AppConfig identifies a GitHub App and the GitHub instance it is installed on.
+type AppConfig struct {
+	ID         int64
+	PrivateKey *rsa.PrivateKey
+	APIURL     string
+	UploadURL  string
+	GraphqlURL string
+	// RefreshBefore is how long before expiry installation tokens are refreshed.
+	RefreshBefore time.Duration
+}
+
+type installationToken struct {
+	token     string
+	expiresAt time.Time
+}
+
+// installation of the app on an owner, mu serializes minting tokens for the owner
+// without blocking requests to other owners.
+type installation struct {
+	mu    sync.Mutex
+	id    int64
+	token installationToken
+}
+
+type installationClients struct {
+	v3 *github.Client
+	v4 *githubv4.Client
+}
+
+type appClients struct {
+	cfg     AppConfig
+	app     *github.Client
+	clock   clockwork.Clock
+	metrics metrics.Emitter
+	// mu guards the maps, not the installations in them
+	mu            sync.Mutex
+	installations map[string]*installation
+	clients       map[string]installationClients
+}
+
+// NewAppClients returns clients authenticated as the installation of the app on each owner.
+// Installation tokens are minted on first use, cached and refreshed before they expire.
+func NewAppClients(cfg AppConfig, clock clockwork.Clock, m metrics.Emitter) (Clients, error) {
+	a := &appClients{
+		cfg:           cfg,
+		clock:         clock,
+		metrics:       m,
+		installations: make(map[string]*installation),
+		clients:       make(map[string]installationClients),
+	}
+	app, err := github.NewEnterpriseClient(cfg.APIURL, cfg.UploadURL, &http.Client{
+		Transport: &appTransport{app: a, base: http.DefaultTransport},
+	})
+	if err != nil {
+		return nil, err
+	}
+	a.app = app
+	return a, nil
+}
+
+// V3 implements Clients.
+func (a *appClients) V3(owner string) *github.Client {
+	return a.installationClients(owner).v3
+}
+
+// V4 implements Clients.
+func (a *appClients) V4(owner string) *githubv4.Client {
+	return a.installationClients(owner).v4
+}
+
+func (a *appClients) installationClients(owner string) installationClients {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if c, ok := a.clients[owner]; ok {
+		return c
+	}
+	httpClient := &http.Client{
+		Transport: &installationTransport{app: a, owner: owner, base: http.DefaultTransport},
+	}
+	// URLs are validated when the app client is created
+	v3, _ := github.NewEnterpriseClient(a.cfg.APIURL, a.cfg.UploadURL, httpClient)
+	c := installationClients{
+		v3: v3,
+		v4: githubv4.NewEnterpriseClient(a.cfg.GraphqlURL, httpClient),
+	}
+	a.clients[owner] = c
+	return c
+}
+
+// token returns a cached installation token for the owner,
+// a new token is minted when the cached token is about to expire.
+func (a *appClients) token(ctx context.Context, owner string) (string, error) {
+	inst := a.installation(owner)
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	if inst.token.token != "" && a.clock.Now().Add(a.cfg.RefreshBefore).Before(inst.token.expiresAt) {
+		return inst.token.token, nil
+	}
+
+	tags := []string{fmt.Sprintf("owner:%s", owner)}
+	t, err := a.mint(ctx, owner, inst)
+	if err != nil {
+		a.metrics.EmitDist(ctx, "ghapp.token.error", 1, tags)
+		return "", err
+	}
+	inst.token = t
+	a.metrics.EmitDist(ctx, "ghapp.token.refreshed", 1, tags)
+	return t.token, nil
+}
+
+func (a *appClients) installation(owner string) *installation {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	inst, ok := a.installations[owner]
+	if !ok {
+		inst = &installation{}
+		a.installations[owner] = inst
+	}
+	return inst
+}
+
+// mint creates an installation token, must be called with inst.mu held.
+func (a *appClients) mint(ctx context.Context, owner string, inst *installation) (installationToken, error) {
+	cached := inst.id != 0
+	if !cached {
+		id, err := a.findInstallation(ctx, owner)
+		if err != nil {
+			return installationToken{}, err
+		}
+		inst.id = id
+	}
+	tok, resp, err := a.app.Apps.CreateInstallationToken(ctx, inst.id, nil)
+	if err != nil && resp != nil &&
+		(resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnauthorized) {
+		// the app was uninstalled or reinstalled on the owner, the installation id is resolved again
+		inst.id = 0
+		if cached {
+			return a.mint(ctx, owner, inst)
+		}
+	}
+	if err != nil {
+		return installationToken{}, classifyError(ctx, resp,
+			fmt.Sprintf("error creating installation token for %v", owner), err)
+	}
+	return installationToken{
+		token:     tok.GetToken(),
+		expiresAt: tok.GetExpiresAt().Time,
+	}, nil
+}
+
+func (a *appClients) findInstallation(ctx context.Context, owner string) (int64, error) {
+	installation, resp, err := a.app.Apps.FindOrganizationInstallation(ctx, owner)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		// owner is a user and not an org
+		installation, resp, err = a.app.Apps.FindUserInstallation(ctx, owner)
+	}
+	if err != nil {
+		return 0, classifyError(ctx, resp, fmt.Sprintf("error finding app installation on %v", owner), err)
+	}
+	return installation.GetID(), nil
+}
+
+// jwt returns a JWT signed with the app private key.
+// https://docs.github.com/en/apps/creating-github-apps/authenticating-with-a-github-app/generating-a-json-web-token-jwt-for-a-github-app
+func (a *appClients) jwt() (string, error) {
+	now := a.clock.Now()
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]int64{
+		// allow for clock drift between pr-bot and GitHub
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(jwtTTL).Unix(),
+		"iss": a.cfg.ID,
+	})
+	if err != nil {
+		return "", err
+	}
+	unsigned := fmt.Sprintf("%s.%s",
+		base64.RawURLEncoding.EncodeToString(header),
+		base64.RawURLEncoding.EncodeToString(claims))
+	digest := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, a.cfg.PrivateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s.%s", unsigned, base64.RawURLEncoding.EncodeToString(sig)), nil
+}
+
+// appTransport authenticates requests as the app.
+type appTransport struct {
+	app  *appClients
+	base http.RoundTripper
+}
+
+func (t *appTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	jwt, err := t.app.jwt()
+	if err != nil {
+		return nil, err
+	}
+	r := req.Clone(req.Context())
+	r.Header.Set("Authorization", "Bearer "+jwt)
+	return t.base.RoundTrip(r)
+}
+
+// installationTransport authenticates requests as the installation of the app on the owner.
+type installationTransport struct {
+	app   *appClients
+	owner string
+	base  http.RoundTripper
+}
+
+func (t *installationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.app.token(req.Context(), t.owner)
+	if err != nil {
+		return nil, err
+	}
+	r := req.Clone(req.Context())
+	r.Header.Set("Authorization", "token "+token)
+	return t.base.RoundTrip(r)
+}
+
+// ParsePrivateKey parses the PEM encoded private key of the app.
+func ParsePrivateKey(key string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(strings.TrimSpace(key)))
+	if block == nil {
+		return nil, ErrInvalidPrivateKey
+	}
+	if k, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return k, nil
+	}
+	k, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPrivateKey, err)
+	}
+	rsaKey, ok := k.(*rsa.PrivateKey)
+	if !ok {
+		return nil, ErrInvalidPrivateKey
+	}
+	return rsaKey, nil
+}
@@ -0,0 +1,224 @@
+package github_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	gh "github.com/marqeta/pr-bot/github"
+	"github.com/marqeta/pr-bot/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeGHE struct {
+	t      *testing.T
+	key    *rsa.PrivateKey
+	clock  clockwork.Clock
+	mu     sync.Mutex
+	minted int
+	auth   map[string]string
+	// org1 is the installation id of the app on org1, removed installations return 404
+	org1    int
+	removed map[string]bool
+	// installation lookups of the slow org wait until hang is closed
+	hang chan struct{}
+}
+
+func newFakeGHE(t *testing.T, key *rsa.PrivateKey, clock clockwork.Clock) *fakeGHE {
+	return &fakeGHE{t: t, key: key, clock: clock, auth: make(map[string]string),
+		org1: 1, removed: make(map[string]bool), hang: make(chan struct{})}
+}
+
+func (f *fakeGHE) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/api/v3/orgs/slow/installation" {
+		<-f.hang
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case r.URL.Path == "/api/v3/orgs/org1/installation":
+		f.verifyJWT(r)
+		fmt.Fprintf(w, `{"id": %d}`, f.org1)
+	case r.URL.Path == "/api/v3/orgs/user1/installation":
+		f.verifyJWT(r)
+		w.WriteHeader(http.StatusNotFound)
+	case r.URL.Path == "/api/v3/users/user1/installation":
+		f.verifyJWT(r)
+		fmt.Fprint(w, `{"id": 2}`)
+	case strings.HasPrefix(r.URL.Path, "/api/v3/app/installations/"):
+		f.verifyJWT(r)
+		id := strings.Split(r.URL.Path, "/")[5]
+		if f.removed[id] {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		f.minted++
+		expiresAt := f.clock.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+		fmt.Fprintf(w, `{"token": "token-%s-%d", "expires_at": "%s"}`, id, f.minted, expiresAt)
+	case strings.HasPrefix(r.URL.Path, "/api/v3/repos/"):
+		owner := strings.Split(r.URL.Path, "/")[4]
+		f.auth[owner] = r.Header.Get("Authorization")
+		fmt.Fprint(w, `{}`)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeGHE) verifyJWT(r *http.Request) {
+	jwt := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	parts := strings.Split(jwt, ".")
+	if !assert.Len(f.t, parts, 3) {
+		return
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	assert.Nil(f.t, err)
+	assert.Nil(f.t, rsa.VerifyPKCS1v15(&f.key.PublicKey, crypto.SHA256, digest[:], sig))
+
+	b, err := base64.RawURLEncoding.DecodeString(parts[1])
+	assert.Nil(f.t, err)
+	var claims map[string]int64
+	assert.Nil(f.t, json.Unmarshal(b, &claims))
+	assert.Equal(f.t, int64(123), claims["iss"])
+	assert.True(f.t, claims["exp"] > f.clock.Now().Unix())
+	assert.True(f.t, claims["iat"] <= f.clock.Now().Unix())
+}
+
+func newAppClients(t *testing.T, fake *fakeGHE) gh.Clients {
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	clients, err := gh.NewAppClients(gh.AppConfig{
+		ID:            123,
+		PrivateKey:    fake.key,
+		APIURL:        srv.URL + "/api/v3/",
+		UploadURL:     srv.URL + "/api/uploads/",
+		GraphqlURL:    srv.URL + "/api/graphql",
+		RefreshBefore: 10 * time.Minute,
+	}, fake.clock, metrics.NewNoopEmitter())
+	assert.Nil(t, err)
+	return clients
+}
+
+func TestAppClients(t *testing.T) {
+	ctx := context.Background()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	clock := clockwork.NewFakeClock()
+	fake := newFakeGHE(t, key, clock)
+	clients := newAppClients(t, fake)
+
+	getRepo := func(owner string) string {
+		_, _, err := clients.V3(owner).Repositories.Get(ctx, owner, "repo1")
+		assert.Nil(t, err)
+		return fake.auth[owner]
+	}
+
+	assert.Equal(t, "token token-1-1", getRepo("org1"))
+	assert.Equal(t, "token token-2-2", getRepo("user1"), "users should use their own installation")
+
+	clock.Advance(45 * time.Minute)
+	assert.Equal(t, "token token-1-1", getRepo("org1"), "cached token should be used")
+
+	clock.Advance(6 * time.Minute)
+	assert.Equal(t, "token token-1-3", getRepo("org1"), "token should be refreshed before it expires")
+	assert.Equal(t, 3, fake.minted)
+
+	fake.org1 = 3
+	fake.removed["1"] = true
+	clock.Advance(51 * time.Minute)
+	assert.Equal(t, "token token-3-4", getRepo("org1"), "installation should be resolved again when it is removed")
+}
+
+func TestAppClients_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	fake := newFakeGHE(t, key, clockwork.NewFakeClock())
+	clients := newAppClients(t, fake)
+
+	slow := make(chan error)
+	go func() {
+		_, _, err := clients.V3("slow").Repositories.Get(ctx, "slow", "repo1")
+		slow <- err
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := clients.V3("org1").Repositories.Get(ctx, "org1", "repo1")
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, fake.minted, "concurrent requests to an owner should share the minted token")
+	assert.Equal(t, "token token-1-1", fake.auth["org1"])
+
+	// minting a token for slow does not block other owners
+	close(fake.hang)
+	assert.NotNil(t, <-slow)
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name    string
+		key     string
+		wantErr bool
+	}{
+		{
+			name: "should parse PKCS1 key",
+			key: string(pem.EncodeToMemory(&pem.Block{
+				Type:  "RSA PRIVATE KEY",
+				Bytes: x509.MarshalPKCS1PrivateKey(key),
+			})),
+			wantErr: false,
+		},
+		{
+			name:    "should parse PKCS8 key",
+			key:     string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8})),
+			wantErr: false,
+		},
+		{
+			name:    "should return error when key is not PEM encoded",
+			key:     "random key",
+			wantErr: true,
+		},
+		{
+			name:    "should return error when key is not a private key",
+			key:     string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("random")})),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := gh.ParsePrivateKey(tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParsePrivateKey() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				assert.True(t, key.Equal(got))
+			}
+		})
+	}
+}
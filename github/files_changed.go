@@ -0,0 +1,59 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/go-github/v50/github"
+	"github.com/marqeta/pr-bot/id"
+)
+
+type filesChangedKey struct{}
+
+// filesChanged holds the files changed in the PRs listed while handling a delivery.
+type filesChanged struct {
+	mu    sync.Mutex
+	lists map[string]listedFiles
+}
+
+type listedFiles struct {
+	files []*github.CommitFile
+	// err is nil or wraps ErrTruncated
+	err error
+}
+
+// WithFilesChanged returns a context in which ListFilesChangedInPR lists the files of a PR from GitHub once,
+// so that the plugins and the reviewer handling a delivery share the same list.
+func WithFilesChanged(ctx context.Context) context.Context {
+	return context.WithValue(ctx, filesChangedKey{}, &filesChanged{lists: make(map[string]listedFiles)})
+}
+
+// ListFilesChangedInPR implements API.
+func (gh *githubDao) ListFilesChangedInPR(ctx context.Context, id id.PR) ([]*github.CommitFile, error) {
+	cache, ok := ctx.Value(filesChangedKey{}).(*filesChanged)
+	if !ok {
+		return gh.listFilesChangedInPR(ctx, id)
+	}
+	// concurrent callers wait for the first list instead of listing the files again
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	key := fmt.Sprintf("%s/%s/%d", id.Owner, id.Repo, id.Number)
+	if l, ok := cache.lists[key]; ok {
+		return l.files, l.err
+	}
+	files, err := gh.listFilesChangedInPR(ctx, id)
+	if err != nil && !errors.Is(err, ErrTruncated) {
+		return files, err
+	}
+	cache.lists[key] = listedFiles{files: files, err: err}
+	return files, err
+}
+
+func (gh *githubDao) listFilesChangedInPR(ctx context.Context, id id.PR) ([]*github.CommitFile, error) {
+	opts := &github.ListOptions{}
+	return listPages(ctx, gh, id, "files", opts, func() ([]*github.CommitFile, *github.Response, error) {
+		return gh.clients.V3(id.Owner).PullRequests.ListFiles(ctx, id.Owner, id.Repo, id.Number, opts)
+	})
+}
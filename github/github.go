@@ -2,8 +2,13 @@ package github
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5/middleware"
@@ -14,8 +19,24 @@ import (
 	"github.com/shurcooL/githubv4"
 )
 
+// ErrFileNotFound is returned when a file does not exist in the repo.
+var ErrFileNotFound = errors.New("file not found")
+
 type ApprovalMessage struct {
 	RequestID string `json:"request_id"`
+	// BodyHash is the hash of the summary of the review, so that reviews with the same summary are not posted twice.
+	BodyHash string `json:"body_hash,omitempty"`
+}
+
+// IsAnnotation returns true for the body of reviews posted with inline comments by AddReviewComments.
+func IsAnnotation(body string) bool {
+	return strings.Contains(body, annotationMarker)
+}
+
+// BodyHash returns the hash of the summary of a review.
+func BodyHash(summary string) string {
+	sum := sha256.Sum256([]byte(summary))
+	return hex.EncodeToString(sum[:])
 }
 
 type ErrorMessage struct {
@@ -24,40 +45,35 @@ type ErrorMessage struct {
 }
 
 type githubDao struct {
-	v3         *github.Client
-	v4         *githubv4.Client
+	clients    Clients
 	metrics    metrics.Emitter
 	serverHost string
 	serverPort int
+	maxItems   int
 }
 
-func NewAPI(serverHost string, serverPort int, v3 *github.Client, v4 *githubv4.Client, metrics metrics.Emitter) API {
+// NewAPI returns an API listing at most maxItems items of every list, lists are not capped when maxItems is 0.
+func NewAPI(serverHost string, serverPort int, clients Clients, metrics metrics.Emitter, maxItems int) API {
 	return &githubDao{
-		v3:         v3,
-		v4:         v4,
+		clients:    clients,
 		metrics:    metrics,
 		serverHost: serverHost,
 		serverPort: serverPort,
+		maxItems:   maxItems,
 	}
 }
 
 // ListReviews implements Dao
 func (gh *githubDao) ListReviews(ctx context.Context, id id.PR) ([]*github.PullRequestReview, error) {
-	reviews, resp, err := gh.v3.PullRequests.ListReviews(ctx,
-		id.Owner, id.Repo, id.Number, &github.ListOptions{
-			Page:    1,
-			PerPage: 50,
-		})
-	if err != nil {
-		return nil, err
-	}
-	gh.emitTokenExpiration(ctx, resp)
-	return reviews, nil
+	opts := &github.ListOptions{}
+	return listPages(ctx, gh, id, "reviews", opts, func() ([]*github.PullRequestReview, *github.Response, error) {
+		return gh.clients.V3(id.Owner).PullRequests.ListReviews(ctx, id.Owner, id.Repo, id.Number, opts)
+	})
 }
 
 // GetBranchProtection implements Dao
 func (gh *githubDao) GetBranchProtection(ctx context.Context, id id.PR, branch string) (*github.Protection, error) {
-	b, resp, err := gh.v3.Repositories.GetBranchProtection(ctx, id.Owner, id.Repo, branch)
+	b, resp, err := gh.clients.V3(id.Owner).Repositories.GetBranchProtection(ctx, id.Owner, id.Repo, branch)
 	if err != nil {
 		return nil, classifyError(ctx, resp, fmt.Sprintf("error getting branch protection for PR %v", id.URL), err)
 	}
@@ -65,22 +81,19 @@ func (gh *githubDao) GetBranchProtection(ctx context.Context, id id.PR, branch s
 	return b, nil
 }
 
-// GetPR implements Dao.
-// Only returns first 30 file changes in PR
-// TODO: iterate over pages
-func (gh *githubDao) ListFilesChangedInPR(ctx context.Context, id id.PR) ([]*github.CommitFile, error) {
-	files, resp, err := gh.v3.PullRequests.ListFiles(ctx, id.Owner, id.Repo, id.Number, nil)
-	if err != nil {
-		return nil, err
-	}
-	gh.emitTokenExpiration(ctx, resp)
-	return files, nil
+// ListCommits implements API.
+// GitHub lists at most 250 commits of a PR.
+func (gh *githubDao) ListCommits(ctx context.Context, id id.PR) ([]*github.RepositoryCommit, error) {
+	opts := &github.ListOptions{}
+	return listPages(ctx, gh, id, "commits", opts, func() ([]*github.RepositoryCommit, *github.Response, error) {
+		return gh.clients.V3(id.Owner).PullRequests.ListCommits(ctx, id.Owner, id.Repo, id.Number, opts)
+	})
 }
 
 // ListFilesInRootDir implements Dao.
 func (gh *githubDao) ListFilesInRootDir(ctx context.Context, id id.PR, branch string) ([]string, error) {
 	// empty path == root dir
-	_, files, resp, err := gh.v3.Repositories.GetContents(ctx, id.Owner, id.Repo, "", &github.RepositoryContentGetOptions{
+	_, files, resp, err := gh.clients.V3(id.Owner).Repositories.GetContents(ctx, id.Owner, id.Repo, "", &github.RepositoryContentGetOptions{
 		Ref: branch,
 	})
 	if err != nil {
@@ -97,9 +110,28 @@ func (gh *githubDao) ListFilesInRootDir(ctx context.Context, id id.PR, branch st
 	return filenames, nil
 }
 
+// GetFileContent implements API.
+// returns an error wrapping ErrFileNotFound when path is not a file on ref.
+func (gh *githubDao) GetFileContent(ctx context.Context, id id.PR, path, ref string) (string, error) {
+	file, _, resp, err := gh.clients.V3(id.Owner).Repositories.GetContents(ctx, id.Owner, id.Repo, path,
+		&github.RepositoryContentGetOptions{Ref: ref})
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return "", fmt.Errorf("%v on %v: %w", path, ref, ErrFileNotFound)
+	}
+	if err != nil {
+		return "", classifyError(ctx, resp, fmt.Sprintf("error getting %v on %v for PR %v", path, ref, id.URL), err)
+	}
+	gh.emitTokenExpiration(ctx, resp)
+	if file == nil {
+		// path is a directory
+		return "", fmt.Errorf("%v on %v: %w", path, ref, ErrFileNotFound)
+	}
+	return file.GetContent()
+}
+
 // ListRequiredStatusChecks implements Dao.
 func (gh *githubDao) ListRequiredStatusChecks(ctx context.Context, id id.PR, branch string) ([]string, error) {
-	checks, resp, err := gh.v3.Repositories.ListRequiredStatusChecksContexts(ctx, id.Owner, id.Repo, branch)
+	checks, resp, err := gh.clients.V3(id.Owner).Repositories.ListRequiredStatusChecksContexts(ctx, id.Owner, id.Repo, branch)
 	if err != nil {
 		return nil, classifyError(ctx, resp,
 			fmt.Sprintf("error listing required status checks on repo for PR %v", id.URL), err)
@@ -110,7 +142,7 @@ func (gh *githubDao) ListRequiredStatusChecks(ctx context.Context, id id.PR, bra
 
 // ListAllTopics implements Dao
 func (gh *githubDao) ListAllTopics(ctx context.Context, id id.PR) ([]string, error) {
-	topics, resp, err := gh.v3.Repositories.ListAllTopics(ctx, id.Owner, id.Repo)
+	topics, resp, err := gh.clients.V3(id.Owner).Repositories.ListAllTopics(ctx, id.Owner, id.Repo)
 	if err != nil {
 		return nil, err
 	}
@@ -119,7 +151,6 @@ func (gh *githubDao) ListAllTopics(ctx context.Context, id id.PR) ([]string, err
 }
 
 // ListNamesOfFilesChangedInPR implements Dao
-// TODO iterate over pages
 func (gh *githubDao) ListNamesOfFilesChangedInPR(ctx context.Context, id id.PR) ([]string, error) {
 	var q struct {
 		Repository struct {
@@ -130,7 +161,11 @@ func (gh *githubDao) ListNamesOfFilesChangedInPR(ctx context.Context, id id.PR)
 							Path githubv4.String
 						}
 					}
-				} `graphql:"files(first: 100)"`
+					PageInfo struct {
+						EndCursor   githubv4.String
+						HasNextPage bool
+					}
+				} `graphql:"files(first: 100, after: $cursor)"`
 			} `graphql:"pullRequest(number: $prNumber)"`
 		} `graphql:"repository(owner: $owner, name: $repo)"`
 	}
@@ -140,16 +175,26 @@ func (gh *githubDao) ListNamesOfFilesChangedInPR(ctx context.Context, id id.PR)
 		// TODO handle int32 vs int usage in v3 and v4 version of github lcient
 		//nolint:gosec
 		"prNumber": githubv4.Int(id.Number),
+		"cursor":   (*githubv4.String)(nil),
 	}
-	err := gh.v4.Query(ctx, &q, variables)
-	if err != nil {
-		return nil, err
-	}
-	filepaths := make([]string, 0, len(q.Repository.PullRequest.Files.Edges))
-	for _, edge := range q.Repository.PullRequest.Files.Edges {
-		filepaths = append(filepaths, string(edge.Node.Path))
+	filepaths := make([]string, 0)
+	for {
+		err := gh.clients.V4(id.Owner).Query(ctx, &q, variables)
+		if err != nil {
+			return nil, err
+		}
+		for _, edge := range q.Repository.PullRequest.Files.Edges {
+			filepaths = append(filepaths, string(edge.Node.Path))
+		}
+		files := q.Repository.PullRequest.Files
+		if gh.exceedsCap(len(filepaths), files.PageInfo.HasNextPage) {
+			return filepaths[:gh.maxItems], gh.truncated(ctx, id, "file names")
+		}
+		if !files.PageInfo.HasNextPage {
+			return filepaths, nil
+		}
+		variables["cursor"] = githubv4.NewString(files.PageInfo.EndCursor)
 	}
-	return filepaths, nil
 }
 
 // EnableAutoMerge implements Dao
@@ -165,7 +210,26 @@ func (gh *githubDao) EnableAutoMerge(ctx context.Context, id id.PR, method githu
 		PullRequestID: id.NodeID,
 		MergeMethod:   &method,
 	}
-	err := gh.v4.Mutate(ctx, &mutation, input, nil)
+	err := gh.clients.V4(id.Owner).Mutate(ctx, &mutation, input, nil)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DisableAutoMerge implements API.
+func (gh *githubDao) DisableAutoMerge(ctx context.Context, id id.PR) error {
+	var mutation struct {
+		DisablePullRequestAutoMerge struct {
+			PullRequest struct {
+				Title githubv4.String
+			}
+		} `graphql:"disablePullRequestAutoMerge(input: $input)"`
+	}
+	input := githubv4.DisablePullRequestAutoMergeInput{
+		PullRequestID: id.NodeID,
+	}
+	err := gh.clients.V4(id.Owner).Mutate(ctx, &mutation, input, nil)
 	if err != nil {
 		return err
 	}
@@ -174,7 +238,7 @@ func (gh *githubDao) EnableAutoMerge(ctx context.Context, id id.PR, method githu
 
 // IssueComment implements Dao
 func (gh *githubDao) IssueComment(ctx context.Context, id id.PR, comment string) error {
-	_, resp, err := gh.v3.Issues.CreateComment(ctx, id.Owner, id.Repo, id.Number,
+	_, resp, err := gh.clients.V3(id.Owner).Issues.CreateComment(ctx, id.Owner, id.Repo, id.Number,
 		&github.IssueComment{
 			Body: &comment,
 		})
@@ -185,17 +249,39 @@ func (gh *githubDao) IssueComment(ctx context.Context, id id.PR, comment string)
 	return nil
 }
 
+// ListIssueComments implements API.
+func (gh *githubDao) ListIssueComments(ctx context.Context, id id.PR) ([]*github.IssueComment, error) {
+	opts := &github.IssueListCommentsOptions{}
+	return listPages(ctx, gh, id, "comments", &opts.ListOptions, func() ([]*github.IssueComment, *github.Response, error) {
+		return gh.clients.V3(id.Owner).Issues.ListComments(ctx, id.Owner, id.Repo, id.Number, opts)
+	})
+}
+
+// EditIssueComment implements API.
+func (gh *githubDao) EditIssueComment(ctx context.Context, id id.PR, commentID int64, comment string) error {
+	_, resp, err := gh.clients.V3(id.Owner).Issues.EditComment(ctx, id.Owner, id.Repo, commentID,
+		&github.IssueComment{
+			Body: &comment,
+		})
+	if err != nil {
+		return classifyError(ctx, resp, fmt.Sprintf("error editing comment %d of PR %v", commentID, id.URL), err)
+	}
+	gh.emitTokenExpiration(ctx, resp)
+	return nil
+}
+
 // AddReview implements Dao
 func (gh *githubDao) AddReview(ctx context.Context, id id.PR, summary, event string) error {
 	msg := ApprovalMessage{
 		RequestID: middleware.GetReqID(ctx),
+		BodyHash:  BodyHash(summary),
 	}
 	b, e := json.MarshalIndent(msg, "", "  ")
 	if e != nil {
 		return e
 	}
 	body := fmt.Sprintf(ApprovalTemplate, summary, gh.UI(id), string(b))
-	_, resp, err := gh.v3.PullRequests.CreateReview(ctx, id.Owner, id.Repo, id.Number,
+	_, resp, err := gh.clients.V3(id.Owner).PullRequests.CreateReview(ctx, id.Owner, id.Repo, id.Number,
 		&github.PullRequestReviewRequest{
 			Body:  &body,
 			Event: &event,
@@ -207,9 +293,36 @@ func (gh *githubDao) AddReview(ctx context.Context, id id.PR, summary, event str
 	return nil
 }
 
+// AddReviewComments implements API.
+// posts a single review of type comment with comments on lines of the diff.
+func (gh *githubDao) AddReviewComments(ctx context.Context, id id.PR, summary string,
+	comments []*github.DraftReviewComment) error {
+	body := fmt.Sprintf(AnnotationTemplate, summary, gh.UI(id), middleware.GetReqID(ctx))
+	_, resp, err := gh.clients.V3(id.Owner).PullRequests.CreateReview(ctx, id.Owner, id.Repo, id.Number,
+		&github.PullRequestReviewRequest{
+			Body:     &body,
+			Event:    github.String(Comment),
+			Comments: comments,
+		})
+	if err != nil {
+		return classifyError(ctx, resp, fmt.Sprintf("error adding review comments to PR %v", id.URL), err)
+	}
+	gh.emitTokenExpiration(ctx, resp)
+	return nil
+}
+
+// ListReviewComments implements API.
+func (gh *githubDao) ListReviewComments(ctx context.Context, id id.PR) ([]*github.PullRequestComment, error) {
+	opts := &github.PullRequestListCommentsOptions{}
+	return listPages(ctx, gh, id, "review comments", &opts.ListOptions,
+		func() ([]*github.PullRequestComment, *github.Response, error) {
+			return gh.clients.V3(id.Owner).PullRequests.ListComments(ctx, id.Owner, id.Repo, id.Number, opts)
+		})
+}
+
 // DismissReview implements API
 func (gh *githubDao) DismissReview(ctx context.Context, id id.PR, reviewID int64, message string) error {
-	_, resp, err := gh.v3.PullRequests.DismissReview(ctx, id.Owner, id.Repo, id.Number, reviewID,
+	_, resp, err := gh.clients.V3(id.Owner).PullRequests.DismissReview(ctx, id.Owner, id.Repo, id.Number, reviewID,
 		&github.PullRequestReviewDismissalRequest{
 			Message: &message,
 		})
@@ -234,7 +347,7 @@ func (gh *githubDao) IssueCommentForError(ctx context.Context, id id.PR, apiErro
 }
 
 func (gh *githubDao) GetPullRequest(ctx context.Context, id id.PR) (*github.PullRequest, error) {
-	pr, resp, err := gh.v3.PullRequests.Get(ctx, id.Owner, id.Repo, id.Number)
+	pr, resp, err := gh.clients.V3(id.Owner).PullRequests.Get(ctx, id.Owner, id.Repo, id.Number)
 	if err != nil {
 		return nil, classifyError(ctx, resp, "error getting PR details", err)
 	}
@@ -243,7 +356,7 @@ func (gh *githubDao) GetPullRequest(ctx context.Context, id id.PR) (*github.Pull
 }
 
 func (gh *githubDao) GetRepository(ctx context.Context, id id.PR) (*github.Repository, error) {
-	repo, resp, err := gh.v3.Repositories.Get(ctx, id.Owner, id.Repo)
+	repo, resp, err := gh.clients.V3(id.Owner).Repositories.Get(ctx, id.Owner, id.Repo)
 	if err != nil {
 		return nil, classifyError(ctx, resp, "error getting repo details", err)
 	}
@@ -252,7 +365,7 @@ func (gh *githubDao) GetRepository(ctx context.Context, id id.PR) (*github.Repos
 }
 
 func (gh *githubDao) GetOrganization(ctx context.Context, id id.PR) (*github.Organization, error) {
-	org, resp, err := gh.v3.Organizations.Get(ctx, id.Owner)
+	org, resp, err := gh.clients.V3(id.Owner).Organizations.Get(ctx, id.Owner)
 	if err != nil {
 		return nil, classifyError(ctx, resp, "error getting org details", err)
 	}
@@ -260,8 +373,195 @@ func (gh *githubDao) GetOrganization(ctx context.Context, id id.PR) (*github.Org
 	return org, nil
 }
 
+// ListPullRequestsWithCommit implements API.
+// only id.Owner and id.Repo are used to identify the repo.
+func (gh *githubDao) ListPullRequestsWithCommit(ctx context.Context, id id.PR, sha string) ([]*github.PullRequest, error) {
+	opts := &github.PullRequestListOptions{State: "open"}
+	return listPages(ctx, gh, id, "pull requests", &opts.ListOptions, func() ([]*github.PullRequest, *github.Response, error) {
+		return gh.clients.V3(id.Owner).PullRequests.ListPullRequestsWithCommit(ctx, id.Owner, id.Repo, sha, opts)
+	})
+}
+
+// ListCheckRunsForRef implements API.
+func (gh *githubDao) ListCheckRunsForRef(ctx context.Context, id id.PR, ref string) ([]*github.CheckRun, error) {
+	opts := &github.ListCheckRunsOptions{}
+	return listPages(ctx, gh, id, "check runs", &opts.ListOptions, func() ([]*github.CheckRun, *github.Response, error) {
+		result, resp, err := gh.clients.V3(id.Owner).Checks.ListCheckRunsForRef(ctx, id.Owner, id.Repo, ref, opts)
+		if err != nil {
+			return nil, resp, err
+		}
+		return result.CheckRuns, resp, nil
+	})
+}
+
+// GetCombinedStatus implements API.
+func (gh *githubDao) GetCombinedStatus(ctx context.Context, id id.PR, ref string) (*github.CombinedStatus, error) {
+	status, resp, err := gh.clients.V3(id.Owner).Repositories.GetCombinedStatus(ctx, id.Owner, id.Repo, ref,
+		&github.ListOptions{PerPage: 100})
+	if err != nil {
+		return nil, classifyError(ctx, resp, fmt.Sprintf("error getting combined status for PR %v", id.URL), err)
+	}
+	gh.emitTokenExpiration(ctx, resp)
+	return status, nil
+}
+
+// GetPermissionLevel implements API.
+// returns one of admin, write, read or none.
+func (gh *githubDao) GetPermissionLevel(ctx context.Context, id id.PR, user string) (string, error) {
+	level, resp, err := gh.clients.V3(id.Owner).Repositories.GetPermissionLevel(ctx, id.Owner, id.Repo, user)
+	if err != nil {
+		return "", classifyError(ctx, resp, fmt.Sprintf("error getting permission level of %v", user), err)
+	}
+	gh.emitTokenExpiration(ctx, resp)
+	return level.GetPermission(), nil
+}
+
+// GetOrgRole implements API.
+// returns admin or member, or an empty role when user is not an active member of the org owning the repo.
+func (gh *githubDao) GetOrgRole(ctx context.Context, id id.PR, user string) (string, error) {
+	membership, resp, err := gh.clients.V3(id.Owner).Organizations.GetOrgMembership(ctx, user, id.Owner)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		// user is not a member, or the repo is not owned by an org
+		return "", nil
+	}
+	if err != nil {
+		return "", classifyError(ctx, resp, fmt.Sprintf("error getting org membership of %v in %v", user, id.Owner), err)
+	}
+	gh.emitTokenExpiration(ctx, resp)
+	if membership.GetState() != "active" {
+		return "", nil
+	}
+	return membership.GetRole(), nil
+}
+
+// IsCollaborator implements API.
+// returns true when user is a collaborator of the repo, directly or through the org owning it.
+// read access to public repos does not make a user a collaborator.
+func (gh *githubDao) IsCollaborator(ctx context.Context, id id.PR, user string) (bool, error) {
+	isCollaborator, resp, err := gh.clients.V3(id.Owner).Repositories.IsCollaborator(ctx, id.Owner, id.Repo, user)
+	if err != nil {
+		return false, classifyError(ctx, resp, fmt.Sprintf("error checking if %v is a collaborator", user), err)
+	}
+	gh.emitTokenExpiration(ctx, resp)
+	return isCollaborator, nil
+}
+
+// ListTeamsOfUser implements API.
+// returns the teams of the org owning the repo which user is a member of, as org/team.
+func (gh *githubDao) ListTeamsOfUser(ctx context.Context, id id.PR, user string) ([]string, error) {
+	var q struct {
+		Organization struct {
+			Teams struct {
+				Nodes []struct {
+					Slug githubv4.String
+				}
+				PageInfo struct {
+					EndCursor   githubv4.String
+					HasNextPage bool
+				}
+			} `graphql:"teams(first: 100, after: $cursor, userLogins: [$login])"`
+		} `graphql:"organization(login: $owner)"`
+	}
+	variables := map[string]interface{}{
+		"owner":  githubv4.String(id.Owner),
+		"login":  githubv4.String(user),
+		"cursor": (*githubv4.String)(nil),
+	}
+	teams := make([]string, 0)
+	for {
+		err := gh.clients.V4(id.Owner).Query(ctx, &q, variables)
+		if err != nil {
+			return nil, err
+		}
+		for _, node := range q.Organization.Teams.Nodes {
+			teams = append(teams, fmt.Sprintf("%s/%s", id.Owner, node.Slug))
+		}
+		page := q.Organization.Teams.PageInfo
+		if gh.exceedsCap(len(teams), page.HasNextPage) {
+			return teams[:gh.maxItems], gh.truncated(ctx, id, "teams of user")
+		}
+		if !page.HasNextPage {
+			return teams, nil
+		}
+		variables["cursor"] = githubv4.NewString(page.EndCursor)
+	}
+}
+
+// AddLabels implements API.
+func (gh *githubDao) AddLabels(ctx context.Context, id id.PR, labels []string) error {
+	_, resp, err := gh.clients.V3(id.Owner).Issues.AddLabelsToIssue(ctx, id.Owner, id.Repo, id.Number, labels)
+	if err != nil {
+		return classifyError(ctx, resp, fmt.Sprintf("error adding labels to PR %v", id.URL), err)
+	}
+	gh.emitTokenExpiration(ctx, resp)
+	return nil
+}
+
+// RemoveLabel implements API.
+// labels which are not on the PR are ignored.
+func (gh *githubDao) RemoveLabel(ctx context.Context, id id.PR, label string) error {
+	resp, err := gh.clients.V3(id.Owner).Issues.RemoveLabelForIssue(ctx, id.Owner, id.Repo, id.Number, label)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if err != nil {
+		return classifyError(ctx, resp, fmt.Sprintf("error removing label %v from PR %v", label, id.URL), err)
+	}
+	gh.emitTokenExpiration(ctx, resp)
+	return nil
+}
+
+// ListRequestedReviewers implements API.
+// returns the users and teams whose review is requested and who have not reviewed yet.
+func (gh *githubDao) ListRequestedReviewers(ctx context.Context, id id.PR) (*github.Reviewers, error) {
+	reviewers, resp, err := gh.clients.V3(id.Owner).PullRequests.ListReviewers(ctx, id.Owner, id.Repo, id.Number,
+		&github.ListOptions{PerPage: 100})
+	if err != nil {
+		return nil, classifyError(ctx, resp, fmt.Sprintf("error listing requested reviewers of PR %v", id.URL), err)
+	}
+	gh.emitTokenExpiration(ctx, resp)
+	return reviewers, nil
+}
+
+// RequestReviewers implements API.
+// teams are team slugs in the org of the repo.
+func (gh *githubDao) RequestReviewers(ctx context.Context, id id.PR, users, teams []string) error {
+	_, resp, err := gh.clients.V3(id.Owner).PullRequests.RequestReviewers(ctx, id.Owner, id.Repo, id.Number,
+		github.ReviewersRequest{
+			Reviewers:     users,
+			TeamReviewers: teams,
+		})
+	if err != nil {
+		return classifyError(ctx, resp, fmt.Sprintf("error requesting reviewers of PR %v", id.URL), err)
+	}
+	gh.emitTokenExpiration(ctx, resp)
+	return nil
+}
+
+// CreateCheckRun implements API.
+func (gh *githubDao) CreateCheckRun(ctx context.Context, id id.PR, opts github.CreateCheckRunOptions) error {
+	_, resp, err := gh.clients.V3(id.Owner).Checks.CreateCheckRun(ctx, id.Owner, id.Repo, opts)
+	if err != nil {
+		return classifyError(ctx, resp, fmt.Sprintf("error creating check run for PR %v", id.URL), err)
+	}
+	gh.emitTokenExpiration(ctx, resp)
+	return nil
+}
+
+// UpdateCheckRun implements API.
+func (gh *githubDao) UpdateCheckRun(ctx context.Context, id id.PR, checkRunID int64, opts github.UpdateCheckRunOptions) error {
+	_, resp, err := gh.clients.V3(id.Owner).Checks.UpdateCheckRun(ctx, id.Owner, id.Repo, checkRunID, opts)
+	if err != nil {
+		return classifyError(ctx, resp, fmt.Sprintf("error updating check run %d for PR %v", checkRunID, id.URL), err)
+	}
+	gh.emitTokenExpiration(ctx, resp)
+	return nil
+}
+
+// emitTokenExpiration emits the expiry of personal access tokens,
+// installation tokens of the app are refreshed before they expire.
 func (gh *githubDao) emitTokenExpiration(ctx context.Context, resp *github.Response) {
-	if resp == nil {
+	if resp == nil || resp.TokenExpiration.IsZero() {
 		return
 	}
 	d := time.Until(resp.TokenExpiration.Time)
@@ -269,6 +569,7 @@ func (gh *githubDao) emitTokenExpiration(ctx context.Context, resp *github.Respo
 	gh.metrics.EmitGauge(ctx, "GHETokenExpiry", days, nil)
 }
 
+// UI implements API.
 func (gh *githubDao) UI(id id.PR) string {
 	if gh.serverHost == "localhost" {
 		return fmt.Sprintf("http://%s:%d/ui/eval/%s/pull/%d", gh.serverHost, gh.serverPort, id.RepoFullName, id.Number)
@@ -280,6 +581,12 @@ func classifyError(ctx context.Context, resp *github.Response, msg string, err e
 	if resp == nil {
 		return err
 	}
+	var rle *github.RateLimitError
+	var arle *github.AbuseRateLimitError
+	if errors.As(err, &rle) || errors.As(err, &arle) {
+		// secondary rate limits are answered with 403, the request succeeds once the limit resets
+		return pe.TooManyRequestError(ctx, msg, err)
+	}
 	if isClientError(resp) {
 		return pe.UserError(ctx, msg, err)
 	}
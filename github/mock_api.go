@@ -28,6 +28,54 @@ func (_m *MockAPI) EXPECT() *MockAPI_Expecter {
 	return &MockAPI_Expecter{mock: &_m.Mock}
 }
 
+// AddLabels provides a mock function with given fields: ctx, _a1, labels
+func (_m *MockAPI) AddLabels(ctx context.Context, _a1 id.PR, labels []string) error {
+	ret := _m.Called(ctx, _a1, labels)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddLabels")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, []string) error); ok {
+		r0 = rf(ctx, _a1, labels)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAPI_AddLabels_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddLabels'
+type MockAPI_AddLabels_Call struct {
+	*mock.Call
+}
+
+// AddLabels is a helper method to define mock.On call
+//   - ctx context.Context
+//   - _a1 id.PR
+//   - labels []string
+func (_e *MockAPI_Expecter) AddLabels(ctx interface{}, _a1 interface{}, labels interface{}) *MockAPI_AddLabels_Call {
+	return &MockAPI_AddLabels_Call{Call: _e.mock.On("AddLabels", ctx, _a1, labels)}
+}
+
+func (_c *MockAPI_AddLabels_Call) Run(run func(ctx context.Context, _a1 id.PR, labels []string)) *MockAPI_AddLabels_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(id.PR), args[2].([]string))
+	})
+	return _c
+}
+
+func (_c *MockAPI_AddLabels_Call) Return(_a0 error) *MockAPI_AddLabels_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAPI_AddLabels_Call) RunAndReturn(run func(context.Context, id.PR, []string) error) *MockAPI_AddLabels_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // AddReview provides a mock function with given fields: ctx, _a1, summary, event
 func (_m *MockAPI) AddReview(ctx context.Context, _a1 id.PR, summary string, event string) error {
 	ret := _m.Called(ctx, _a1, summary, event)
@@ -77,6 +125,150 @@ func (_c *MockAPI_AddReview_Call) RunAndReturn(run func(context.Context, id.PR,
 	return _c
 }
 
+// AddReviewComments provides a mock function with given fields: ctx, _a1, summary, comments
+func (_m *MockAPI) AddReviewComments(ctx context.Context, _a1 id.PR, summary string, comments []*v50github.DraftReviewComment) error {
+	ret := _m.Called(ctx, _a1, summary, comments)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddReviewComments")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, string, []*v50github.DraftReviewComment) error); ok {
+		r0 = rf(ctx, _a1, summary, comments)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAPI_AddReviewComments_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddReviewComments'
+type MockAPI_AddReviewComments_Call struct {
+	*mock.Call
+}
+
+// AddReviewComments is a helper method to define mock.On call
+//   - ctx context.Context
+//   - _a1 id.PR
+//   - summary string
+//   - comments []*v50github.DraftReviewComment
+func (_e *MockAPI_Expecter) AddReviewComments(ctx interface{}, _a1 interface{}, summary interface{}, comments interface{}) *MockAPI_AddReviewComments_Call {
+	return &MockAPI_AddReviewComments_Call{Call: _e.mock.On("AddReviewComments", ctx, _a1, summary, comments)}
+}
+
+func (_c *MockAPI_AddReviewComments_Call) Run(run func(ctx context.Context, _a1 id.PR, summary string, comments []*v50github.DraftReviewComment)) *MockAPI_AddReviewComments_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(id.PR), args[2].(string), args[3].([]*v50github.DraftReviewComment))
+	})
+	return _c
+}
+
+func (_c *MockAPI_AddReviewComments_Call) Return(_a0 error) *MockAPI_AddReviewComments_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAPI_AddReviewComments_Call) RunAndReturn(run func(context.Context, id.PR, string, []*v50github.DraftReviewComment) error) *MockAPI_AddReviewComments_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateCheckRun provides a mock function with given fields: ctx, _a1, opts
+func (_m *MockAPI) CreateCheckRun(ctx context.Context, _a1 id.PR, opts v50github.CreateCheckRunOptions) error {
+	ret := _m.Called(ctx, _a1, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateCheckRun")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, v50github.CreateCheckRunOptions) error); ok {
+		r0 = rf(ctx, _a1, opts)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAPI_CreateCheckRun_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateCheckRun'
+type MockAPI_CreateCheckRun_Call struct {
+	*mock.Call
+}
+
+// CreateCheckRun is a helper method to define mock.On call
+//   - ctx context.Context
+//   - _a1 id.PR
+//   - opts v50github.CreateCheckRunOptions
+func (_e *MockAPI_Expecter) CreateCheckRun(ctx interface{}, _a1 interface{}, opts interface{}) *MockAPI_CreateCheckRun_Call {
+	return &MockAPI_CreateCheckRun_Call{Call: _e.mock.On("CreateCheckRun", ctx, _a1, opts)}
+}
+
+func (_c *MockAPI_CreateCheckRun_Call) Run(run func(ctx context.Context, _a1 id.PR, opts v50github.CreateCheckRunOptions)) *MockAPI_CreateCheckRun_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(id.PR), args[2].(v50github.CreateCheckRunOptions))
+	})
+	return _c
+}
+
+func (_c *MockAPI_CreateCheckRun_Call) Return(_a0 error) *MockAPI_CreateCheckRun_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAPI_CreateCheckRun_Call) RunAndReturn(run func(context.Context, id.PR, v50github.CreateCheckRunOptions) error) *MockAPI_CreateCheckRun_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DisableAutoMerge provides a mock function with given fields: ctx, _a1
+func (_m *MockAPI) DisableAutoMerge(ctx context.Context, _a1 id.PR) error {
+	ret := _m.Called(ctx, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DisableAutoMerge")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR) error); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAPI_DisableAutoMerge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DisableAutoMerge'
+type MockAPI_DisableAutoMerge_Call struct {
+	*mock.Call
+}
+
+// DisableAutoMerge is a helper method to define mock.On call
+//   - ctx context.Context
+//   - _a1 id.PR
+func (_e *MockAPI_Expecter) DisableAutoMerge(ctx interface{}, _a1 interface{}) *MockAPI_DisableAutoMerge_Call {
+	return &MockAPI_DisableAutoMerge_Call{Call: _e.mock.On("DisableAutoMerge", ctx, _a1)}
+}
+
+func (_c *MockAPI_DisableAutoMerge_Call) Run(run func(ctx context.Context, _a1 id.PR)) *MockAPI_DisableAutoMerge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(id.PR))
+	})
+	return _c
+}
+
+func (_c *MockAPI_DisableAutoMerge_Call) Return(_a0 error) *MockAPI_DisableAutoMerge_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAPI_DisableAutoMerge_Call) RunAndReturn(run func(context.Context, id.PR) error) *MockAPI_DisableAutoMerge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DismissReview provides a mock function with given fields: ctx, _a1, reviewID, message
 func (_m *MockAPI) DismissReview(ctx context.Context, _a1 id.PR, reviewID int64, message string) error {
 	ret := _m.Called(ctx, _a1, reviewID, message)
@@ -95,6 +287,86 @@ func (_m *MockAPI) DismissReview(ctx context.Context, _a1 id.PR, reviewID int64,
 	return r0
 }
 
+// MockAPI_DismissReview_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DismissReview'
+type MockAPI_DismissReview_Call struct {
+	*mock.Call
+}
+
+// DismissReview is a helper method to define mock.On call
+//   - ctx context.Context
+//   - _a1 id.PR
+//   - reviewID int64
+//   - message string
+func (_e *MockAPI_Expecter) DismissReview(ctx interface{}, _a1 interface{}, reviewID interface{}, message interface{}) *MockAPI_DismissReview_Call {
+	return &MockAPI_DismissReview_Call{Call: _e.mock.On("DismissReview", ctx, _a1, reviewID, message)}
+}
+
+func (_c *MockAPI_DismissReview_Call) Run(run func(ctx context.Context, _a1 id.PR, reviewID int64, message string)) *MockAPI_DismissReview_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(id.PR), args[2].(int64), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockAPI_DismissReview_Call) Return(_a0 error) *MockAPI_DismissReview_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAPI_DismissReview_Call) RunAndReturn(run func(context.Context, id.PR, int64, string) error) *MockAPI_DismissReview_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// EditIssueComment provides a mock function with given fields: ctx, _a1, commentID, comment
+func (_m *MockAPI) EditIssueComment(ctx context.Context, _a1 id.PR, commentID int64, comment string) error {
+	ret := _m.Called(ctx, _a1, commentID, comment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EditIssueComment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, int64, string) error); ok {
+		r0 = rf(ctx, _a1, commentID, comment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAPI_EditIssueComment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EditIssueComment'
+type MockAPI_EditIssueComment_Call struct {
+	*mock.Call
+}
+
+// EditIssueComment is a helper method to define mock.On call
+//   - ctx context.Context
+//   - _a1 id.PR
+//   - commentID int64
+//   - comment string
+func (_e *MockAPI_Expecter) EditIssueComment(ctx interface{}, _a1 interface{}, commentID interface{}, comment interface{}) *MockAPI_EditIssueComment_Call {
+	return &MockAPI_EditIssueComment_Call{Call: _e.mock.On("EditIssueComment", ctx, _a1, commentID, comment)}
+}
+
+func (_c *MockAPI_EditIssueComment_Call) Run(run func(ctx context.Context, _a1 id.PR, commentID int64, comment string)) *MockAPI_EditIssueComment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(id.PR), args[2].(int64), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockAPI_EditIssueComment_Call) Return(_a0 error) *MockAPI_EditIssueComment_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAPI_EditIssueComment_Call) RunAndReturn(run func(context.Context, id.PR, int64, string) error) *MockAPI_EditIssueComment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // EnableAutoMerge provides a mock function with given fields: ctx, _a1, method
 func (_m *MockAPI) EnableAutoMerge(ctx context.Context, _a1 id.PR, method githubv4.PullRequestMergeMethod) error {
 	ret := _m.Called(ctx, _a1, method)
@@ -203,29 +475,29 @@ func (_c *MockAPI_GetBranchProtection_Call) RunAndReturn(run func(context.Contex
 	return _c
 }
 
-// GetOrganization provides a mock function with given fields: ctx, _a1
-func (_m *MockAPI) GetOrganization(ctx context.Context, _a1 id.PR) (*v50github.Organization, error) {
-	ret := _m.Called(ctx, _a1)
+// GetCombinedStatus provides a mock function with given fields: ctx, _a1, ref
+func (_m *MockAPI) GetCombinedStatus(ctx context.Context, _a1 id.PR, ref string) (*v50github.CombinedStatus, error) {
+	ret := _m.Called(ctx, _a1, ref)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetOrganization")
+		panic("no return value specified for GetCombinedStatus")
 	}
 
-	var r0 *v50github.Organization
+	var r0 *v50github.CombinedStatus
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, id.PR) (*v50github.Organization, error)); ok {
-		return rf(ctx, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, string) (*v50github.CombinedStatus, error)); ok {
+		return rf(ctx, _a1, ref)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, id.PR) *v50github.Organization); ok {
-		r0 = rf(ctx, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, string) *v50github.CombinedStatus); ok {
+		r0 = rf(ctx, _a1, ref)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*v50github.Organization)
+			r0 = ret.Get(0).(*v50github.CombinedStatus)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, id.PR) error); ok {
-		r1 = rf(ctx, _a1)
+	if rf, ok := ret.Get(1).(func(context.Context, id.PR, string) error); ok {
+		r1 = rf(ctx, _a1, ref)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -233,58 +505,57 @@ func (_m *MockAPI) GetOrganization(ctx context.Context, _a1 id.PR) (*v50github.O
 	return r0, r1
 }
 
-// MockAPI_GetOrganization_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrganization'
-type MockAPI_GetOrganization_Call struct {
+// MockAPI_GetCombinedStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCombinedStatus'
+type MockAPI_GetCombinedStatus_Call struct {
 	*mock.Call
 }
 
-// GetOrganization is a helper method to define mock.On call
+// GetCombinedStatus is a helper method to define mock.On call
 //   - ctx context.Context
 //   - _a1 id.PR
-func (_e *MockAPI_Expecter) GetOrganization(ctx interface{}, _a1 interface{}) *MockAPI_GetOrganization_Call {
-	return &MockAPI_GetOrganization_Call{Call: _e.mock.On("GetOrganization", ctx, _a1)}
+//   - ref string
+func (_e *MockAPI_Expecter) GetCombinedStatus(ctx interface{}, _a1 interface{}, ref interface{}) *MockAPI_GetCombinedStatus_Call {
+	return &MockAPI_GetCombinedStatus_Call{Call: _e.mock.On("GetCombinedStatus", ctx, _a1, ref)}
 }
 
-func (_c *MockAPI_GetOrganization_Call) Run(run func(ctx context.Context, _a1 id.PR)) *MockAPI_GetOrganization_Call {
+func (_c *MockAPI_GetCombinedStatus_Call) Run(run func(ctx context.Context, _a1 id.PR, ref string)) *MockAPI_GetCombinedStatus_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(id.PR))
+		run(args[0].(context.Context), args[1].(id.PR), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *MockAPI_GetOrganization_Call) Return(_a0 *v50github.Organization, _a1 error) *MockAPI_GetOrganization_Call {
+func (_c *MockAPI_GetCombinedStatus_Call) Return(_a0 *v50github.CombinedStatus, _a1 error) *MockAPI_GetCombinedStatus_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockAPI_GetOrganization_Call) RunAndReturn(run func(context.Context, id.PR) (*v50github.Organization, error)) *MockAPI_GetOrganization_Call {
+func (_c *MockAPI_GetCombinedStatus_Call) RunAndReturn(run func(context.Context, id.PR, string) (*v50github.CombinedStatus, error)) *MockAPI_GetCombinedStatus_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetPullRequest provides a mock function with given fields: ctx, _a1
-func (_m *MockAPI) GetPullRequest(ctx context.Context, _a1 id.PR) (*v50github.PullRequest, error) {
-	ret := _m.Called(ctx, _a1)
+// GetFileContent provides a mock function with given fields: ctx, _a1, path, ref
+func (_m *MockAPI) GetFileContent(ctx context.Context, _a1 id.PR, path string, ref string) (string, error) {
+	ret := _m.Called(ctx, _a1, path, ref)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetPullRequest")
+		panic("no return value specified for GetFileContent")
 	}
 
-	var r0 *v50github.PullRequest
+	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, id.PR) (*v50github.PullRequest, error)); ok {
-		return rf(ctx, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, string, string) (string, error)); ok {
+		return rf(ctx, _a1, path, ref)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, id.PR) *v50github.PullRequest); ok {
-		r0 = rf(ctx, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, string, string) string); ok {
+		r0 = rf(ctx, _a1, path, ref)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*v50github.PullRequest)
-		}
+		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, id.PR) error); ok {
-		r1 = rf(ctx, _a1)
+	if rf, ok := ret.Get(1).(func(context.Context, id.PR, string, string) error); ok {
+		r1 = rf(ctx, _a1, path, ref)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -292,58 +563,58 @@ func (_m *MockAPI) GetPullRequest(ctx context.Context, _a1 id.PR) (*v50github.Pu
 	return r0, r1
 }
 
-// MockAPI_GetPullRequest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPullRequest'
-type MockAPI_GetPullRequest_Call struct {
+// MockAPI_GetFileContent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFileContent'
+type MockAPI_GetFileContent_Call struct {
 	*mock.Call
 }
 
-// GetPullRequest is a helper method to define mock.On call
+// GetFileContent is a helper method to define mock.On call
 //   - ctx context.Context
 //   - _a1 id.PR
-func (_e *MockAPI_Expecter) GetPullRequest(ctx interface{}, _a1 interface{}) *MockAPI_GetPullRequest_Call {
-	return &MockAPI_GetPullRequest_Call{Call: _e.mock.On("GetPullRequest", ctx, _a1)}
+//   - path string
+//   - ref string
+func (_e *MockAPI_Expecter) GetFileContent(ctx interface{}, _a1 interface{}, path interface{}, ref interface{}) *MockAPI_GetFileContent_Call {
+	return &MockAPI_GetFileContent_Call{Call: _e.mock.On("GetFileContent", ctx, _a1, path, ref)}
 }
 
-func (_c *MockAPI_GetPullRequest_Call) Run(run func(ctx context.Context, _a1 id.PR)) *MockAPI_GetPullRequest_Call {
+func (_c *MockAPI_GetFileContent_Call) Run(run func(ctx context.Context, _a1 id.PR, path string, ref string)) *MockAPI_GetFileContent_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(id.PR))
+		run(args[0].(context.Context), args[1].(id.PR), args[2].(string), args[3].(string))
 	})
 	return _c
 }
 
-func (_c *MockAPI_GetPullRequest_Call) Return(_a0 *v50github.PullRequest, _a1 error) *MockAPI_GetPullRequest_Call {
+func (_c *MockAPI_GetFileContent_Call) Return(_a0 string, _a1 error) *MockAPI_GetFileContent_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockAPI_GetPullRequest_Call) RunAndReturn(run func(context.Context, id.PR) (*v50github.PullRequest, error)) *MockAPI_GetPullRequest_Call {
+func (_c *MockAPI_GetFileContent_Call) RunAndReturn(run func(context.Context, id.PR, string, string) (string, error)) *MockAPI_GetFileContent_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetRepository provides a mock function with given fields: ctx, _a1
-func (_m *MockAPI) GetRepository(ctx context.Context, _a1 id.PR) (*v50github.Repository, error) {
-	ret := _m.Called(ctx, _a1)
+// GetOrgRole provides a mock function with given fields: ctx, _a1, user
+func (_m *MockAPI) GetOrgRole(ctx context.Context, _a1 id.PR, user string) (string, error) {
+	ret := _m.Called(ctx, _a1, user)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetRepository")
+		panic("no return value specified for GetOrgRole")
 	}
 
-	var r0 *v50github.Repository
+	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, id.PR) (*v50github.Repository, error)); ok {
-		return rf(ctx, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, string) (string, error)); ok {
+		return rf(ctx, _a1, user)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, id.PR) *v50github.Repository); ok {
-		r0 = rf(ctx, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, string) string); ok {
+		r0 = rf(ctx, _a1, user)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*v50github.Repository)
-		}
+		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, id.PR) error); ok {
-		r1 = rf(ctx, _a1)
+	if rf, ok := ret.Get(1).(func(context.Context, id.PR, string) error); ok {
+		r1 = rf(ctx, _a1, user)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -351,21 +622,257 @@ func (_m *MockAPI) GetRepository(ctx context.Context, _a1 id.PR) (*v50github.Rep
 	return r0, r1
 }
 
-// MockAPI_GetRepository_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRepository'
-type MockAPI_GetRepository_Call struct {
+// MockAPI_GetOrgRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrgRole'
+type MockAPI_GetOrgRole_Call struct {
 	*mock.Call
 }
 
-// GetRepository is a helper method to define mock.On call
+// GetOrgRole is a helper method to define mock.On call
 //   - ctx context.Context
 //   - _a1 id.PR
-func (_e *MockAPI_Expecter) GetRepository(ctx interface{}, _a1 interface{}) *MockAPI_GetRepository_Call {
-	return &MockAPI_GetRepository_Call{Call: _e.mock.On("GetRepository", ctx, _a1)}
+//   - user string
+func (_e *MockAPI_Expecter) GetOrgRole(ctx interface{}, _a1 interface{}, user interface{}) *MockAPI_GetOrgRole_Call {
+	return &MockAPI_GetOrgRole_Call{Call: _e.mock.On("GetOrgRole", ctx, _a1, user)}
 }
 
-func (_c *MockAPI_GetRepository_Call) Run(run func(ctx context.Context, _a1 id.PR)) *MockAPI_GetRepository_Call {
+func (_c *MockAPI_GetOrgRole_Call) Run(run func(ctx context.Context, _a1 id.PR, user string)) *MockAPI_GetOrgRole_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(id.PR))
+		run(args[0].(context.Context), args[1].(id.PR), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockAPI_GetOrgRole_Call) Return(_a0 string, _a1 error) *MockAPI_GetOrgRole_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAPI_GetOrgRole_Call) RunAndReturn(run func(context.Context, id.PR, string) (string, error)) *MockAPI_GetOrgRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOrganization provides a mock function with given fields: ctx, _a1
+func (_m *MockAPI) GetOrganization(ctx context.Context, _a1 id.PR) (*v50github.Organization, error) {
+	ret := _m.Called(ctx, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrganization")
+	}
+
+	var r0 *v50github.Organization
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR) (*v50github.Organization, error)); ok {
+		return rf(ctx, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR) *v50github.Organization); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v50github.Organization)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, id.PR) error); ok {
+		r1 = rf(ctx, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAPI_GetOrganization_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrganization'
+type MockAPI_GetOrganization_Call struct {
+	*mock.Call
+}
+
+// GetOrganization is a helper method to define mock.On call
+//   - ctx context.Context
+//   - _a1 id.PR
+func (_e *MockAPI_Expecter) GetOrganization(ctx interface{}, _a1 interface{}) *MockAPI_GetOrganization_Call {
+	return &MockAPI_GetOrganization_Call{Call: _e.mock.On("GetOrganization", ctx, _a1)}
+}
+
+func (_c *MockAPI_GetOrganization_Call) Run(run func(ctx context.Context, _a1 id.PR)) *MockAPI_GetOrganization_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(id.PR))
+	})
+	return _c
+}
+
+func (_c *MockAPI_GetOrganization_Call) Return(_a0 *v50github.Organization, _a1 error) *MockAPI_GetOrganization_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAPI_GetOrganization_Call) RunAndReturn(run func(context.Context, id.PR) (*v50github.Organization, error)) *MockAPI_GetOrganization_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPermissionLevel provides a mock function with given fields: ctx, _a1, user
+func (_m *MockAPI) GetPermissionLevel(ctx context.Context, _a1 id.PR, user string) (string, error) {
+	ret := _m.Called(ctx, _a1, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPermissionLevel")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, string) (string, error)); ok {
+		return rf(ctx, _a1, user)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, string) string); ok {
+		r0 = rf(ctx, _a1, user)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, id.PR, string) error); ok {
+		r1 = rf(ctx, _a1, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAPI_GetPermissionLevel_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPermissionLevel'
+type MockAPI_GetPermissionLevel_Call struct {
+	*mock.Call
+}
+
+// GetPermissionLevel is a helper method to define mock.On call
+//   - ctx context.Context
+//   - _a1 id.PR
+//   - user string
+func (_e *MockAPI_Expecter) GetPermissionLevel(ctx interface{}, _a1 interface{}, user interface{}) *MockAPI_GetPermissionLevel_Call {
+	return &MockAPI_GetPermissionLevel_Call{Call: _e.mock.On("GetPermissionLevel", ctx, _a1, user)}
+}
+
+func (_c *MockAPI_GetPermissionLevel_Call) Run(run func(ctx context.Context, _a1 id.PR, user string)) *MockAPI_GetPermissionLevel_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(id.PR), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockAPI_GetPermissionLevel_Call) Return(_a0 string, _a1 error) *MockAPI_GetPermissionLevel_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAPI_GetPermissionLevel_Call) RunAndReturn(run func(context.Context, id.PR, string) (string, error)) *MockAPI_GetPermissionLevel_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPullRequest provides a mock function with given fields: ctx, _a1
+func (_m *MockAPI) GetPullRequest(ctx context.Context, _a1 id.PR) (*v50github.PullRequest, error) {
+	ret := _m.Called(ctx, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPullRequest")
+	}
+
+	var r0 *v50github.PullRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR) (*v50github.PullRequest, error)); ok {
+		return rf(ctx, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR) *v50github.PullRequest); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v50github.PullRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, id.PR) error); ok {
+		r1 = rf(ctx, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAPI_GetPullRequest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPullRequest'
+type MockAPI_GetPullRequest_Call struct {
+	*mock.Call
+}
+
+// GetPullRequest is a helper method to define mock.On call
+//   - ctx context.Context
+//   - _a1 id.PR
+func (_e *MockAPI_Expecter) GetPullRequest(ctx interface{}, _a1 interface{}) *MockAPI_GetPullRequest_Call {
+	return &MockAPI_GetPullRequest_Call{Call: _e.mock.On("GetPullRequest", ctx, _a1)}
+}
+
+func (_c *MockAPI_GetPullRequest_Call) Run(run func(ctx context.Context, _a1 id.PR)) *MockAPI_GetPullRequest_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(id.PR))
+	})
+	return _c
+}
+
+func (_c *MockAPI_GetPullRequest_Call) Return(_a0 *v50github.PullRequest, _a1 error) *MockAPI_GetPullRequest_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAPI_GetPullRequest_Call) RunAndReturn(run func(context.Context, id.PR) (*v50github.PullRequest, error)) *MockAPI_GetPullRequest_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRepository provides a mock function with given fields: ctx, _a1
+func (_m *MockAPI) GetRepository(ctx context.Context, _a1 id.PR) (*v50github.Repository, error) {
+	ret := _m.Called(ctx, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRepository")
+	}
+
+	var r0 *v50github.Repository
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR) (*v50github.Repository, error)); ok {
+		return rf(ctx, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR) *v50github.Repository); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v50github.Repository)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, id.PR) error); ok {
+		r1 = rf(ctx, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAPI_GetRepository_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRepository'
+type MockAPI_GetRepository_Call struct {
+	*mock.Call
+}
+
+// GetRepository is a helper method to define mock.On call
+//   - ctx context.Context
+//   - _a1 id.PR
+func (_e *MockAPI_Expecter) GetRepository(ctx interface{}, _a1 interface{}) *MockAPI_GetRepository_Call {
+	return &MockAPI_GetRepository_Call{Call: _e.mock.On("GetRepository", ctx, _a1)}
+}
+
+func (_c *MockAPI_GetRepository_Call) Run(run func(ctx context.Context, _a1 id.PR)) *MockAPI_GetRepository_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(id.PR))
 	})
 	return _c
 }
@@ -380,6 +887,64 @@ func (_c *MockAPI_GetRepository_Call) RunAndReturn(run func(context.Context, id.
 	return _c
 }
 
+// IsCollaborator provides a mock function with given fields: ctx, _a1, user
+func (_m *MockAPI) IsCollaborator(ctx context.Context, _a1 id.PR, user string) (bool, error) {
+	ret := _m.Called(ctx, _a1, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsCollaborator")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, string) (bool, error)); ok {
+		return rf(ctx, _a1, user)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, string) bool); ok {
+		r0 = rf(ctx, _a1, user)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, id.PR, string) error); ok {
+		r1 = rf(ctx, _a1, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAPI_IsCollaborator_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsCollaborator'
+type MockAPI_IsCollaborator_Call struct {
+	*mock.Call
+}
+
+// IsCollaborator is a helper method to define mock.On call
+//   - ctx context.Context
+//   - _a1 id.PR
+//   - user string
+func (_e *MockAPI_Expecter) IsCollaborator(ctx interface{}, _a1 interface{}, user interface{}) *MockAPI_IsCollaborator_Call {
+	return &MockAPI_IsCollaborator_Call{Call: _e.mock.On("IsCollaborator", ctx, _a1, user)}
+}
+
+func (_c *MockAPI_IsCollaborator_Call) Run(run func(ctx context.Context, _a1 id.PR, user string)) *MockAPI_IsCollaborator_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(id.PR), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockAPI_IsCollaborator_Call) Return(_a0 bool, _a1 error) *MockAPI_IsCollaborator_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAPI_IsCollaborator_Call) RunAndReturn(run func(context.Context, id.PR, string) (bool, error)) *MockAPI_IsCollaborator_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // IssueComment provides a mock function with given fields: ctx, _a1, comment
 func (_m *MockAPI) IssueComment(ctx context.Context, _a1 id.PR, comment string) error {
 	ret := _m.Called(ctx, _a1, comment)
@@ -398,102 +963,696 @@ func (_m *MockAPI) IssueComment(ctx context.Context, _a1 id.PR, comment string)
 	return r0
 }
 
-// MockAPI_IssueComment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IssueComment'
-type MockAPI_IssueComment_Call struct {
+// MockAPI_IssueComment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IssueComment'
+type MockAPI_IssueComment_Call struct {
+	*mock.Call
+}
+
+// IssueComment is a helper method to define mock.On call
+//   - ctx context.Context
+//   - _a1 id.PR
+//   - comment string
+func (_e *MockAPI_Expecter) IssueComment(ctx interface{}, _a1 interface{}, comment interface{}) *MockAPI_IssueComment_Call {
+	return &MockAPI_IssueComment_Call{Call: _e.mock.On("IssueComment", ctx, _a1, comment)}
+}
+
+func (_c *MockAPI_IssueComment_Call) Run(run func(ctx context.Context, _a1 id.PR, comment string)) *MockAPI_IssueComment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(id.PR), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockAPI_IssueComment_Call) Return(_a0 error) *MockAPI_IssueComment_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAPI_IssueComment_Call) RunAndReturn(run func(context.Context, id.PR, string) error) *MockAPI_IssueComment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IssueCommentForError provides a mock function with given fields: ctx, _a1, err
+func (_m *MockAPI) IssueCommentForError(ctx context.Context, _a1 id.PR, err errors.APIError) error {
+	ret := _m.Called(ctx, _a1, err)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IssueCommentForError")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, errors.APIError) error); ok {
+		r0 = rf(ctx, _a1, err)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAPI_IssueCommentForError_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IssueCommentForError'
+type MockAPI_IssueCommentForError_Call struct {
+	*mock.Call
+}
+
+// IssueCommentForError is a helper method to define mock.On call
+//   - ctx context.Context
+//   - _a1 id.PR
+//   - err errors.APIError
+func (_e *MockAPI_Expecter) IssueCommentForError(ctx interface{}, _a1 interface{}, err interface{}) *MockAPI_IssueCommentForError_Call {
+	return &MockAPI_IssueCommentForError_Call{Call: _e.mock.On("IssueCommentForError", ctx, _a1, err)}
+}
+
+func (_c *MockAPI_IssueCommentForError_Call) Run(run func(ctx context.Context, _a1 id.PR, err errors.APIError)) *MockAPI_IssueCommentForError_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(id.PR), args[2].(errors.APIError))
+	})
+	return _c
+}
+
+func (_c *MockAPI_IssueCommentForError_Call) Return(_a0 error) *MockAPI_IssueCommentForError_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAPI_IssueCommentForError_Call) RunAndReturn(run func(context.Context, id.PR, errors.APIError) error) *MockAPI_IssueCommentForError_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAllTopics provides a mock function with given fields: ctx, _a1
+func (_m *MockAPI) ListAllTopics(ctx context.Context, _a1 id.PR) ([]string, error) {
+	ret := _m.Called(ctx, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAllTopics")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR) ([]string, error)); ok {
+		return rf(ctx, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR) []string); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, id.PR) error); ok {
+		r1 = rf(ctx, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAPI_ListAllTopics_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAllTopics'
+type MockAPI_ListAllTopics_Call struct {
+	*mock.Call
+}
+
+// ListAllTopics is a helper method to define mock.On call
+//   - ctx context.Context
+//   - _a1 id.PR
+func (_e *MockAPI_Expecter) ListAllTopics(ctx interface{}, _a1 interface{}) *MockAPI_ListAllTopics_Call {
+	return &MockAPI_ListAllTopics_Call{Call: _e.mock.On("ListAllTopics", ctx, _a1)}
+}
+
+func (_c *MockAPI_ListAllTopics_Call) Run(run func(ctx context.Context, _a1 id.PR)) *MockAPI_ListAllTopics_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(id.PR))
+	})
+	return _c
+}
+
+func (_c *MockAPI_ListAllTopics_Call) Return(_a0 []string, _a1 error) *MockAPI_ListAllTopics_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAPI_ListAllTopics_Call) RunAndReturn(run func(context.Context, id.PR) ([]string, error)) *MockAPI_ListAllTopics_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListCheckRunsForRef provides a mock function with given fields: ctx, _a1, ref
+func (_m *MockAPI) ListCheckRunsForRef(ctx context.Context, _a1 id.PR, ref string) ([]*v50github.CheckRun, error) {
+	ret := _m.Called(ctx, _a1, ref)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListCheckRunsForRef")
+	}
+
+	var r0 []*v50github.CheckRun
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, string) ([]*v50github.CheckRun, error)); ok {
+		return rf(ctx, _a1, ref)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, string) []*v50github.CheckRun); ok {
+		r0 = rf(ctx, _a1, ref)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*v50github.CheckRun)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, id.PR, string) error); ok {
+		r1 = rf(ctx, _a1, ref)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAPI_ListCheckRunsForRef_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListCheckRunsForRef'
+type MockAPI_ListCheckRunsForRef_Call struct {
+	*mock.Call
+}
+
+// ListCheckRunsForRef is a helper method to define mock.On call
+//   - ctx context.Context
+//   - _a1 id.PR
+//   - ref string
+func (_e *MockAPI_Expecter) ListCheckRunsForRef(ctx interface{}, _a1 interface{}, ref interface{}) *MockAPI_ListCheckRunsForRef_Call {
+	return &MockAPI_ListCheckRunsForRef_Call{Call: _e.mock.On("ListCheckRunsForRef", ctx, _a1, ref)}
+}
+
+func (_c *MockAPI_ListCheckRunsForRef_Call) Run(run func(ctx context.Context, _a1 id.PR, ref string)) *MockAPI_ListCheckRunsForRef_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(id.PR), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockAPI_ListCheckRunsForRef_Call) Return(_a0 []*v50github.CheckRun, _a1 error) *MockAPI_ListCheckRunsForRef_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAPI_ListCheckRunsForRef_Call) RunAndReturn(run func(context.Context, id.PR, string) ([]*v50github.CheckRun, error)) *MockAPI_ListCheckRunsForRef_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListCommits provides a mock function with given fields: ctx, _a1
+func (_m *MockAPI) ListCommits(ctx context.Context, _a1 id.PR) ([]*v50github.RepositoryCommit, error) {
+	ret := _m.Called(ctx, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListCommits")
+	}
+
+	var r0 []*v50github.RepositoryCommit
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR) ([]*v50github.RepositoryCommit, error)); ok {
+		return rf(ctx, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR) []*v50github.RepositoryCommit); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*v50github.RepositoryCommit)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, id.PR) error); ok {
+		r1 = rf(ctx, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAPI_ListCommits_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListCommits'
+type MockAPI_ListCommits_Call struct {
+	*mock.Call
+}
+
+// ListCommits is a helper method to define mock.On call
+//   - ctx context.Context
+//   - _a1 id.PR
+func (_e *MockAPI_Expecter) ListCommits(ctx interface{}, _a1 interface{}) *MockAPI_ListCommits_Call {
+	return &MockAPI_ListCommits_Call{Call: _e.mock.On("ListCommits", ctx, _a1)}
+}
+
+func (_c *MockAPI_ListCommits_Call) Run(run func(ctx context.Context, _a1 id.PR)) *MockAPI_ListCommits_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(id.PR))
+	})
+	return _c
+}
+
+func (_c *MockAPI_ListCommits_Call) Return(_a0 []*v50github.RepositoryCommit, _a1 error) *MockAPI_ListCommits_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAPI_ListCommits_Call) RunAndReturn(run func(context.Context, id.PR) ([]*v50github.RepositoryCommit, error)) *MockAPI_ListCommits_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListFilesChangedInPR provides a mock function with given fields: ctx, _a1
+func (_m *MockAPI) ListFilesChangedInPR(ctx context.Context, _a1 id.PR) ([]*v50github.CommitFile, error) {
+	ret := _m.Called(ctx, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListFilesChangedInPR")
+	}
+
+	var r0 []*v50github.CommitFile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR) ([]*v50github.CommitFile, error)); ok {
+		return rf(ctx, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR) []*v50github.CommitFile); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*v50github.CommitFile)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, id.PR) error); ok {
+		r1 = rf(ctx, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAPI_ListFilesChangedInPR_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListFilesChangedInPR'
+type MockAPI_ListFilesChangedInPR_Call struct {
+	*mock.Call
+}
+
+// ListFilesChangedInPR is a helper method to define mock.On call
+//   - ctx context.Context
+//   - _a1 id.PR
+func (_e *MockAPI_Expecter) ListFilesChangedInPR(ctx interface{}, _a1 interface{}) *MockAPI_ListFilesChangedInPR_Call {
+	return &MockAPI_ListFilesChangedInPR_Call{Call: _e.mock.On("ListFilesChangedInPR", ctx, _a1)}
+}
+
+func (_c *MockAPI_ListFilesChangedInPR_Call) Run(run func(ctx context.Context, _a1 id.PR)) *MockAPI_ListFilesChangedInPR_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(id.PR))
+	})
+	return _c
+}
+
+func (_c *MockAPI_ListFilesChangedInPR_Call) Return(_a0 []*v50github.CommitFile, _a1 error) *MockAPI_ListFilesChangedInPR_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAPI_ListFilesChangedInPR_Call) RunAndReturn(run func(context.Context, id.PR) ([]*v50github.CommitFile, error)) *MockAPI_ListFilesChangedInPR_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListFilesInRootDir provides a mock function with given fields: ctx, _a1, branch
+func (_m *MockAPI) ListFilesInRootDir(ctx context.Context, _a1 id.PR, branch string) ([]string, error) {
+	ret := _m.Called(ctx, _a1, branch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListFilesInRootDir")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, string) ([]string, error)); ok {
+		return rf(ctx, _a1, branch)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, string) []string); ok {
+		r0 = rf(ctx, _a1, branch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, id.PR, string) error); ok {
+		r1 = rf(ctx, _a1, branch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAPI_ListFilesInRootDir_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListFilesInRootDir'
+type MockAPI_ListFilesInRootDir_Call struct {
+	*mock.Call
+}
+
+// ListFilesInRootDir is a helper method to define mock.On call
+//   - ctx context.Context
+//   - _a1 id.PR
+//   - branch string
+func (_e *MockAPI_Expecter) ListFilesInRootDir(ctx interface{}, _a1 interface{}, branch interface{}) *MockAPI_ListFilesInRootDir_Call {
+	return &MockAPI_ListFilesInRootDir_Call{Call: _e.mock.On("ListFilesInRootDir", ctx, _a1, branch)}
+}
+
+func (_c *MockAPI_ListFilesInRootDir_Call) Run(run func(ctx context.Context, _a1 id.PR, branch string)) *MockAPI_ListFilesInRootDir_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(id.PR), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockAPI_ListFilesInRootDir_Call) Return(_a0 []string, _a1 error) *MockAPI_ListFilesInRootDir_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAPI_ListFilesInRootDir_Call) RunAndReturn(run func(context.Context, id.PR, string) ([]string, error)) *MockAPI_ListFilesInRootDir_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListIssueComments provides a mock function with given fields: ctx, _a1
+func (_m *MockAPI) ListIssueComments(ctx context.Context, _a1 id.PR) ([]*v50github.IssueComment, error) {
+	ret := _m.Called(ctx, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListIssueComments")
+	}
+
+	var r0 []*v50github.IssueComment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR) ([]*v50github.IssueComment, error)); ok {
+		return rf(ctx, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR) []*v50github.IssueComment); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*v50github.IssueComment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, id.PR) error); ok {
+		r1 = rf(ctx, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAPI_ListIssueComments_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListIssueComments'
+type MockAPI_ListIssueComments_Call struct {
+	*mock.Call
+}
+
+// ListIssueComments is a helper method to define mock.On call
+//   - ctx context.Context
+//   - _a1 id.PR
+func (_e *MockAPI_Expecter) ListIssueComments(ctx interface{}, _a1 interface{}) *MockAPI_ListIssueComments_Call {
+	return &MockAPI_ListIssueComments_Call{Call: _e.mock.On("ListIssueComments", ctx, _a1)}
+}
+
+func (_c *MockAPI_ListIssueComments_Call) Run(run func(ctx context.Context, _a1 id.PR)) *MockAPI_ListIssueComments_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(id.PR))
+	})
+	return _c
+}
+
+func (_c *MockAPI_ListIssueComments_Call) Return(_a0 []*v50github.IssueComment, _a1 error) *MockAPI_ListIssueComments_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAPI_ListIssueComments_Call) RunAndReturn(run func(context.Context, id.PR) ([]*v50github.IssueComment, error)) *MockAPI_ListIssueComments_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListNamesOfFilesChangedInPR provides a mock function with given fields: ctx, _a1
+func (_m *MockAPI) ListNamesOfFilesChangedInPR(ctx context.Context, _a1 id.PR) ([]string, error) {
+	ret := _m.Called(ctx, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListNamesOfFilesChangedInPR")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR) ([]string, error)); ok {
+		return rf(ctx, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR) []string); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, id.PR) error); ok {
+		r1 = rf(ctx, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAPI_ListNamesOfFilesChangedInPR_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListNamesOfFilesChangedInPR'
+type MockAPI_ListNamesOfFilesChangedInPR_Call struct {
+	*mock.Call
+}
+
+// ListNamesOfFilesChangedInPR is a helper method to define mock.On call
+//   - ctx context.Context
+//   - _a1 id.PR
+func (_e *MockAPI_Expecter) ListNamesOfFilesChangedInPR(ctx interface{}, _a1 interface{}) *MockAPI_ListNamesOfFilesChangedInPR_Call {
+	return &MockAPI_ListNamesOfFilesChangedInPR_Call{Call: _e.mock.On("ListNamesOfFilesChangedInPR", ctx, _a1)}
+}
+
+func (_c *MockAPI_ListNamesOfFilesChangedInPR_Call) Run(run func(ctx context.Context, _a1 id.PR)) *MockAPI_ListNamesOfFilesChangedInPR_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(id.PR))
+	})
+	return _c
+}
+
+func (_c *MockAPI_ListNamesOfFilesChangedInPR_Call) Return(_a0 []string, _a1 error) *MockAPI_ListNamesOfFilesChangedInPR_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAPI_ListNamesOfFilesChangedInPR_Call) RunAndReturn(run func(context.Context, id.PR) ([]string, error)) *MockAPI_ListNamesOfFilesChangedInPR_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListPullRequestsWithCommit provides a mock function with given fields: ctx, _a1, sha
+func (_m *MockAPI) ListPullRequestsWithCommit(ctx context.Context, _a1 id.PR, sha string) ([]*v50github.PullRequest, error) {
+	ret := _m.Called(ctx, _a1, sha)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPullRequestsWithCommit")
+	}
+
+	var r0 []*v50github.PullRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, string) ([]*v50github.PullRequest, error)); ok {
+		return rf(ctx, _a1, sha)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, string) []*v50github.PullRequest); ok {
+		r0 = rf(ctx, _a1, sha)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*v50github.PullRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, id.PR, string) error); ok {
+		r1 = rf(ctx, _a1, sha)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAPI_ListPullRequestsWithCommit_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPullRequestsWithCommit'
+type MockAPI_ListPullRequestsWithCommit_Call struct {
+	*mock.Call
+}
+
+// ListPullRequestsWithCommit is a helper method to define mock.On call
+//   - ctx context.Context
+//   - _a1 id.PR
+//   - sha string
+func (_e *MockAPI_Expecter) ListPullRequestsWithCommit(ctx interface{}, _a1 interface{}, sha interface{}) *MockAPI_ListPullRequestsWithCommit_Call {
+	return &MockAPI_ListPullRequestsWithCommit_Call{Call: _e.mock.On("ListPullRequestsWithCommit", ctx, _a1, sha)}
+}
+
+func (_c *MockAPI_ListPullRequestsWithCommit_Call) Run(run func(ctx context.Context, _a1 id.PR, sha string)) *MockAPI_ListPullRequestsWithCommit_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(id.PR), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockAPI_ListPullRequestsWithCommit_Call) Return(_a0 []*v50github.PullRequest, _a1 error) *MockAPI_ListPullRequestsWithCommit_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAPI_ListPullRequestsWithCommit_Call) RunAndReturn(run func(context.Context, id.PR, string) ([]*v50github.PullRequest, error)) *MockAPI_ListPullRequestsWithCommit_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListRequestedReviewers provides a mock function with given fields: ctx, _a1
+func (_m *MockAPI) ListRequestedReviewers(ctx context.Context, _a1 id.PR) (*v50github.Reviewers, error) {
+	ret := _m.Called(ctx, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRequestedReviewers")
+	}
+
+	var r0 *v50github.Reviewers
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR) (*v50github.Reviewers, error)); ok {
+		return rf(ctx, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR) *v50github.Reviewers); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v50github.Reviewers)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, id.PR) error); ok {
+		r1 = rf(ctx, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAPI_ListRequestedReviewers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListRequestedReviewers'
+type MockAPI_ListRequestedReviewers_Call struct {
 	*mock.Call
 }
 
-// IssueComment is a helper method to define mock.On call
+// ListRequestedReviewers is a helper method to define mock.On call
 //   - ctx context.Context
 //   - _a1 id.PR
-//   - comment string
-func (_e *MockAPI_Expecter) IssueComment(ctx interface{}, _a1 interface{}, comment interface{}) *MockAPI_IssueComment_Call {
-	return &MockAPI_IssueComment_Call{Call: _e.mock.On("IssueComment", ctx, _a1, comment)}
+func (_e *MockAPI_Expecter) ListRequestedReviewers(ctx interface{}, _a1 interface{}) *MockAPI_ListRequestedReviewers_Call {
+	return &MockAPI_ListRequestedReviewers_Call{Call: _e.mock.On("ListRequestedReviewers", ctx, _a1)}
 }
 
-func (_c *MockAPI_IssueComment_Call) Run(run func(ctx context.Context, _a1 id.PR, comment string)) *MockAPI_IssueComment_Call {
+func (_c *MockAPI_ListRequestedReviewers_Call) Run(run func(ctx context.Context, _a1 id.PR)) *MockAPI_ListRequestedReviewers_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(id.PR), args[2].(string))
+		run(args[0].(context.Context), args[1].(id.PR))
 	})
 	return _c
 }
 
-func (_c *MockAPI_IssueComment_Call) Return(_a0 error) *MockAPI_IssueComment_Call {
-	_c.Call.Return(_a0)
+func (_c *MockAPI_ListRequestedReviewers_Call) Return(_a0 *v50github.Reviewers, _a1 error) *MockAPI_ListRequestedReviewers_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockAPI_IssueComment_Call) RunAndReturn(run func(context.Context, id.PR, string) error) *MockAPI_IssueComment_Call {
+func (_c *MockAPI_ListRequestedReviewers_Call) RunAndReturn(run func(context.Context, id.PR) (*v50github.Reviewers, error)) *MockAPI_ListRequestedReviewers_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// IssueCommentForError provides a mock function with given fields: ctx, _a1, err
-func (_m *MockAPI) IssueCommentForError(ctx context.Context, _a1 id.PR, err errors.APIError) error {
-	ret := _m.Called(ctx, _a1, err)
+// ListRequiredStatusChecks provides a mock function with given fields: ctx, _a1, branch
+func (_m *MockAPI) ListRequiredStatusChecks(ctx context.Context, _a1 id.PR, branch string) ([]string, error) {
+	ret := _m.Called(ctx, _a1, branch)
 
 	if len(ret) == 0 {
-		panic("no return value specified for IssueCommentForError")
+		panic("no return value specified for ListRequiredStatusChecks")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, id.PR, errors.APIError) error); ok {
-		r0 = rf(ctx, _a1, err)
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, string) ([]string, error)); ok {
+		return rf(ctx, _a1, branch)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, string) []string); ok {
+		r0 = rf(ctx, _a1, branch)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, id.PR, string) error); ok {
+		r1 = rf(ctx, _a1, branch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// MockAPI_IssueCommentForError_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IssueCommentForError'
-type MockAPI_IssueCommentForError_Call struct {
+// MockAPI_ListRequiredStatusChecks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListRequiredStatusChecks'
+type MockAPI_ListRequiredStatusChecks_Call struct {
 	*mock.Call
 }
 
-// IssueCommentForError is a helper method to define mock.On call
+// ListRequiredStatusChecks is a helper method to define mock.On call
 //   - ctx context.Context
 //   - _a1 id.PR
-//   - err errors.APIError
-func (_e *MockAPI_Expecter) IssueCommentForError(ctx interface{}, _a1 interface{}, err interface{}) *MockAPI_IssueCommentForError_Call {
-	return &MockAPI_IssueCommentForError_Call{Call: _e.mock.On("IssueCommentForError", ctx, _a1, err)}
+//   - branch string
+func (_e *MockAPI_Expecter) ListRequiredStatusChecks(ctx interface{}, _a1 interface{}, branch interface{}) *MockAPI_ListRequiredStatusChecks_Call {
+	return &MockAPI_ListRequiredStatusChecks_Call{Call: _e.mock.On("ListRequiredStatusChecks", ctx, _a1, branch)}
 }
 
-func (_c *MockAPI_IssueCommentForError_Call) Run(run func(ctx context.Context, _a1 id.PR, err errors.APIError)) *MockAPI_IssueCommentForError_Call {
+func (_c *MockAPI_ListRequiredStatusChecks_Call) Run(run func(ctx context.Context, _a1 id.PR, branch string)) *MockAPI_ListRequiredStatusChecks_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(id.PR), args[2].(errors.APIError))
+		run(args[0].(context.Context), args[1].(id.PR), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *MockAPI_IssueCommentForError_Call) Return(_a0 error) *MockAPI_IssueCommentForError_Call {
-	_c.Call.Return(_a0)
+func (_c *MockAPI_ListRequiredStatusChecks_Call) Return(_a0 []string, _a1 error) *MockAPI_ListRequiredStatusChecks_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockAPI_IssueCommentForError_Call) RunAndReturn(run func(context.Context, id.PR, errors.APIError) error) *MockAPI_IssueCommentForError_Call {
+func (_c *MockAPI_ListRequiredStatusChecks_Call) RunAndReturn(run func(context.Context, id.PR, string) ([]string, error)) *MockAPI_ListRequiredStatusChecks_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListAllTopics provides a mock function with given fields: ctx, _a1
-func (_m *MockAPI) ListAllTopics(ctx context.Context, _a1 id.PR) ([]string, error) {
+// ListReviewComments provides a mock function with given fields: ctx, _a1
+func (_m *MockAPI) ListReviewComments(ctx context.Context, _a1 id.PR) ([]*v50github.PullRequestComment, error) {
 	ret := _m.Called(ctx, _a1)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListAllTopics")
+		panic("no return value specified for ListReviewComments")
 	}
 
-	var r0 []string
+	var r0 []*v50github.PullRequestComment
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, id.PR) ([]string, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR) ([]*v50github.PullRequestComment, error)); ok {
 		return rf(ctx, _a1)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, id.PR) []string); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR) []*v50github.PullRequestComment); ok {
 		r0 = rf(ctx, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]string)
+			r0 = ret.Get(0).([]*v50github.PullRequestComment)
 		}
 	}
 
@@ -506,53 +1665,53 @@ func (_m *MockAPI) ListAllTopics(ctx context.Context, _a1 id.PR) ([]string, erro
 	return r0, r1
 }
 
-// MockAPI_ListAllTopics_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAllTopics'
-type MockAPI_ListAllTopics_Call struct {
+// MockAPI_ListReviewComments_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListReviewComments'
+type MockAPI_ListReviewComments_Call struct {
 	*mock.Call
 }
 
-// ListAllTopics is a helper method to define mock.On call
+// ListReviewComments is a helper method to define mock.On call
 //   - ctx context.Context
 //   - _a1 id.PR
-func (_e *MockAPI_Expecter) ListAllTopics(ctx interface{}, _a1 interface{}) *MockAPI_ListAllTopics_Call {
-	return &MockAPI_ListAllTopics_Call{Call: _e.mock.On("ListAllTopics", ctx, _a1)}
+func (_e *MockAPI_Expecter) ListReviewComments(ctx interface{}, _a1 interface{}) *MockAPI_ListReviewComments_Call {
+	return &MockAPI_ListReviewComments_Call{Call: _e.mock.On("ListReviewComments", ctx, _a1)}
 }
 
-func (_c *MockAPI_ListAllTopics_Call) Run(run func(ctx context.Context, _a1 id.PR)) *MockAPI_ListAllTopics_Call {
+func (_c *MockAPI_ListReviewComments_Call) Run(run func(ctx context.Context, _a1 id.PR)) *MockAPI_ListReviewComments_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(context.Context), args[1].(id.PR))
 	})
 	return _c
 }
 
-func (_c *MockAPI_ListAllTopics_Call) Return(_a0 []string, _a1 error) *MockAPI_ListAllTopics_Call {
+func (_c *MockAPI_ListReviewComments_Call) Return(_a0 []*v50github.PullRequestComment, _a1 error) *MockAPI_ListReviewComments_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockAPI_ListAllTopics_Call) RunAndReturn(run func(context.Context, id.PR) ([]string, error)) *MockAPI_ListAllTopics_Call {
+func (_c *MockAPI_ListReviewComments_Call) RunAndReturn(run func(context.Context, id.PR) ([]*v50github.PullRequestComment, error)) *MockAPI_ListReviewComments_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListFilesChangedInPR provides a mock function with given fields: ctx, _a1
-func (_m *MockAPI) ListFilesChangedInPR(ctx context.Context, _a1 id.PR) ([]*v50github.CommitFile, error) {
+// ListReviews provides a mock function with given fields: ctx, _a1
+func (_m *MockAPI) ListReviews(ctx context.Context, _a1 id.PR) ([]*v50github.PullRequestReview, error) {
 	ret := _m.Called(ctx, _a1)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListFilesChangedInPR")
+		panic("no return value specified for ListReviews")
 	}
 
-	var r0 []*v50github.CommitFile
+	var r0 []*v50github.PullRequestReview
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, id.PR) ([]*v50github.CommitFile, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR) ([]*v50github.PullRequestReview, error)); ok {
 		return rf(ctx, _a1)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, id.PR) []*v50github.CommitFile); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR) []*v50github.PullRequestReview); ok {
 		r0 = rf(ctx, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*v50github.CommitFile)
+			r0 = ret.Get(0).([]*v50github.PullRequestReview)
 		}
 	}
 
@@ -565,50 +1724,50 @@ func (_m *MockAPI) ListFilesChangedInPR(ctx context.Context, _a1 id.PR) ([]*v50g
 	return r0, r1
 }
 
-// MockAPI_ListFilesChangedInPR_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListFilesChangedInPR'
-type MockAPI_ListFilesChangedInPR_Call struct {
+// MockAPI_ListReviews_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListReviews'
+type MockAPI_ListReviews_Call struct {
 	*mock.Call
 }
 
-// ListFilesChangedInPR is a helper method to define mock.On call
+// ListReviews is a helper method to define mock.On call
 //   - ctx context.Context
 //   - _a1 id.PR
-func (_e *MockAPI_Expecter) ListFilesChangedInPR(ctx interface{}, _a1 interface{}) *MockAPI_ListFilesChangedInPR_Call {
-	return &MockAPI_ListFilesChangedInPR_Call{Call: _e.mock.On("ListFilesChangedInPR", ctx, _a1)}
+func (_e *MockAPI_Expecter) ListReviews(ctx interface{}, _a1 interface{}) *MockAPI_ListReviews_Call {
+	return &MockAPI_ListReviews_Call{Call: _e.mock.On("ListReviews", ctx, _a1)}
 }
 
-func (_c *MockAPI_ListFilesChangedInPR_Call) Run(run func(ctx context.Context, _a1 id.PR)) *MockAPI_ListFilesChangedInPR_Call {
+func (_c *MockAPI_ListReviews_Call) Run(run func(ctx context.Context, _a1 id.PR)) *MockAPI_ListReviews_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(context.Context), args[1].(id.PR))
 	})
 	return _c
 }
 
-func (_c *MockAPI_ListFilesChangedInPR_Call) Return(_a0 []*v50github.CommitFile, _a1 error) *MockAPI_ListFilesChangedInPR_Call {
+func (_c *MockAPI_ListReviews_Call) Return(_a0 []*v50github.PullRequestReview, _a1 error) *MockAPI_ListReviews_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockAPI_ListFilesChangedInPR_Call) RunAndReturn(run func(context.Context, id.PR) ([]*v50github.CommitFile, error)) *MockAPI_ListFilesChangedInPR_Call {
+func (_c *MockAPI_ListReviews_Call) RunAndReturn(run func(context.Context, id.PR) ([]*v50github.PullRequestReview, error)) *MockAPI_ListReviews_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListFilesInRootDir provides a mock function with given fields: ctx, _a1, branch
-func (_m *MockAPI) ListFilesInRootDir(ctx context.Context, _a1 id.PR, branch string) ([]string, error) {
-	ret := _m.Called(ctx, _a1, branch)
+// ListTeamsOfUser provides a mock function with given fields: ctx, _a1, user
+func (_m *MockAPI) ListTeamsOfUser(ctx context.Context, _a1 id.PR, user string) ([]string, error) {
+	ret := _m.Called(ctx, _a1, user)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListFilesInRootDir")
+		panic("no return value specified for ListTeamsOfUser")
 	}
 
 	var r0 []string
 	var r1 error
 	if rf, ok := ret.Get(0).(func(context.Context, id.PR, string) ([]string, error)); ok {
-		return rf(ctx, _a1, branch)
+		return rf(ctx, _a1, user)
 	}
 	if rf, ok := ret.Get(0).(func(context.Context, id.PR, string) []string); ok {
-		r0 = rf(ctx, _a1, branch)
+		r0 = rf(ctx, _a1, user)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]string)
@@ -616,7 +1775,7 @@ func (_m *MockAPI) ListFilesInRootDir(ctx context.Context, _a1 id.PR, branch str
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, id.PR, string) error); ok {
-		r1 = rf(ctx, _a1, branch)
+		r1 = rf(ctx, _a1, user)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -624,210 +1783,224 @@ func (_m *MockAPI) ListFilesInRootDir(ctx context.Context, _a1 id.PR, branch str
 	return r0, r1
 }
 
-// MockAPI_ListFilesInRootDir_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListFilesInRootDir'
-type MockAPI_ListFilesInRootDir_Call struct {
+// MockAPI_ListTeamsOfUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTeamsOfUser'
+type MockAPI_ListTeamsOfUser_Call struct {
 	*mock.Call
 }
 
-// ListFilesInRootDir is a helper method to define mock.On call
+// ListTeamsOfUser is a helper method to define mock.On call
 //   - ctx context.Context
 //   - _a1 id.PR
-//   - branch string
-func (_e *MockAPI_Expecter) ListFilesInRootDir(ctx interface{}, _a1 interface{}, branch interface{}) *MockAPI_ListFilesInRootDir_Call {
-	return &MockAPI_ListFilesInRootDir_Call{Call: _e.mock.On("ListFilesInRootDir", ctx, _a1, branch)}
+//   - user string
+func (_e *MockAPI_Expecter) ListTeamsOfUser(ctx interface{}, _a1 interface{}, user interface{}) *MockAPI_ListTeamsOfUser_Call {
+	return &MockAPI_ListTeamsOfUser_Call{Call: _e.mock.On("ListTeamsOfUser", ctx, _a1, user)}
 }
 
-func (_c *MockAPI_ListFilesInRootDir_Call) Run(run func(ctx context.Context, _a1 id.PR, branch string)) *MockAPI_ListFilesInRootDir_Call {
+func (_c *MockAPI_ListTeamsOfUser_Call) Run(run func(ctx context.Context, _a1 id.PR, user string)) *MockAPI_ListTeamsOfUser_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(context.Context), args[1].(id.PR), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *MockAPI_ListFilesInRootDir_Call) Return(_a0 []string, _a1 error) *MockAPI_ListFilesInRootDir_Call {
+func (_c *MockAPI_ListTeamsOfUser_Call) Return(_a0 []string, _a1 error) *MockAPI_ListTeamsOfUser_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockAPI_ListFilesInRootDir_Call) RunAndReturn(run func(context.Context, id.PR, string) ([]string, error)) *MockAPI_ListFilesInRootDir_Call {
+func (_c *MockAPI_ListTeamsOfUser_Call) RunAndReturn(run func(context.Context, id.PR, string) ([]string, error)) *MockAPI_ListTeamsOfUser_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListNamesOfFilesChangedInPR provides a mock function with given fields: ctx, _a1
-func (_m *MockAPI) ListNamesOfFilesChangedInPR(ctx context.Context, _a1 id.PR) ([]string, error) {
-	ret := _m.Called(ctx, _a1)
+// RemoveLabel provides a mock function with given fields: ctx, _a1, label
+func (_m *MockAPI) RemoveLabel(ctx context.Context, _a1 id.PR, label string) error {
+	ret := _m.Called(ctx, _a1, label)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListNamesOfFilesChangedInPR")
-	}
-
-	var r0 []string
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, id.PR) ([]string, error)); ok {
-		return rf(ctx, _a1)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context, id.PR) []string); ok {
-		r0 = rf(ctx, _a1)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]string)
-		}
+		panic("no return value specified for RemoveLabel")
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, id.PR) error); ok {
-		r1 = rf(ctx, _a1)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, string) error); ok {
+		r0 = rf(ctx, _a1, label)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1
+	return r0
 }
 
-// MockAPI_ListNamesOfFilesChangedInPR_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListNamesOfFilesChangedInPR'
-type MockAPI_ListNamesOfFilesChangedInPR_Call struct {
+// MockAPI_RemoveLabel_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RemoveLabel'
+type MockAPI_RemoveLabel_Call struct {
 	*mock.Call
 }
 
-// ListNamesOfFilesChangedInPR is a helper method to define mock.On call
+// RemoveLabel is a helper method to define mock.On call
 //   - ctx context.Context
 //   - _a1 id.PR
-func (_e *MockAPI_Expecter) ListNamesOfFilesChangedInPR(ctx interface{}, _a1 interface{}) *MockAPI_ListNamesOfFilesChangedInPR_Call {
-	return &MockAPI_ListNamesOfFilesChangedInPR_Call{Call: _e.mock.On("ListNamesOfFilesChangedInPR", ctx, _a1)}
+//   - label string
+func (_e *MockAPI_Expecter) RemoveLabel(ctx interface{}, _a1 interface{}, label interface{}) *MockAPI_RemoveLabel_Call {
+	return &MockAPI_RemoveLabel_Call{Call: _e.mock.On("RemoveLabel", ctx, _a1, label)}
 }
 
-func (_c *MockAPI_ListNamesOfFilesChangedInPR_Call) Run(run func(ctx context.Context, _a1 id.PR)) *MockAPI_ListNamesOfFilesChangedInPR_Call {
+func (_c *MockAPI_RemoveLabel_Call) Run(run func(ctx context.Context, _a1 id.PR, label string)) *MockAPI_RemoveLabel_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(id.PR))
+		run(args[0].(context.Context), args[1].(id.PR), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *MockAPI_ListNamesOfFilesChangedInPR_Call) Return(_a0 []string, _a1 error) *MockAPI_ListNamesOfFilesChangedInPR_Call {
-	_c.Call.Return(_a0, _a1)
+func (_c *MockAPI_RemoveLabel_Call) Return(_a0 error) *MockAPI_RemoveLabel_Call {
+	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockAPI_ListNamesOfFilesChangedInPR_Call) RunAndReturn(run func(context.Context, id.PR) ([]string, error)) *MockAPI_ListNamesOfFilesChangedInPR_Call {
+func (_c *MockAPI_RemoveLabel_Call) RunAndReturn(run func(context.Context, id.PR, string) error) *MockAPI_RemoveLabel_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListRequiredStatusChecks provides a mock function with given fields: ctx, _a1, branch
-func (_m *MockAPI) ListRequiredStatusChecks(ctx context.Context, _a1 id.PR, branch string) ([]string, error) {
-	ret := _m.Called(ctx, _a1, branch)
+// RequestReviewers provides a mock function with given fields: ctx, _a1, users, teams
+func (_m *MockAPI) RequestReviewers(ctx context.Context, _a1 id.PR, users []string, teams []string) error {
+	ret := _m.Called(ctx, _a1, users, teams)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListRequiredStatusChecks")
-	}
-
-	var r0 []string
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, id.PR, string) ([]string, error)); ok {
-		return rf(ctx, _a1, branch)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context, id.PR, string) []string); ok {
-		r0 = rf(ctx, _a1, branch)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]string)
-		}
+		panic("no return value specified for RequestReviewers")
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, id.PR, string) error); ok {
-		r1 = rf(ctx, _a1, branch)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, []string, []string) error); ok {
+		r0 = rf(ctx, _a1, users, teams)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1
+	return r0
 }
 
-// MockAPI_ListRequiredStatusChecks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListRequiredStatusChecks'
-type MockAPI_ListRequiredStatusChecks_Call struct {
+// MockAPI_RequestReviewers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RequestReviewers'
+type MockAPI_RequestReviewers_Call struct {
 	*mock.Call
 }
 
-// ListRequiredStatusChecks is a helper method to define mock.On call
+// RequestReviewers is a helper method to define mock.On call
 //   - ctx context.Context
 //   - _a1 id.PR
-//   - branch string
-func (_e *MockAPI_Expecter) ListRequiredStatusChecks(ctx interface{}, _a1 interface{}, branch interface{}) *MockAPI_ListRequiredStatusChecks_Call {
-	return &MockAPI_ListRequiredStatusChecks_Call{Call: _e.mock.On("ListRequiredStatusChecks", ctx, _a1, branch)}
+//   - users []string
+//   - teams []string
+func (_e *MockAPI_Expecter) RequestReviewers(ctx interface{}, _a1 interface{}, users interface{}, teams interface{}) *MockAPI_RequestReviewers_Call {
+	return &MockAPI_RequestReviewers_Call{Call: _e.mock.On("RequestReviewers", ctx, _a1, users, teams)}
 }
 
-func (_c *MockAPI_ListRequiredStatusChecks_Call) Run(run func(ctx context.Context, _a1 id.PR, branch string)) *MockAPI_ListRequiredStatusChecks_Call {
+func (_c *MockAPI_RequestReviewers_Call) Run(run func(ctx context.Context, _a1 id.PR, users []string, teams []string)) *MockAPI_RequestReviewers_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(id.PR), args[2].(string))
+		run(args[0].(context.Context), args[1].(id.PR), args[2].([]string), args[3].([]string))
 	})
 	return _c
 }
 
-func (_c *MockAPI_ListRequiredStatusChecks_Call) Return(_a0 []string, _a1 error) *MockAPI_ListRequiredStatusChecks_Call {
-	_c.Call.Return(_a0, _a1)
+func (_c *MockAPI_RequestReviewers_Call) Return(_a0 error) *MockAPI_RequestReviewers_Call {
+	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockAPI_ListRequiredStatusChecks_Call) RunAndReturn(run func(context.Context, id.PR, string) ([]string, error)) *MockAPI_ListRequiredStatusChecks_Call {
+func (_c *MockAPI_RequestReviewers_Call) RunAndReturn(run func(context.Context, id.PR, []string, []string) error) *MockAPI_RequestReviewers_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListReviews provides a mock function with given fields: ctx, _a1
-func (_m *MockAPI) ListReviews(ctx context.Context, _a1 id.PR) ([]*v50github.PullRequestReview, error) {
-	ret := _m.Called(ctx, _a1)
+// UI provides a mock function with given fields: _a0
+func (_m *MockAPI) UI(_a0 id.PR) string {
+	ret := _m.Called(_a0)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListReviews")
+		panic("no return value specified for UI")
 	}
 
-	var r0 []*v50github.PullRequestReview
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, id.PR) ([]*v50github.PullRequestReview, error)); ok {
-		return rf(ctx, _a1)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context, id.PR) []*v50github.PullRequestReview); ok {
-		r0 = rf(ctx, _a1)
+	var r0 string
+	if rf, ok := ret.Get(0).(func(id.PR) string); ok {
+		r0 = rf(_a0)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*v50github.PullRequestReview)
-		}
+		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, id.PR) error); ok {
-		r1 = rf(ctx, _a1)
+	return r0
+}
+
+// MockAPI_UI_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UI'
+type MockAPI_UI_Call struct {
+	*mock.Call
+}
+
+// UI is a helper method to define mock.On call
+//   - _a0 id.PR
+func (_e *MockAPI_Expecter) UI(_a0 interface{}) *MockAPI_UI_Call {
+	return &MockAPI_UI_Call{Call: _e.mock.On("UI", _a0)}
+}
+
+func (_c *MockAPI_UI_Call) Run(run func(_a0 id.PR)) *MockAPI_UI_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(id.PR))
+	})
+	return _c
+}
+
+func (_c *MockAPI_UI_Call) Return(_a0 string) *MockAPI_UI_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAPI_UI_Call) RunAndReturn(run func(id.PR) string) *MockAPI_UI_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateCheckRun provides a mock function with given fields: ctx, _a1, checkRunID, opts
+func (_m *MockAPI) UpdateCheckRun(ctx context.Context, _a1 id.PR, checkRunID int64, opts v50github.UpdateCheckRunOptions) error {
+	ret := _m.Called(ctx, _a1, checkRunID, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateCheckRun")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, int64, v50github.UpdateCheckRunOptions) error); ok {
+		r0 = rf(ctx, _a1, checkRunID, opts)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1
+	return r0
 }
 
-// MockAPI_ListReviews_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListReviews'
-type MockAPI_ListReviews_Call struct {
+// MockAPI_UpdateCheckRun_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateCheckRun'
+type MockAPI_UpdateCheckRun_Call struct {
 	*mock.Call
 }
 
-// ListReviews is a helper method to define mock.On call
+// UpdateCheckRun is a helper method to define mock.On call
 //   - ctx context.Context
 //   - _a1 id.PR
-func (_e *MockAPI_Expecter) ListReviews(ctx interface{}, _a1 interface{}) *MockAPI_ListReviews_Call {
-	return &MockAPI_ListReviews_Call{Call: _e.mock.On("ListReviews", ctx, _a1)}
+//   - checkRunID int64
+//   - opts v50github.UpdateCheckRunOptions
+func (_e *MockAPI_Expecter) UpdateCheckRun(ctx interface{}, _a1 interface{}, checkRunID interface{}, opts interface{}) *MockAPI_UpdateCheckRun_Call {
+	return &MockAPI_UpdateCheckRun_Call{Call: _e.mock.On("UpdateCheckRun", ctx, _a1, checkRunID, opts)}
 }
 
-func (_c *MockAPI_ListReviews_Call) Run(run func(ctx context.Context, _a1 id.PR)) *MockAPI_ListReviews_Call {
+func (_c *MockAPI_UpdateCheckRun_Call) Run(run func(ctx context.Context, _a1 id.PR, checkRunID int64, opts v50github.UpdateCheckRunOptions)) *MockAPI_UpdateCheckRun_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(id.PR))
+		run(args[0].(context.Context), args[1].(id.PR), args[2].(int64), args[3].(v50github.UpdateCheckRunOptions))
 	})
 	return _c
 }
 
-func (_c *MockAPI_ListReviews_Call) Return(_a0 []*v50github.PullRequestReview, _a1 error) *MockAPI_ListReviews_Call {
-	_c.Call.Return(_a0, _a1)
+func (_c *MockAPI_UpdateCheckRun_Call) Return(_a0 error) *MockAPI_UpdateCheckRun_Call {
+	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockAPI_ListReviews_Call) RunAndReturn(run func(context.Context, id.PR) ([]*v50github.PullRequestReview, error)) *MockAPI_ListReviews_Call {
+func (_c *MockAPI_UpdateCheckRun_Call) RunAndReturn(run func(context.Context, id.PR, int64, v50github.UpdateCheckRunOptions) error) *MockAPI_UpdateCheckRun_Call {
 	_c.Call.Return(run)
 	return _c
 }
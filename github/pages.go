@@ -0,0 +1,56 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/go-github/v50/github"
+	"github.com/marqeta/pr-bot/id"
+)
+
+const (
+	// perPage is the largest page size of the REST and GraphQL APIs.
+	perPage = 100
+)
+
+// ErrTruncated is returned with the items listed so far, when a list is longer than the cap of listed items.
+var ErrTruncated = errors.New("list truncated")
+
+// listPages lists every page of a REST API call, until the last page or the cap of listed items.
+// list is called with opts.Page set to the page to list.
+// returns the items listed so far and an error wrapping ErrTruncated when items are left out.
+func listPages[T any](ctx context.Context, gh *githubDao, id id.PR, op string, opts *github.ListOptions,
+	list func() ([]T, *github.Response, error)) ([]T, error) {
+	opts.PerPage = perPage
+	items := make([]T, 0)
+	for {
+		page, resp, err := list()
+		if err != nil {
+			return nil, classifyError(ctx, resp, fmt.Sprintf("error listing %v of PR %v", op, id.URL), err)
+		}
+		gh.emitTokenExpiration(ctx, resp)
+		items = append(items, page...)
+		if gh.exceedsCap(len(items), resp.NextPage != 0) {
+			return items[:gh.maxItems], gh.truncated(ctx, id, op)
+		}
+		if resp.NextPage == 0 {
+			return items, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// exceedsCap returns true when the cap of listed items is reached and items are left out.
+func (gh *githubDao) exceedsCap(listed int, hasNextPage bool) bool {
+	if gh.maxItems <= 0 {
+		return false
+	}
+	return listed > gh.maxItems || (listed == gh.maxItems && hasNextPage)
+}
+
+func (gh *githubDao) truncated(ctx context.Context, id id.PR, op string) error {
+	tags := append(id.ToTags(), fmt.Sprintf("op:%s", op))
+	gh.metrics.EmitDist(ctx, "githubListTruncated", 1.0, tags)
+	return fmt.Errorf("%v of PR %v after %d items: %w", op, id.URL, gh.maxItems, ErrTruncated)
+}
@@ -0,0 +1,230 @@
+package github_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-github/v50/github"
+	pe "github.com/marqeta/pr-bot/errors"
+	gh "github.com/marqeta/pr-bot/github"
+	"github.com/marqeta/pr-bot/id"
+	"github.com/marqeta/pr-bot/metrics"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+)
+
+// pagedGHE serves n reviews and n files of PR owner1/repo1#1,
+// and n PRs with commit sha1 of owner1/repo1, in pages of 100.
+// reviews of PR owner1/repo1#2 hit the secondary rate limit.
+type pagedGHE struct {
+	n int
+	// filePages is the number of pages of files served
+	filePages atomic.Int32
+}
+
+func (p *pagedGHE) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/repos/owner1/repo1/pulls/1/files":
+		p.filePages.Add(1)
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		page = max(page, 1)
+		files := make([]*github.CommitFile, 0)
+		for i := (page-1)*100 + 1; i <= min(page*100, p.n); i++ {
+			files = append(files, &github.CommitFile{Filename: github.String(fmt.Sprintf("file-%d", i))})
+		}
+		if page*100 < p.n {
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s%s?page=%d>; rel="next"`, r.Host, r.URL.Path, page+1))
+		}
+		_ = json.NewEncoder(w).Encode(files)
+	case "/repos/owner1/repo1/commits/sha1/pulls":
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		page = max(page, 1)
+		prs := make([]*github.PullRequest, 0)
+		for i := (page-1)*100 + 1; i <= min(page*100, p.n); i++ {
+			prs = append(prs, &github.PullRequest{Number: github.Int(i)})
+		}
+		if page*100 < p.n {
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s%s?page=%d>; rel="next"`, r.Host, r.URL.Path, page+1))
+		}
+		_ = json.NewEncoder(w).Encode(prs)
+	case "/repos/owner1/repo1/pulls/2/reviews":
+		w.WriteHeader(http.StatusForbidden)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"message":           "You have exceeded a secondary rate limit.",
+			"documentation_url": "https://docs.github.com/rest/overview/resources-in-the-rest-api#secondary-rate-limits",
+		})
+	case "/repos/owner1/repo1/pulls/1/reviews":
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		page = max(page, 1)
+		reviews := make([]*github.PullRequestReview, 0)
+		for i := (page-1)*100 + 1; i <= min(page*100, p.n); i++ {
+			reviews = append(reviews, &github.PullRequestReview{ID: github.Int64(int64(i))})
+		}
+		if page*100 < p.n {
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s%s?page=%d>; rel="next"`, r.Host, r.URL.Path, page+1))
+		}
+		_ = json.NewEncoder(w).Encode(reviews)
+	case "/graphql":
+		var body struct {
+			Variables struct {
+				Cursor *string `json:"cursor"`
+			} `json:"variables"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		start := 0
+		if body.Variables.Cursor != nil {
+			start, _ = strconv.Atoi(*body.Variables.Cursor)
+		}
+		edges := make([]map[string]any, 0)
+		for i := start; i < min(start+100, p.n); i++ {
+			edges = append(edges, map[string]any{"node": map[string]any{"path": fmt.Sprintf("file-%d", i)}})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{"repository": map[string]any{"pullRequest": map[string]any{"files": map[string]any{
+				"edges":    edges,
+				"pageInfo": map[string]any{"endCursor": strconv.Itoa(start + 100), "hasNextPage": start+100 < p.n},
+			}}}},
+		})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func pagedAPI(t *testing.T, n, maxItems int) gh.API {
+	api, _ := pagedAPIWithServer(t, n, maxItems)
+	return api
+}
+
+func pagedAPIWithServer(t *testing.T, n, maxItems int) (gh.API, *pagedGHE) {
+	ghe := &pagedGHE{n: n}
+	srv := httptest.NewServer(ghe)
+	t.Cleanup(srv.Close)
+	v3 := github.NewClient(nil)
+	v3.BaseURL, _ = url.Parse(srv.URL + "/")
+	clients := gh.NewMockClients(t)
+	clients.EXPECT().V3("owner1").Return(v3).Maybe()
+	clients.EXPECT().V4("owner1").Return(githubv4.NewEnterpriseClient(srv.URL+"/graphql", nil)).Maybe()
+	return gh.NewAPI("localhost", 8080, clients, metrics.NewNoopEmitter(), maxItems), ghe
+}
+
+func TestGithubDao_ListReviews_Pages(t *testing.T) {
+	ctx := context.TODO()
+	pr := id.PR{Owner: "owner1", Repo: "repo1", Number: 1}
+	tests := []struct {
+		name          string
+		n             int
+		maxItems      int
+		want          int
+		wantTruncated bool
+	}{
+		{name: "Should list every page", n: 250, maxItems: 3000, want: 250},
+		{name: "Should list every page when lists are not capped", n: 250, maxItems: 0, want: 250},
+		{name: "Should not truncate list as long as the cap", n: 200, maxItems: 200, want: 200},
+		{name: "Should stop at the cap", n: 250, maxItems: 150, want: 150, wantTruncated: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reviews, err := pagedAPI(t, tt.n, tt.maxItems).ListReviews(ctx, pr)
+			if tt.wantTruncated {
+				assert.ErrorIs(t, err, gh.ErrTruncated)
+			} else {
+				assert.Nil(t, err)
+			}
+			assert.Len(t, reviews, tt.want)
+			assert.Equal(t, int64(tt.want), reviews[len(reviews)-1].GetID())
+		})
+	}
+}
+
+func TestGithubDao_ListPullRequestsWithCommit_Pages(t *testing.T) {
+	ctx := context.TODO()
+	repo := id.PR{Owner: "owner1", Repo: "repo1"}
+
+	prs, err := pagedAPI(t, 250, 3000).ListPullRequestsWithCommit(ctx, repo, "sha1")
+	assert.Nil(t, err)
+	assert.Len(t, prs, 250)
+	assert.Equal(t, 250, prs[249].GetNumber())
+
+	prs, err = pagedAPI(t, 250, 120).ListPullRequestsWithCommit(ctx, repo, "sha1")
+	assert.ErrorIs(t, err, gh.ErrTruncated)
+	assert.Len(t, prs, 120)
+}
+
+func TestGithubDao_ListNamesOfFilesChangedInPR_Pages(t *testing.T) {
+	ctx := context.TODO()
+	pr := id.PR{Owner: "owner1", Repo: "repo1", Number: 1}
+
+	files, err := pagedAPI(t, 250, 3000).ListNamesOfFilesChangedInPR(ctx, pr)
+	assert.Nil(t, err)
+	assert.Len(t, files, 250)
+	assert.Equal(t, "file-249", files[249])
+
+	files, err = pagedAPI(t, 250, 120).ListNamesOfFilesChangedInPR(ctx, pr)
+	assert.ErrorIs(t, err, gh.ErrTruncated)
+	assert.Len(t, files, 120)
+}
+
+func TestGithubDao_ListFilesChangedInPR_Shared(t *testing.T) {
+	pr := id.PR{Owner: "owner1", Repo: "repo1", Number: 1}
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		maxItems  int
+		wantErr   error
+		wantFiles int
+		wantPages int32
+	}{
+		{
+			name:      "Should list files once per delivery",
+			ctx:       gh.WithFilesChanged(context.TODO()),
+			maxItems:  3000,
+			wantFiles: 250,
+			wantPages: 3,
+		},
+		{
+			name:      "Should share truncated list within a delivery",
+			ctx:       gh.WithFilesChanged(context.TODO()),
+			maxItems:  120,
+			wantErr:   gh.ErrTruncated,
+			wantFiles: 120,
+			wantPages: 2,
+		},
+		{
+			name:      "Should list files on every call outside a delivery",
+			ctx:       context.TODO(),
+			maxItems:  3000,
+			wantFiles: 250,
+			wantPages: 6,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api, ghe := pagedAPIWithServer(t, 250, tt.maxItems)
+			for i := 0; i < 2; i++ {
+				files, err := api.ListFilesChangedInPR(tt.ctx, pr)
+				if tt.wantErr != nil {
+					assert.ErrorIs(t, err, tt.wantErr)
+				} else {
+					assert.Nil(t, err)
+				}
+				assert.Len(t, files, tt.wantFiles)
+			}
+			assert.Equal(t, tt.wantPages, ghe.filePages.Load())
+		})
+	}
+}
+
+func TestGithubDao_ListReviews_SecondaryRateLimit(t *testing.T) {
+	pr := id.PR{Owner: "owner1", Repo: "repo1", Number: 2}
+	_, err := pagedAPI(t, 1, 3000).ListReviews(context.TODO(), pr)
+	var ae pe.APIError
+	assert.ErrorAs(t, err, &ae)
+	assert.Equal(t, http.StatusTooManyRequests, ae.StatusCode)
+}
@@ -13,11 +13,18 @@ type PR struct {
 	RepoFullName string `json:"repo_full_name,omitempty"`
 	Author       string `json:"author,omitempty"`
 	URL          string `json:"url,omitempty"`
+	// HeadSHA is the head commit of the PR when the event was received.
+	HeadSHA string `json:"head_sha,omitempty"`
+}
+
+// IsServiceAccount returns true when login follows the naming of service accounts, i.e. svc-<name>.
+func IsServiceAccount(login string) bool {
+	return strings.HasPrefix(login, "svc-")
 }
 
 func (pr PR) ToTags() []string {
 	acctType := "user"
-	if strings.HasPrefix(pr.Author, "svc-") {
+	if IsServiceAccount(pr.Author) {
 		acctType = "service-account"
 	}
 	return []string{fmt.Sprintf("owner:%s", pr.Owner),
@@ -17,34 +17,55 @@ type ecrPuller struct {
 }
 
 // Pull implements Puller.
-func (e *ecrPuller) Pull(ctx context.Context, id ArtifactID, path string) error {
+func (e *ecrPuller) Pull(ctx context.Context, id ArtifactID, path string) (string, error) {
 	fs, err := file.New(path)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer fs.Close()
-	// Create a remote repository client
+	repository, err := e.repository(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	// Copy from the remote repository to the OCI layout store
+	manifestDescriptor, err := oras.Copy(ctx, repository, id.Tag, fs, id.Tag, oras.DefaultCopyOptions)
+	if err != nil {
+		return "", err
+	}
+	log.Info().Interface("manifest", manifestDescriptor).Msgf("pulled image")
+	return manifestDescriptor.Digest.String(), nil
+}
+
+// Resolve implements Puller.
+func (e *ecrPuller) Resolve(ctx context.Context, id ArtifactID) (string, error) {
+	repository, err := e.repository(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	manifestDescriptor, err := repository.Resolve(ctx, id.Tag)
+	if err != nil {
+		return "", err
+	}
+	return manifestDescriptor.Digest.String(), nil
+}
+
+// repository returns a client of the remote repository of the artifact.
+func (e *ecrPuller) repository(ctx context.Context, id ArtifactID) (*remote.Repository, error) {
 	repository, err := remote.NewRepository(fmt.Sprintf("%s/%s", id.Registry, id.Repo))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	creds, err := e.credentialRetriever.RetrieveCredential(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	repository.Client = &auth.Client{
 		Client:     retry.DefaultClient,
 		Cache:      auth.DefaultCache,
 		Credential: auth.StaticCredential(id.Registry, creds),
 	}
-
-	// Copy from the remote repository to the OCI layout store
-	manifestDescriptor, err := oras.Copy(ctx, repository, id.Tag, fs, id.Tag, oras.DefaultCopyOptions)
-	if err != nil {
-		return err
-	}
-	log.Info().Interface("manifest", manifestDescriptor).Msgf("pulled image")
-	return nil
+	return repository, nil
 }
 
 func NewECRPuller(credentialRetriever CredentialRetriever) Puller {
@@ -2,8 +2,12 @@ package oci
 
 import "context"
 
+//go:generate mockery --name Puller
 type Puller interface {
-	Pull(ctx context.Context, id ArtifactID, path string) error
+	// Pull pulls the artifact into path, returns the digest of the pulled manifest.
+	Pull(ctx context.Context, id ArtifactID, path string) (string, error)
+	// Resolve returns the digest of the manifest the tag of the artifact points to.
+	Resolve(ctx context.Context, id ArtifactID) (string, error)
 }
 
 type ArtifactID struct {
@@ -12,6 +16,7 @@ type ArtifactID struct {
 	Tag      string
 }
 
+//go:generate mockery --name Reader
 type Reader interface {
 	ListDirs(ctx context.Context, filePath string) ([]string, error)
 	FilterModules(ctx context.Context, dirs []string) []string
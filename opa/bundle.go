@@ -0,0 +1,206 @@
+package opa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"github.com/marqeta/pr-bot/oci"
+	"github.com/marqeta/pr-bot/opa/client"
+)
+
+var ErrNoModules = errors.New("OPA bundle does not contain any modules")
+
+// Bundle is a loaded OPA policy bundle.
+type Bundle struct {
+	// Version is the tag of the bundle, recorded as the policy version in reports.
+	Version string
+	// Digest of the manifest the bundle was pulled from, a new digest pushed to the same tag is reloaded.
+	Digest  string
+	Modules []string
+	Policy  Policy
+	// Client serving the bundle, nil when the bundle is not hot reloaded.
+	Client client.Client
+	// Dir the bundle was pulled into, unique to the bundle and removed once it is superseded.
+	Dir string
+
+	mu       sync.Mutex
+	refs     int
+	retired  bool
+	released chan struct{}
+}
+
+// acquire adds an evaluation in flight, returns false if the bundle was retired.
+func (b *Bundle) acquire() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.retired {
+		return false
+	}
+	b.refs++
+	return true
+}
+
+func (b *Bundle) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refs--
+	if b.retired && b.refs == 0 {
+		close(b.released)
+	}
+}
+
+// retire stops new evaluations from acquiring the bundle,
+// the returned channel is closed once the evaluations in flight release it.
+func (b *Bundle) retire() <-chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.retired {
+		return b.released
+	}
+	b.retired = true
+	b.released = make(chan struct{})
+	if b.refs == 0 {
+		close(b.released)
+	}
+	return b.released
+}
+
+// BundleHolder holds the active bundle.
+// Bundles are swapped atomically, evaluations in flight keep using the bundle they acquired.
+type BundleHolder struct {
+	current atomic.Pointer[Bundle]
+}
+
+func NewBundleHolder(b *Bundle) *BundleHolder {
+	h := &BundleHolder{}
+	h.current.Store(b)
+	return h
+}
+
+// Bundle returns the active bundle.
+func (h *BundleHolder) Bundle() *Bundle {
+	return h.current.Load()
+}
+
+// Acquire returns the active bundle and a func to release it,
+// the bundle is not stopped before it is released.
+func (h *BundleHolder) Acquire() (*Bundle, func()) {
+	for {
+		b := h.current.Load()
+		if b.acquire() {
+			return b, b.release
+		}
+		// b was swapped and retired after it was loaded, the new bundle is active
+	}
+}
+
+// Swap activates b and returns the previously active bundle.
+func (h *BundleHolder) Swap(b *Bundle) *Bundle {
+	return h.current.Swap(b)
+}
+
+// BundleLoader loads the bundle with the tag.
+//
+//go:generate mockery --name BundleLoader --testonly
+type BundleLoader interface {
+	Load(ctx context.Context, tag string) (*Bundle, error)
+	// Resolve returns the digest the tag points to, without loading the bundle.
+	Resolve(ctx context.Context, tag string) (string, error)
+}
+
+type ociBundleLoader struct {
+	puller    oci.Puller
+	reader    oci.Reader
+	artifact  oci.ArtifactID
+	root      string
+	filename  string
+	newClient func(ctx context.Context, bundlePath string) (client.Client, error)
+	newPolicy func(client.Client) Policy
+}
+
+// NewOCIBundleLoader returns a BundleLoader which pulls bundles from the OCI registry into root/<tag>-<random>/filename,
+// every load pulls into a new directory, so that it does not touch the directory of a bundle still serving evaluations.
+// newClient is expected to validate the bundle, e.g. fail when the bundle has compilation errors.
+func NewOCIBundleLoader(puller oci.Puller, reader oci.Reader, artifact oci.ArtifactID, root, filename string,
+	newClient func(ctx context.Context, bundlePath string) (client.Client, error),
+	newPolicy func(client.Client) Policy) BundleLoader {
+	return &ociBundleLoader{
+		puller:    puller,
+		reader:    reader,
+		artifact:  artifact,
+		root:      root,
+		filename:  filename,
+		newClient: newClient,
+		newPolicy: newPolicy,
+	}
+}
+
+// Load implements BundleLoader.
+func (l *ociBundleLoader) Load(ctx context.Context, tag string) (*Bundle, error) {
+	err := os.MkdirAll(l.root, 0o700)
+	if err != nil {
+		return nil, fmt.Errorf("error creating directory of OPA bundles %v: %w", l.root, err)
+	}
+	dir, err := os.MkdirTemp(l.root, tag+"-")
+	if err != nil {
+		return nil, fmt.Errorf("error creating directory of OPA bundle %v: %w", tag, err)
+	}
+	artifact := l.artifact
+	artifact.Tag = tag
+	b, err := l.load(ctx, artifact, dir)
+	if err != nil {
+		// bundles which fail to load are not kept on disk
+		if rmErr := os.RemoveAll(dir); rmErr != nil {
+			return nil, errors.Join(err, rmErr)
+		}
+		return nil, err
+	}
+	return b, nil
+}
+
+// Resolve implements BundleLoader.
+func (l *ociBundleLoader) Resolve(ctx context.Context, tag string) (string, error) {
+	artifact := l.artifact
+	artifact.Tag = tag
+	digest, err := l.puller.Resolve(ctx, artifact)
+	if err != nil {
+		return "", fmt.Errorf("error resolving OPA bundle %v: %w", tag, err)
+	}
+	return digest, nil
+}
+
+func (l *ociBundleLoader) load(ctx context.Context, artifact oci.ArtifactID, dir string) (*Bundle, error) {
+	tag := artifact.Tag
+	digest, err := l.puller.Pull(ctx, artifact, dir)
+	if err != nil {
+		return nil, fmt.Errorf("error pulling OPA bundle %v: %w", tag, err)
+	}
+
+	path := filepath.Join(dir, l.filename)
+	dirs, err := l.reader.ListDirs(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading OPA bundle %v: %w", tag, err)
+	}
+	modules := l.reader.FilterModules(ctx, dirs)
+	if len(modules) == 0 {
+		return nil, fmt.Errorf("%w: %v", ErrNoModules, tag)
+	}
+
+	c, err := l.newClient(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("error loading OPA bundle %v: %w", tag, err)
+	}
+	return &Bundle{
+		Version: tag,
+		Digest:  digest,
+		Modules: modules,
+		Policy:  l.newPolicy(c),
+		Client:  c,
+		Dir:     dir,
+	}, nil
+}
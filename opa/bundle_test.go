@@ -0,0 +1,321 @@
+package opa_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/marqeta/pr-bot/configstore"
+	"github.com/marqeta/pr-bot/metrics"
+	"github.com/marqeta/pr-bot/oci"
+	"github.com/marqeta/pr-bot/opa"
+	"github.com/marqeta/pr-bot/opa/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_ociBundleLoader_Load(t *testing.T) {
+	ctx := context.TODO()
+	//nolint:goerr113
+	randomErr := fmt.Errorf("random error")
+	artifact := oci.ArtifactID{Registry: "registry", Repo: "repo", Tag: "v2"}
+	tests := []struct {
+		name            string
+		setExpectations func(p *oci.MockPuller, r *oci.MockReader, dir, path interface{}) error
+		wantModules     []string
+		wantErr         bool
+	}{
+		{
+			name: "should load bundle",
+			setExpectations: func(p *oci.MockPuller, r *oci.MockReader, dir, path interface{}) error {
+				p.EXPECT().Pull(ctx, artifact, dir).Return("sha256:2", nil)
+				r.EXPECT().ListDirs(ctx, path).Return([]string{"m1", "m2", "lib"}, nil)
+				r.EXPECT().FilterModules(ctx, []string{"m1", "m2", "lib"}).Return([]string{"m1", "m2"})
+				return nil
+			},
+			wantModules: []string{"m1", "m2"},
+			wantErr:     false,
+		},
+		{
+			name: "should return error when pull fails",
+			setExpectations: func(p *oci.MockPuller, _ *oci.MockReader, dir, _ interface{}) error {
+				p.EXPECT().Pull(ctx, artifact, dir).Return("", randomErr)
+				return nil
+			},
+			wantErr: true,
+		},
+		{
+			name: "should return error when bundle cannot be read",
+			setExpectations: func(p *oci.MockPuller, r *oci.MockReader, dir, path interface{}) error {
+				p.EXPECT().Pull(ctx, artifact, dir).Return("sha256:2", nil)
+				r.EXPECT().ListDirs(ctx, path).Return(nil, randomErr)
+				return nil
+			},
+			wantErr: true,
+		},
+		{
+			name: "should return error when bundle has no modules",
+			setExpectations: func(p *oci.MockPuller, r *oci.MockReader, dir, path interface{}) error {
+				p.EXPECT().Pull(ctx, artifact, dir).Return("sha256:2", nil)
+				r.EXPECT().ListDirs(ctx, path).Return([]string{"lib"}, nil)
+				r.EXPECT().FilterModules(ctx, []string{"lib"}).Return([]string{})
+				return nil
+			},
+			wantErr: true,
+		},
+		{
+			name: "should return error when bundle cannot be activated",
+			setExpectations: func(p *oci.MockPuller, r *oci.MockReader, dir, path interface{}) error {
+				p.EXPECT().Pull(ctx, artifact, dir).Return("sha256:2", nil)
+				r.EXPECT().ListDirs(ctx, path).Return([]string{"m1"}, nil)
+				r.EXPECT().FilterModules(ctx, []string{"m1"}).Return([]string{"m1"})
+				return client.ErrBundleNotReady
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			// directory of the same tag still serving evaluations
+			active := filepath.Join(root, "v2-active")
+			assert.Nil(t, os.Mkdir(active, 0o700))
+			isNewDir := func(dir string) bool {
+				return filepath.Dir(dir) == root && dir != active && strings.HasPrefix(filepath.Base(dir), "v2-")
+			}
+			isBundlePath := func(path string) bool {
+				return filepath.Base(path) == "bundle.tar.gz" && isNewDir(filepath.Dir(path))
+			}
+			p := oci.NewMockPuller(t)
+			r := oci.NewMockReader(t)
+			c := client.NewMockClient(t)
+			policy := opa.NewMockPolicy(t)
+			clientErr := tt.setExpectations(p, r, mock.MatchedBy(isNewDir), mock.MatchedBy(isBundlePath))
+
+			loader := opa.NewOCIBundleLoader(p, r, oci.ArtifactID{Registry: "registry", Repo: "repo"},
+				root, "bundle.tar.gz",
+				func(_ context.Context, path string) (client.Client, error) {
+					assert.True(t, isBundlePath(path))
+					if clientErr != nil {
+						return nil, clientErr
+					}
+					return c, nil
+				},
+				func(client.Client) opa.Policy { return policy })
+
+			got, err := loader.Load(ctx, "v2")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ociBundleLoader.Load() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.DirExists(t, active, "directory of the active bundle should not be removed")
+			entries, err := os.ReadDir(root)
+			assert.Nil(t, err)
+			if tt.wantErr {
+				assert.Len(t, entries, 1, "directory of the failed load should be removed")
+				return
+			}
+			assert.Len(t, entries, 2)
+			assert.True(t, isNewDir(got.Dir))
+			assert.Equal(t, &opa.Bundle{Version: "v2", Digest: "sha256:2", Modules: tt.wantModules, Policy: policy,
+				Client: c, Dir: got.Dir}, got)
+		})
+	}
+}
+
+func Test_ociBundleLoader_Resolve(t *testing.T) {
+	ctx := context.TODO()
+	p := oci.NewMockPuller(t)
+	p.EXPECT().Resolve(ctx, oci.ArtifactID{Registry: "registry", Repo: "repo", Tag: "v2"}).Return("sha256:2", nil)
+	loader := opa.NewOCIBundleLoader(p, oci.NewMockReader(t), oci.ArtifactID{Registry: "registry", Repo: "repo"},
+		t.TempDir(), "bundle.tar.gz", nil, nil)
+
+	got, err := loader.Resolve(ctx, "v2")
+	assert.Nil(t, err)
+	assert.Equal(t, "sha256:2", got)
+}
+
+func Test_BundleWatcher_Reload(t *testing.T) {
+	ctx := context.TODO()
+	//nolint:goerr113
+	randomErr := fmt.Errorf("random error")
+	tests := []struct {
+		name            string
+		desiredTag      string
+		setExpectations func(l *opa.MockBundleLoader, old, next *client.MockClient)
+		wantReloaded    bool
+		wantVersion     string
+		wantErr         bool
+	}{
+		{
+			name:       "should activate bundle when desired tag changes",
+			desiredTag: "v2",
+			setExpectations: func(l *opa.MockBundleLoader, _, next *client.MockClient) {
+				l.EXPECT().Load(ctx, "v2").Return(&opa.Bundle{Version: "v2", Modules: []string{"m1"}, Client: next}, nil)
+			},
+			wantReloaded: true,
+			wantVersion:  "v2",
+			wantErr:      false,
+		},
+		{
+			name:       "should not reload when desired tag is active",
+			desiredTag: "v1",
+			setExpectations: func(l *opa.MockBundleLoader, _, _ *client.MockClient) {
+				l.EXPECT().Resolve(ctx, "v1").Return("sha256:1", nil)
+			},
+			wantReloaded: false,
+			wantVersion:  "v1",
+			wantErr:      false,
+		},
+		{
+			name:       "should activate bundle when desired tag points to a new digest",
+			desiredTag: "v1",
+			setExpectations: func(l *opa.MockBundleLoader, _, next *client.MockClient) {
+				l.EXPECT().Resolve(ctx, "v1").Return("sha256:2", nil)
+				l.EXPECT().Load(ctx, "v1").
+					Return(&opa.Bundle{Version: "v1", Digest: "sha256:2", Modules: []string{"m1"}, Client: next}, nil)
+			},
+			wantReloaded: true,
+			wantVersion:  "v1",
+			wantErr:      false,
+		},
+		{
+			name:       "should keep last good bundle when digest cannot be resolved",
+			desiredTag: "v1",
+			setExpectations: func(l *opa.MockBundleLoader, _, _ *client.MockClient) {
+				l.EXPECT().Resolve(ctx, "v1").Return("", randomErr)
+			},
+			wantReloaded: false,
+			wantVersion:  "v1",
+			wantErr:      true,
+		},
+		{
+			name:            "should not reload when desired tag is not set",
+			desiredTag:      "",
+			setExpectations: func(_ *opa.MockBundleLoader, _, _ *client.MockClient) {},
+			wantReloaded:    false,
+			wantVersion:     "v1",
+			wantErr:         false,
+		},
+		{
+			name:       "should keep last good bundle when load fails",
+			desiredTag: "v2",
+			setExpectations: func(l *opa.MockBundleLoader, _, _ *client.MockClient) {
+				l.EXPECT().Load(ctx, "v2").Return(nil, randomErr)
+			},
+			wantReloaded: false,
+			wantVersion:  "v1",
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := opa.NewMockBundleLoader(t)
+			old := client.NewMockClient(t)
+			next := client.NewMockClient(t)
+			tt.setExpectations(l, old, next)
+
+			clock := clockwork.NewFakeClock()
+			holder := opa.NewBundleHolder(&opa.Bundle{Version: "v1", Digest: "sha256:1", Modules: []string{"m1"},
+				Client: old})
+			desired, err := configstore.NewInMemoryStore(&opa.BundleCfg{Tag: tt.desiredTag})
+			assert.Nil(t, err)
+			w := opa.NewBundleWatcher(holder, l, desired, clock, time.Minute, 5*time.Minute, metrics.NewNoopEmitter())
+			stopped := make(chan struct{})
+			if tt.wantReloaded {
+				old.EXPECT().Stop(context.Background()).Run(func(context.Context) { close(stopped) }).Return()
+			}
+
+			got, err := w.Reload(ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BundleWatcher.Reload() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.wantReloaded, got)
+			assert.Equal(t, tt.wantVersion, holder.Bundle().Version)
+
+			if !tt.wantReloaded {
+				return
+			}
+			// previous bundle is stopped once no evaluation is in flight
+			select {
+			case <-stopped:
+			case <-time.After(time.Second):
+				t.Error("previous bundle was not stopped")
+			}
+		})
+	}
+}
+
+func Test_BundleWatcher_Reload_InFlight(t *testing.T) {
+	ctx := context.TODO()
+	tests := []struct {
+		name string
+		// finish ends the evaluation in flight, or lets the grace period elapse
+		finish func(clock clockwork.FakeClock, release func())
+	}{
+		{
+			name: "should stop previous bundle when evaluations in flight finish",
+			finish: func(_ clockwork.FakeClock, release func()) {
+				release()
+			},
+		},
+		{
+			name: "should stop previous bundle after the grace period",
+			finish: func(clock clockwork.FakeClock, _ func()) {
+				clock.BlockUntil(1)
+				clock.Advance(5 * time.Minute)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			oldDir := filepath.Join(root, "v1")
+			assert.Nil(t, os.Mkdir(oldDir, 0o700))
+			l := opa.NewMockBundleLoader(t)
+			old := client.NewMockClient(t)
+			next := client.NewMockClient(t)
+			l.EXPECT().Load(ctx, "v2").
+				Return(&opa.Bundle{Version: "v2", Modules: []string{"m1"}, Client: next, Dir: filepath.Join(root, "v2")}, nil)
+
+			clock := clockwork.NewFakeClock()
+			holder := opa.NewBundleHolder(&opa.Bundle{Version: "v1", Modules: []string{"m1"}, Client: old, Dir: oldDir})
+			desired, err := configstore.NewInMemoryStore(&opa.BundleCfg{Tag: "v2"})
+			assert.Nil(t, err)
+			w := opa.NewBundleWatcher(holder, l, desired, clock, time.Minute, 5*time.Minute, metrics.NewNoopEmitter())
+
+			stopped := make(chan struct{})
+			old.EXPECT().Stop(context.Background()).Run(func(context.Context) { close(stopped) }).Return()
+
+			inFlight, release := holder.Acquire()
+			assert.Equal(t, "v1", inFlight.Version)
+			got, err := w.Reload(ctx)
+			assert.Nil(t, err)
+			assert.True(t, got)
+			active, releaseActive := holder.Acquire()
+			assert.Equal(t, "v2", active.Version, "new evaluations should use the new bundle")
+			releaseActive()
+
+			time.Sleep(10 * time.Millisecond)
+			old.AssertNotCalled(t, "Stop", context.Background())
+
+			tt.finish(clock, release)
+			select {
+			case <-stopped:
+			case <-time.After(time.Second):
+				t.Error("previous bundle was not stopped")
+			}
+			assert.Eventually(t, func() bool {
+				_, err := os.Stat(oldDir)
+				return errors.Is(err, os.ErrNotExist)
+			}, time.Second, 10*time.Millisecond, "directory of previous bundle should be removed")
+		})
+	}
+}
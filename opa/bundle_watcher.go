@@ -0,0 +1,159 @@
+package opa
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/marqeta/pr-bot/configstore"
+	"github.com/marqeta/pr-bot/metrics"
+	"github.com/rs/zerolog/log"
+)
+
+// BundleCfg is the dynamic config holding the desired bundle tag.
+type BundleCfg struct {
+	Tag string `dynamodbav:"tag"`
+}
+
+// Update implements configstore.DynamicConfig.
+func (c *BundleCfg) Update() error {
+	return nil
+}
+
+// BundleWatcher polls the desired bundle tag and hot reloads the bundle when the tag,
+// or the digest the tag points to, changes.
+// A bundle which fails to load is not activated, the last good bundle keeps serving evaluations.
+type BundleWatcher struct {
+	holder   *BundleHolder
+	loader   BundleLoader
+	desired  configstore.Getter[*BundleCfg]
+	clock    clockwork.Clock
+	interval time.Duration
+	// grace is how long the previous bundle is kept running at most for evaluations in flight.
+	grace   time.Duration
+	metrics metrics.Emitter
+	mu      sync.Mutex
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+func NewBundleWatcher(holder *BundleHolder, loader BundleLoader, desired configstore.Getter[*BundleCfg],
+	clock clockwork.Clock, interval, grace time.Duration, m metrics.Emitter) *BundleWatcher {
+	return &BundleWatcher{
+		holder:   holder,
+		loader:   loader,
+		desired:  desired,
+		clock:    clock,
+		interval: interval,
+		grace:    grace,
+		metrics:  m,
+		done:     make(chan struct{}),
+	}
+}
+
+// Start polls the desired tag in the background.
+func (w *BundleWatcher) Start() {
+	log.Info().Msgf("Watching for OPA bundle updates every %v", w.interval)
+	w.wg.Add(1)
+	go w.watch()
+}
+
+// Close stops polling and waits for an in flight reload to finish.
+func (w *BundleWatcher) Close() {
+	close(w.done)
+	w.wg.Wait()
+	log.Info().Msg("OPA bundle watcher stopped")
+}
+
+func (w *BundleWatcher) watch() {
+	defer w.wg.Done()
+	ticker := w.clock.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.Chan():
+			_, err := w.Reload(context.Background())
+			if err != nil {
+				log.Err(err).Msg("error reloading OPA bundle, last good bundle is still active")
+			}
+		}
+	}
+}
+
+// Reload loads and activates the desired bundle if it differs from the active bundle.
+// returns true if a new bundle was activated.
+func (w *BundleWatcher) Reload(ctx context.Context) (bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	cfg, err := w.desired.Get()
+	if err != nil {
+		w.emit(ctx, "ConfigError", "")
+		return false, err
+	}
+	current := w.holder.Bundle()
+	if cfg.Tag == "" {
+		return false, nil
+	}
+	if cfg.Tag == current.Version {
+		// the tag may have been pushed again
+		digest, err := w.loader.Resolve(ctx, cfg.Tag)
+		if err != nil {
+			w.emit(ctx, "ResolveError", cfg.Tag)
+			return false, err
+		}
+		if digest == current.Digest {
+			return false, nil
+		}
+	}
+
+	log.Info().Msgf("Loading OPA bundle %v, active bundle is %v@%v", cfg.Tag, current.Version, current.Digest)
+	next, err := w.loader.Load(ctx, cfg.Tag)
+	if err != nil {
+		w.emit(ctx, "LoadError", cfg.Tag)
+		return false, err
+	}
+
+	previous := w.holder.Swap(next)
+	w.emit(ctx, "Success", cfg.Tag)
+	log.Info().Interface("Modules", next.Modules).Msgf("Activated OPA bundle %v@%v", next.Version, next.Digest)
+
+	go w.stop(previous)
+	return true, nil
+}
+
+// stop stops the client of a superseded bundle once the evaluations in flight release it,
+// or after the grace period, and removes the directory of the bundle.
+func (w *BundleWatcher) stop(b *Bundle) {
+	select {
+	case <-b.retire():
+	case <-w.clock.After(w.grace):
+		log.Warn().Msgf("Evaluations with OPA bundle %v are still in flight after %v", b.Version, w.grace)
+	}
+	if b.Client != nil {
+		b.Client.Stop(context.Background())
+		log.Info().Msgf("Stopped OPA bundle %v", b.Version)
+	}
+	if b.Dir == "" {
+		return
+	}
+	// every bundle is pulled into its own directory, no other bundle uses it
+	err := os.RemoveAll(b.Dir)
+	if err != nil {
+		log.Err(err).Msgf("error removing OPA bundle %v", b.Dir)
+		return
+	}
+	log.Info().Msgf("Removed OPA bundle %v", b.Dir)
+}
+
+func (w *BundleWatcher) emit(ctx context.Context, status, tag string) {
+	w.metrics.EmitDist(ctx, "opa.bundle.reload", 1, []string{
+		fmt.Sprintf("status:%s", status),
+		fmt.Sprintf("tag:%s", tag),
+	})
+}
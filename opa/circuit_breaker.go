@@ -0,0 +1,157 @@
+package opa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/marqeta/pr-bot/metrics"
+	"github.com/rs/zerolog/log"
+)
+
+var ErrCircuitOpen = errors.New("module circuit breaker is open, module was not evaluated")
+
+// CircuitBreaker stops evaluating modules whose error rate crosses a threshold.
+type CircuitBreaker interface {
+	// Allow returns false if the module should not be evaluated.
+	Allow(module string) bool
+	// Record records the outcome of evaluating the module.
+	Record(ctx context.Context, module string, err error)
+}
+
+// CircuitBreakerConfig configures when the circuit of a module is opened.
+type CircuitBreakerConfig struct {
+	// Window is the number of most recent evaluations of a module the error rate is computed over.
+	Window int
+	// MinEvaluations in the window before the circuit can open.
+	MinEvaluations int
+	// Threshold is the error rate, between 0 and 1, at which the circuit opens.
+	Threshold float64
+	// Cooldown is how long the circuit stays open before the module is evaluated again.
+	Cooldown time.Duration
+}
+
+type noopCircuitBreaker struct{}
+
+// NewNoopCircuitBreaker returns a CircuitBreaker which always allows modules to be evaluated.
+func NewNoopCircuitBreaker() CircuitBreaker {
+	return &noopCircuitBreaker{}
+}
+
+// Allow implements CircuitBreaker.
+func (*noopCircuitBreaker) Allow(_ string) bool {
+	return true
+}
+
+// Record implements CircuitBreaker.
+func (*noopCircuitBreaker) Record(_ context.Context, _ string, _ error) {}
+
+type circuit struct {
+	// outcomes is a ring buffer, true for evaluations which failed.
+	outcomes  []bool
+	next      int
+	count     int
+	errors    int
+	openUntil time.Time
+	// halfOpen is true after the cooldown, the next evaluation closes or opens the circuit again.
+	halfOpen bool
+}
+
+func (c *circuit) reset() {
+	clear(c.outcomes)
+	c.next = 0
+	c.count = 0
+	c.errors = 0
+}
+
+func (c *circuit) add(failed bool) {
+	if c.count == len(c.outcomes) {
+		if c.outcomes[c.next] {
+			c.errors--
+		}
+	} else {
+		c.count++
+	}
+	c.outcomes[c.next] = failed
+	if failed {
+		c.errors++
+	}
+	c.next = (c.next + 1) % len(c.outcomes)
+}
+
+type circuitBreaker struct {
+	cfg      CircuitBreakerConfig
+	clock    clockwork.Clock
+	metrics  metrics.Emitter
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+// NewCircuitBreaker returns a CircuitBreaker which tracks the error rate of each module in the process.
+func NewCircuitBreaker(cfg CircuitBreakerConfig, clock clockwork.Clock, m metrics.Emitter) CircuitBreaker {
+	return &circuitBreaker{
+		cfg:      cfg,
+		clock:    clock,
+		metrics:  m,
+		circuits: make(map[string]*circuit),
+	}
+}
+
+// Allow implements CircuitBreaker.
+func (b *circuitBreaker) Allow(module string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !b.clock.Now().Before(b.circuit(module).openUntil)
+}
+
+// Record implements CircuitBreaker.
+func (b *circuitBreaker) Record(ctx context.Context, module string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c := b.circuit(module)
+	failed := err != nil
+
+	if c.halfOpen {
+		if failed {
+			b.open(ctx, module, c)
+			return
+		}
+		c.halfOpen = false
+		c.reset()
+		log.Info().Msgf("circuit of module %s is closed", module)
+		b.emit(ctx, module, "closed")
+		return
+	}
+
+	c.add(failed)
+	if c.count >= b.cfg.MinEvaluations && float64(c.errors)/float64(c.count) >= b.cfg.Threshold {
+		b.open(ctx, module, c)
+	}
+}
+
+func (b *circuitBreaker) open(ctx context.Context, module string, c *circuit) {
+	c.openUntil = b.clock.Now().Add(b.cfg.Cooldown)
+	c.halfOpen = true
+	c.reset()
+	log.Warn().Msgf("circuit of module %s is open until %v", module, c.openUntil)
+	b.emit(ctx, module, "open")
+}
+
+func (b *circuitBreaker) circuit(module string) *circuit {
+	c, ok := b.circuits[module]
+	if !ok {
+		c = &circuit{outcomes: make([]bool, max(b.cfg.Window, 1))}
+		b.circuits[module] = c
+	}
+	return c
+}
+
+func (b *circuitBreaker) emit(ctx context.Context, module, state string) {
+	b.metrics.EmitDist(ctx, "opa.breaker.state", 1, []string{
+		fmt.Sprintf("module:%s", module),
+		fmt.Sprintf("state:%s", state),
+	})
+}
@@ -0,0 +1,131 @@
+package opa_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/jonboulle/clockwork"
+	"github.com/marqeta/pr-bot/configstore"
+	"github.com/marqeta/pr-bot/metrics"
+	"github.com/marqeta/pr-bot/opa"
+	"github.com/marqeta/pr-bot/opa/evaluation"
+	"github.com/marqeta/pr-bot/opa/input"
+	"github.com/marqeta/pr-bot/opa/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_circuitBreaker(t *testing.T) {
+	ctx := context.TODO()
+	//nolint:goerr113
+	randomErr := fmt.Errorf("random error")
+	clock := clockwork.NewFakeClock()
+	b := opa.NewCircuitBreaker(opa.CircuitBreakerConfig{
+		Window:         4,
+		MinEvaluations: 3,
+		Threshold:      0.5,
+		Cooldown:       time.Minute,
+	}, clock, metrics.NewNoopEmitter())
+
+	b.Record(ctx, "m1", randomErr)
+	b.Record(ctx, "m1", randomErr)
+	assert.True(t, b.Allow("m1"), "circuit should not open before min evaluations")
+	b.Record(ctx, "m1", nil)
+	assert.False(t, b.Allow("m1"), "circuit should open when error rate crosses threshold")
+	assert.True(t, b.Allow("m2"), "circuits should be tracked per module")
+
+	clock.Advance(time.Minute)
+	assert.True(t, b.Allow("m1"), "module should be evaluated after cooldown")
+	b.Record(ctx, "m1", randomErr)
+	assert.False(t, b.Allow("m1"), "circuit should open again when module fails after cooldown")
+
+	clock.Advance(time.Minute)
+	b.Record(ctx, "m1", nil)
+	assert.True(t, b.Allow("m1"), "circuit should close when module succeeds after cooldown")
+
+	// errors older than the window are forgotten
+	b.Record(ctx, "m1", randomErr)
+	b.Record(ctx, "m1", nil)
+	b.Record(ctx, "m1", nil)
+	b.Record(ctx, "m1", nil)
+	b.Record(ctx, "m1", nil)
+	b.Record(ctx, "m1", randomErr)
+	assert.True(t, b.Allow("m1"))
+}
+
+func Test_evaluator_Evaluate_CircuitOpen(t *testing.T) {
+	ctx := context.WithValue(context.TODO(), middleware.RequestIDKey, "request_id")
+	ctx = context.WithValue(ctx, evaluation.DeliveryIDKey, "delivery_id")
+	//nolint:goerr113
+	randomErr := fmt.Errorf("random error")
+	breaker := opa.NewCircuitBreaker(opa.CircuitBreakerConfig{
+		Window:         1,
+		MinEvaluations: 1,
+		Threshold:      1,
+		Cooldown:       time.Minute,
+	}, clockwork.NewFakeClock(), metrics.NewNoopEmitter())
+	breaker.Record(ctx, "m1", randomErr)
+
+	f := input.NewMockFactory(t)
+	p := opa.NewMockPolicy(t)
+	m := evaluation.NewMockManager(t)
+	b := evaluation.NewMockReportBuilder(t)
+	modes, err := configstore.NewInMemoryStore(&opa.ModuleCfg{Failures: map[string]string{"m1": "open"}})
+	assert.Nil(t, err)
+	holder := opa.NewBundleHolder(&opa.Bundle{Modules: []string{"m1", "m2"}, Policy: p})
+	e := opa.NewReloadableEvaluator(holder, modes, breaker, f, m,
+		opa.EvaluatorConfig{Workers: 2}, clockwork.NewFakeClock(), metrics.NewNoopEmitter())
+
+	f.EXPECT().CreateModel(ctx, randomGHE()).Return(randomModel(), nil)
+	m.EXPECT().NewReportBuilder(ctx, "ci/terraform-provider-oci/259", "request_id", "delivery_id").Return(b)
+	b.EXPECT().SetInput(randomModel())
+	b.EXPECT().AddModuleResult("m1", evaluation.Result{Err: opa.ErrCircuitOpen, Failure: "open", CircuitOpen: true})
+	p.EXPECT().Evaluate(ctx, "m2", randomModel()).Return(comment(), nil)
+	b.EXPECT().AddModuleResult("m2", evalResult(comment(), nil))
+	b.EXPECT().SetOutcome(evalResult(comment(), nil))
+	m.EXPECT().StoreReport(ctx, b).Return(nil)
+
+	got, err := e.Evaluate(ctx, randomGHE())
+	assert.Nil(t, err)
+	assert.Equal(t, comment(), got)
+}
+
+func Test_evaluator_Evaluate_CircuitOpen_AbortingModule(t *testing.T) {
+	ctx := context.WithValue(context.TODO(), middleware.RequestIDKey, "request_id")
+	ctx = context.WithValue(ctx, evaluation.DeliveryIDKey, "delivery_id")
+	//nolint:goerr113
+	randomErr := fmt.Errorf("random error")
+	breaker := opa.NewCircuitBreaker(opa.CircuitBreakerConfig{
+		Window:         1,
+		MinEvaluations: 1,
+		Threshold:      1,
+		Cooldown:       time.Minute,
+	}, clockwork.NewFakeClock(), metrics.NewNoopEmitter())
+	breaker.Record(ctx, "m1", randomErr)
+
+	f := input.NewMockFactory(t)
+	p := opa.NewMockPolicy(t)
+	m := evaluation.NewMockManager(t)
+	b := evaluation.NewMockReportBuilder(t)
+	// m1 is not configured, it aborts the evaluation on errors
+	modes, err := configstore.NewInMemoryStore(&opa.ModuleCfg{})
+	assert.Nil(t, err)
+	holder := opa.NewBundleHolder(&opa.Bundle{Modules: []string{"m1", "m2"}, Policy: p})
+	e := opa.NewReloadableEvaluator(holder, modes, breaker, f, m,
+		opa.EvaluatorConfig{Workers: 1}, clockwork.NewFakeClock(), metrics.NewNoopEmitter())
+
+	f.EXPECT().CreateModel(ctx, randomGHE()).Return(randomModel(), nil)
+	m.EXPECT().NewReportBuilder(ctx, "ci/terraform-provider-oci/259", "request_id", "delivery_id").Return(b)
+	b.EXPECT().SetInput(randomModel())
+	b.EXPECT().AddModuleResult("m1", evaluation.Result{Err: opa.ErrCircuitOpen, CircuitOpen: true})
+	b.EXPECT().SetOutcome(evaluation.Result{Err: opa.ErrCircuitOpen})
+	// m2 is not started once m1 aborted the evaluation
+	b.EXPECT().AddModuleResult("m2", evalResult(types.Result{}, opa.ErrNotEvaluated))
+	m.EXPECT().StoreReport(ctx, b).Return(nil)
+
+	got, err := e.Evaluate(ctx, randomGHE())
+	assert.ErrorIs(t, err, opa.ErrCircuitOpen)
+	assert.Equal(t, types.Result{}, got)
+}
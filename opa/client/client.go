@@ -1,8 +1,12 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/open-policy-agent/opa/v1/sdk"
@@ -13,8 +17,11 @@ type Client interface {
 	Decision(ctx context.Context, options sdk.DecisionOptions) (*sdk.DecisionResult, error)
 	DecisionID(ctx context.Context) string
 	Path(module string, rule string) string
+	Stop(ctx context.Context)
 }
 
+var ErrBundleNotReady = errors.New("OPA bundle was not activated")
+
 type client struct {
 	sdk *sdk.OPA
 }
@@ -32,6 +39,46 @@ func (c *client) Decision(ctx context.Context, options sdk.DecisionOptions) (*sd
 	return c.sdk.Decision(ctx, options)
 }
 
+// Stop implements Client.
+func (c *client) Stop(ctx context.Context) {
+	c.sdk.Stop(ctx)
+}
+
+// NewBundleClient creates an OPA SDK serving the bundle at bundlePath.
+// returns ErrBundleNotReady if the bundle could not be activated within timeout,
+// e.g. when the bundle has compilation errors.
+func NewBundleClient(ctx context.Context, id string, labels map[string]string,
+	bundlePath string, timeout time.Duration) (Client, error) {
+
+	config, err := json.Marshal(map[string]any{
+		"labels": labels,
+		"bundles": map[string]any{
+			"local": map[string]string{
+				"resource": fmt.Sprintf("file:///%s", bundlePath),
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	ready := make(chan struct{})
+	opaSDK, err := sdk.New(ctx, sdk.Options{
+		ID:     id,
+		Config: bytes.NewReader(config),
+		Ready:  ready,
+	})
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case <-ready:
+		return NewClient(opaSDK), nil
+	case <-time.After(timeout):
+		opaSDK.Stop(ctx)
+		return nil, fmt.Errorf("%w: %v", ErrBundleNotReady, bundlePath)
+	}
+}
+
 func NewClient(sdk *sdk.OPA) Client {
 	return &client{
 		sdk: sdk,
@@ -174,6 +174,39 @@ func (_c *MockClient_Path_Call) RunAndReturn(run func(string, string) string) *M
 	return _c
 }
 
+// Stop provides a mock function with given fields: ctx
+func (_m *MockClient) Stop(ctx context.Context) {
+	_m.Called(ctx)
+}
+
+// MockClient_Stop_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Stop'
+type MockClient_Stop_Call struct {
+	*mock.Call
+}
+
+// Stop is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockClient_Expecter) Stop(ctx interface{}) *MockClient_Stop_Call {
+	return &MockClient_Stop_Call{Call: _e.mock.On("Stop", ctx)}
+}
+
+func (_c *MockClient_Stop_Call) Run(run func(ctx context.Context)) *MockClient_Stop_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockClient_Stop_Call) Return() *MockClient_Stop_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockClient_Stop_Call) RunAndReturn(run func(context.Context)) *MockClient_Stop_Call {
+	_c.Run(run)
+	return _c
+}
+
 // NewMockClient creates a new instance of MockClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockClient(t interface {
@@ -9,6 +9,7 @@ type ReportBuilder interface {
 	AddModuleResult(module string, result Result)
 	SetInput(input *input.Model)
 	SetOutcome(result Result)
+	SetPolicyVersion(version string)
 	GetReport() Report
 }
 
@@ -35,6 +36,11 @@ func (b *reportBuilder) SetOutcome(outcome Result) {
 	b.report.Outcome = outcome
 }
 
+// SetPolicyVersion implements Builder.
+func (b *reportBuilder) SetPolicyVersion(version string) {
+	b.report.PolicyVersion = version
+}
+
 // GetReport implements Builder.
 func (b *reportBuilder) GetReport() Report {
 	return b.report
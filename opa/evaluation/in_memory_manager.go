@@ -0,0 +1,78 @@
+package evaluation
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+type inMemoryManager struct {
+	policyVersion string
+	mu            sync.Mutex
+	reports       map[string]map[string]Report
+}
+
+// NewInMemoryManager returns a Manager which keeps reports in memory, for local runs.
+func NewInMemoryManager(policyVersion string) Manager {
+	return &inMemoryManager{
+		policyVersion: policyVersion,
+		reports:       make(map[string]map[string]Report),
+	}
+}
+
+// NewReportBuilder implements Manager.
+func (m *inMemoryManager) NewReportBuilder(_ context.Context, pr, reqID, deliveryID string) ReportBuilder {
+	return newReportBuilder(pr, reqID, deliveryID, m.policyVersion, 0)
+}
+
+// GetReport implements Manager.
+func (m *inMemoryManager) GetReport(_ context.Context, pr, deliveryID string) (*Report, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	report, ok := m.reports[pr][deliveryID]
+	if !ok {
+		return nil, fmt.Errorf("report for pr: %v delivery_id: %v %w", pr, deliveryID, ErrReportNotFound)
+	}
+	return &report, nil
+}
+
+// StoreReport implements Manager.
+func (m *inMemoryManager) StoreReport(_ context.Context, builder ReportBuilder) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	report := builder.GetReport()
+	if _, ok := m.reports[report.PR]; !ok {
+		m.reports[report.PR] = make(map[string]Report)
+	}
+	m.reports[report.PR][report.DeliveryID] = report
+	return nil
+}
+
+// ListReports implements Manager.
+func (m *inMemoryManager) ListReports(_ context.Context, pr string) ([]ReportMetadata, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	reports := make([]ReportMetadata, 0, len(m.reports[pr]))
+	for _, report := range m.reports[pr] {
+		reports = append(reports, report.ReportMetadata)
+	}
+	slices.SortFunc(reports, func(i, j ReportMetadata) int {
+		return cmp.Compare(i.CreatedAt, j.CreatedAt)
+	})
+	return reports, nil
+}
+
+// RecordReconciliation implements Manager.
+func (m *inMemoryManager) RecordReconciliation(_ context.Context, pr, deliveryID string, reconciliation Reconciliation) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	report, ok := m.reports[pr][deliveryID]
+	if !ok {
+		return fmt.Errorf("report for pr: %v delivery_id: %v %w", pr, deliveryID, ErrReportNotFound)
+	}
+	report.Reconciliation = &reconciliation
+	m.reports[pr][deliveryID] = report
+	return nil
+}
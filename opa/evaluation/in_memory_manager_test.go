@@ -0,0 +1,45 @@
+package evaluation_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/marqeta/pr-bot/opa/evaluation"
+	"github.com/marqeta/pr-bot/opa/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_inMemoryManager(t *testing.T) {
+	ctx := context.TODO()
+	m := evaluation.NewInMemoryManager("v1")
+
+	_, err := m.GetReport(ctx, "org/repo/1", "delivery1")
+	assert.True(t, errors.Is(err, evaluation.ErrReportNotFound))
+
+	b := m.NewReportBuilder(ctx, "org/repo/1", "req1", "delivery1")
+	result := evaluation.Result{Result: types.Result{Track: true, Review: types.Review{Type: types.Approve}}}
+	b.AddModuleResult("m1", result)
+	b.SetOutcome(result)
+	assert.Nil(t, m.StoreReport(ctx, b))
+
+	got, err := m.GetReport(ctx, "org/repo/1", "delivery1")
+	assert.Nil(t, err)
+	assert.Equal(t, "v1", got.PolicyVersion)
+	assert.Equal(t, "req1", got.RequestID)
+	assert.Equal(t, map[string]evaluation.Result{"m1": result}, got.Breakdown)
+	assert.Equal(t, result, got.Outcome)
+
+	reports, err := m.ListReports(ctx, "org/repo/1")
+	assert.Nil(t, err)
+	assert.Equal(t, []evaluation.ReportMetadata{got.ReportMetadata}, reports)
+
+	reconciliation := evaluation.Reconciliation{DismissedReviews: []int64{1}, AutoMergeDisabled: true}
+	assert.Nil(t, m.RecordReconciliation(ctx, "org/repo/1", "delivery1", reconciliation))
+	got, err = m.GetReport(ctx, "org/repo/1", "delivery1")
+	assert.Nil(t, err)
+	assert.Equal(t, &reconciliation, got.Reconciliation)
+
+	err = m.RecordReconciliation(ctx, "org/repo/1", "delivery2", reconciliation)
+	assert.True(t, errors.Is(err, evaluation.ErrReportNotFound))
+}
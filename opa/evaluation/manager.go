@@ -24,6 +24,8 @@ type Manager interface {
 	GetReport(ctx context.Context, pr, deliveryID string) (*Report, error)
 	StoreReport(ctx context.Context, builder ReportBuilder) error
 	ListReports(ctx context.Context, pr string) ([]ReportMetadata, error)
+	// RecordReconciliation adds the reconciliation to a stored report.
+	RecordReconciliation(ctx context.Context, pr, deliveryID string, reconciliation Reconciliation) error
 }
 
 type manager struct {
@@ -117,6 +119,10 @@ func (m *manager) GetReport(ctx context.Context, pr, deliveryID string) (*Report
 
 // NewTracker implements Manager.
 func (m *manager) NewReportBuilder(_ context.Context, pr, reqID, deliveryID string) ReportBuilder {
+	return newReportBuilder(pr, reqID, deliveryID, m.policyVersion, m.ttl)
+}
+
+func newReportBuilder(pr, reqID, deliveryID, policyVersion string, ttl time.Duration) ReportBuilder {
 	now := time.Now()
 	return &reportBuilder{
 		report: Report{
@@ -124,8 +130,8 @@ func (m *manager) NewReportBuilder(_ context.Context, pr, reqID, deliveryID stri
 				PR:            pr,
 				RequestID:     reqID,
 				DeliveryID:    deliveryID,
-				PolicyVersion: m.policyVersion,
-				ExpireAt:      now.Add(m.ttl).Unix(),
+				PolicyVersion: policyVersion,
+				ExpireAt:      now.Add(ttl).Unix(),
 				CreatedAt:     now.Unix(),
 			},
 			Breakdown: make(map[string]Result),
@@ -152,6 +158,46 @@ func (m *manager) StoreReport(ctx context.Context, tracker ReportBuilder) error
 	return nil
 }
 
+// RecordReconciliation implements Manager.
+func (m *manager) RecordReconciliation(ctx context.Context, pr, deliveryID string, reconciliation Reconciliation) error {
+	key, err := key(pr, deliveryID)
+	if err != nil {
+		m.emitError(ctx, "RecordReconciliation", "MarshalError")
+		return err
+	}
+	value, err := attributevalue.MarshalWithOptions(reconciliation, useJSONTagEncoding)
+	if err != nil {
+		m.emitError(ctx, "RecordReconciliation", "MarshalError")
+		return err
+	}
+	// reports which expired or were never stored are not created
+	update := expression.Set(expression.Name("reconciliation"), expression.Value(value))
+	cond := expression.AttributeExists(expression.Name("pr"))
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(cond).Build()
+	if err != nil {
+		m.emitError(ctx, "RecordReconciliation", "MarshalError")
+		return err
+	}
+	_, err = m.dao.UpdateItem(context.Background(), &dynamodb.UpdateItemInput{
+		Key:                       key,
+		TableName:                 &m.table,
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	var ccf *types.ConditionalCheckFailedException
+	if errors.As(err, &ccf) {
+		m.emitError(ctx, "RecordReconciliation", "ReportNotFound")
+		return fmt.Errorf("report for pr: %v delivery_id: %v %w", pr, deliveryID, ErrReportNotFound)
+	}
+	if err != nil {
+		m.emitError(ctx, "RecordReconciliation", "DDBUpdateItemError")
+		return err
+	}
+	return nil
+}
+
 func NewManager(dao Dao, policyVersion string, ttl time.Duration,
 	metrics metrics.Emitter, table string) Manager {
 	return &manager{
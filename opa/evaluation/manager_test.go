@@ -2,13 +2,17 @@ package evaluation_test
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/marqeta/pr-bot/metrics"
 	"github.com/marqeta/pr-bot/opa/evaluation"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func Test_GetDeliveryID(t *testing.T) {
@@ -72,3 +76,49 @@ func Test_manager_NewReportBuilder(t *testing.T) {
 		})
 	}
 }
+
+func Test_manager_RecordReconciliation(t *testing.T) {
+	ctx := context.TODO()
+	//nolint:goerr113
+	errRandom := errors.New("random error")
+	reconciliation := evaluation.Reconciliation{DismissedReviews: []int64{1}, AutoMergeDisabled: true}
+	tests := []struct {
+		name         string
+		updateErr    error
+		wantNotFound bool
+		wantErr      bool
+	}{
+		{
+			name:    "Should add reconciliation to report",
+			wantErr: false,
+		},
+		{
+			name:         "Should return ErrReportNotFound when report does not exist",
+			updateErr:    &types.ConditionalCheckFailedException{},
+			wantNotFound: true,
+			wantErr:      true,
+		},
+		{
+			name:      "Should return error when UpdateItem fails",
+			updateErr: errRandom,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDao := evaluation.NewMockDao(t)
+			m := evaluation.NewManager(mockDao, "version", time.Hour, metrics.NewNoopEmitter(), "table")
+			mockDao.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+				r, ok := in.ExpressionAttributeValues[":0"].(*types.AttributeValueMemberM)
+				return *in.TableName == "table" && in.Key["pr"].(*types.AttributeValueMemberS).Value == "pr" &&
+					in.Key["delivery_id"].(*types.AttributeValueMemberS).Value == "delivery1" &&
+					ok && r.Value["auto_merge_disabled"].(*types.AttributeValueMemberBOOL).Value
+			})).Return(&dynamodb.UpdateItemOutput{}, tt.updateErr)
+			err := m.RecordReconciliation(ctx, "pr", "delivery1", reconciliation)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("manager.RecordReconciliation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.wantNotFound, errors.Is(err, evaluation.ErrReportNotFound))
+		})
+	}
+}
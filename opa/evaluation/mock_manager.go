@@ -191,6 +191,55 @@ func (_c *MockManager_NewReportBuilder_Call) RunAndReturn(run func(context.Conte
 	return _c
 }
 
+// RecordReconciliation provides a mock function with given fields: ctx, pr, deliveryID, reconciliation
+func (_m *MockManager) RecordReconciliation(ctx context.Context, pr string, deliveryID string, reconciliation Reconciliation) error {
+	ret := _m.Called(ctx, pr, deliveryID, reconciliation)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordReconciliation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, Reconciliation) error); ok {
+		r0 = rf(ctx, pr, deliveryID, reconciliation)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockManager_RecordReconciliation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordReconciliation'
+type MockManager_RecordReconciliation_Call struct {
+	*mock.Call
+}
+
+// RecordReconciliation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - pr string
+//   - deliveryID string
+//   - reconciliation Reconciliation
+func (_e *MockManager_Expecter) RecordReconciliation(ctx interface{}, pr interface{}, deliveryID interface{}, reconciliation interface{}) *MockManager_RecordReconciliation_Call {
+	return &MockManager_RecordReconciliation_Call{Call: _e.mock.On("RecordReconciliation", ctx, pr, deliveryID, reconciliation)}
+}
+
+func (_c *MockManager_RecordReconciliation_Call) Run(run func(ctx context.Context, pr string, deliveryID string, reconciliation Reconciliation)) *MockManager_RecordReconciliation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(Reconciliation))
+	})
+	return _c
+}
+
+func (_c *MockManager_RecordReconciliation_Call) Return(_a0 error) *MockManager_RecordReconciliation_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockManager_RecordReconciliation_Call) RunAndReturn(run func(context.Context, string, string, Reconciliation) error) *MockManager_RecordReconciliation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // StoreReport provides a mock function with given fields: ctx, builder
 func (_m *MockManager) StoreReport(ctx context.Context, builder ReportBuilder) error {
 	ret := _m.Called(ctx, builder)
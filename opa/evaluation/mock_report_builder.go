@@ -50,11 +50,11 @@ func (_c *MockReportBuilder_AddModuleResult_Call) Return() *MockReportBuilder_Ad
 }
 
 func (_c *MockReportBuilder_AddModuleResult_Call) RunAndReturn(run func(string, Result)) *MockReportBuilder_AddModuleResult_Call {
-	_c.Call.Return(run)
+	_c.Run(run)
 	return _c
 }
 
-// GetReport provides a mock function with given fields:
+// GetReport provides a mock function with no fields
 func (_m *MockReportBuilder) GetReport() Report {
 	ret := _m.Called()
 
@@ -128,7 +128,7 @@ func (_c *MockReportBuilder_SetInput_Call) Return() *MockReportBuilder_SetInput_
 }
 
 func (_c *MockReportBuilder_SetInput_Call) RunAndReturn(run func(*input.Model)) *MockReportBuilder_SetInput_Call {
-	_c.Call.Return(run)
+	_c.Run(run)
 	return _c
 }
 
@@ -161,7 +161,40 @@ func (_c *MockReportBuilder_SetOutcome_Call) Return() *MockReportBuilder_SetOutc
 }
 
 func (_c *MockReportBuilder_SetOutcome_Call) RunAndReturn(run func(Result)) *MockReportBuilder_SetOutcome_Call {
-	_c.Call.Return(run)
+	_c.Run(run)
+	return _c
+}
+
+// SetPolicyVersion provides a mock function with given fields: version
+func (_m *MockReportBuilder) SetPolicyVersion(version string) {
+	_m.Called(version)
+}
+
+// MockReportBuilder_SetPolicyVersion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetPolicyVersion'
+type MockReportBuilder_SetPolicyVersion_Call struct {
+	*mock.Call
+}
+
+// SetPolicyVersion is a helper method to define mock.On call
+//   - version string
+func (_e *MockReportBuilder_Expecter) SetPolicyVersion(version interface{}) *MockReportBuilder_SetPolicyVersion_Call {
+	return &MockReportBuilder_SetPolicyVersion_Call{Call: _e.mock.On("SetPolicyVersion", version)}
+}
+
+func (_c *MockReportBuilder_SetPolicyVersion_Call) Run(run func(version string)) *MockReportBuilder_SetPolicyVersion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockReportBuilder_SetPolicyVersion_Call) Return() *MockReportBuilder_SetPolicyVersion_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockReportBuilder_SetPolicyVersion_Call) RunAndReturn(run func(string)) *MockReportBuilder_SetPolicyVersion_Call {
+	_c.Run(run)
 	return _c
 }
 
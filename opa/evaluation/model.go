@@ -12,15 +12,43 @@ type ctxKeyDeliveryID int
 
 const DeliveryIDKey ctxKeyDeliveryID = 0
 
+// Key to use when setting the modules to skip.
+type ctxKeySkippedModules int
+
+const SkippedModulesKey ctxKeySkippedModules = 0
+
 type Result struct {
 	Result types.Result `json:"result"`
 	Err    error        `json:"err"`
+	// Shadow is true if the module was evaluated in shadow mode and did not affect the outcome.
+	Shadow bool `json:"shadow,omitempty"`
+	// Failure is the failure mode applied to Err, empty if the error aborted the evaluation.
+	Failure string `json:"failure,omitempty"`
+	// CircuitOpen is true if the module was not evaluated because its circuit breaker is open.
+	CircuitOpen bool `json:"circuit_open,omitempty"`
+	// LatencyMS is how long evaluating the module took.
+	LatencyMS int64 `json:"latency_ms,omitempty"`
 }
 
 type Report struct {
 	ReportMetadata
 	Breakdown map[string]Result `json:"breakdown"`
 	Input     *input.Model      `json:"input"`
+	// Reconciliation is recorded after the review, when the outcome undid an earlier approval of pr-bot.
+	Reconciliation *Reconciliation `json:"reconciliation,omitempty"`
+}
+
+// Reconciliation records the changes made to a PR, so that its state matches the outcome of the evaluation.
+type Reconciliation struct {
+	// DismissedReviews are the IDs of the stale approvals of pr-bot which were dismissed.
+	DismissedReviews []int64 `json:"dismissed_reviews,omitempty"`
+	// AutoMergeDisabled is true if auto merge enabled by pr-bot was disabled.
+	AutoMergeDisabled bool `json:"auto_merge_disabled,omitempty"`
+}
+
+// IsEmpty returns true if no changes were made to the PR.
+func (r Reconciliation) IsEmpty() bool {
+	return len(r.DismissedReviews) == 0 && !r.AutoMergeDisabled
 }
 
 type ReportMetadata struct {
@@ -47,3 +75,15 @@ func GetDeliveryID(ctx context.Context) string {
 func SetDeliveryID(ctx context.Context, deliveryID string) context.Context {
 	return context.WithValue(ctx, DeliveryIDKey, deliveryID)
 }
+
+// GetSkippedModules returns the modules which should not be evaluated.
+func GetSkippedModules(ctx context.Context) []string {
+	if val, ok := ctx.Value(SkippedModulesKey).([]string); ok {
+		return val
+	}
+	return nil
+}
+
+func SetSkippedModules(ctx context.Context, modules []string) context.Context {
+	return context.WithValue(ctx, SkippedModulesKey, modules)
+}
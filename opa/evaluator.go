@@ -2,15 +2,31 @@ package opa
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/httplog"
+	"github.com/jonboulle/clockwork"
+	"github.com/marqeta/pr-bot/configstore"
+	"github.com/marqeta/pr-bot/metrics"
 	"github.com/marqeta/pr-bot/opa/evaluation"
 	"github.com/marqeta/pr-bot/opa/input"
 	"github.com/marqeta/pr-bot/opa/types"
 )
 
+// SkippedModuleMsg is recorded in the report for modules which were skipped on request.
+const SkippedModuleMsg = "module skipped on request"
+
+var ErrModuleTimeout = errors.New("module evaluation timed out")
+
+// ErrNotEvaluated is recorded in the report for modules which were not started since the evaluation was aborted.
+var ErrNotEvaluated = errors.New("module was not evaluated since the evaluation was aborted")
+
 // Evaluator evaluates the policy for each module in the bundle.
 //
 //go:generate mockery --name Evaluator
@@ -19,18 +35,64 @@ type Evaluator interface {
 }
 
 type evaluator struct {
-	modules      []string
-	policy       Policy
+	bundles      *BundleHolder
+	modes        configstore.Getter[*ModuleCfg]
+	breaker      CircuitBreaker
 	inputFactory input.Factory
 	manager      evaluation.Manager
+	cfg          EvaluatorConfig
+	clock        clockwork.Clock
+	metrics      metrics.Emitter
 }
 
+// EvaluatorConfig configures how modules are evaluated.
+type EvaluatorConfig struct {
+	// Workers is the number of modules evaluated concurrently.
+	Workers int
+	// ModuleTimeout is the deadline for evaluating a single module, no deadline if zero.
+	ModuleTimeout time.Duration
+}
+
+// moduleResult is the result of evaluating a single module.
+type moduleResult struct {
+	result      types.Result
+	err         error
+	circuitOpen bool
+	latency     time.Duration
+	// notEvaluated is true if the module was not started since the evaluation was aborted.
+	notEvaluated bool
+}
+
+// shadowResult is the result of a module in shadow mode.
+type shadowResult struct {
+	module string
+	result types.Result
+	err    error
+}
+
+// NewEvaluator returns an Evaluator which enforces all modules and evaluates them one at a time.
 func NewEvaluator(modules []string, policy Policy, factory input.Factory, manager evaluation.Manager) Evaluator {
+	// empty config cannot fail to update
+	modes, _ := configstore.NewInMemoryStore(&ModuleCfg{})
+	return NewReloadableEvaluator(NewBundleHolder(&Bundle{Modules: modules, Policy: policy}),
+		modes, NewNoopCircuitBreaker(), factory, manager,
+		EvaluatorConfig{Workers: 1}, clockwork.NewRealClock(), metrics.NewNoopEmitter())
+}
+
+// NewReloadableEvaluator returns an Evaluator which evaluates the active bundle of the holder,
+// modules are evaluated in the mode set in modes, unless their circuit is open.
+func NewReloadableEvaluator(bundles *BundleHolder, modes configstore.Getter[*ModuleCfg], breaker CircuitBreaker,
+	factory input.Factory, manager evaluation.Manager, cfg EvaluatorConfig,
+	clock clockwork.Clock, m metrics.Emitter) Evaluator {
 	return &evaluator{
-		modules:      modules,
-		policy:       policy,
+		bundles:      bundles,
+		modes:        modes,
+		breaker:      breaker,
 		inputFactory: factory,
 		manager:      manager,
+		cfg:          cfg,
+		clock:        clock,
+		metrics:      m,
 	}
 }
 
@@ -42,6 +104,14 @@ func (e *evaluator) Evaluate(ctx context.Context, ghe input.GHE) (types.Result,
 
 	oplog := httplog.LogEntry(ctx)
 	coalesced := types.Result{}
+	// all modules are evaluated against the same bundle, even if a new bundle is activated mid evaluation
+	bundle, release := e.bundles.Acquire()
+	defer release()
+	modes, err := e.modes.Get()
+	if err != nil {
+		oplog.Err(err).Msg("failed to get module modes")
+		return types.Result{}, err
+	}
 	model, err := e.inputFactory.CreateModel(ctx, ghe)
 	if err != nil {
 		oplog.Err(err).Msg("failed to create input model for policy evaluation")
@@ -49,14 +119,74 @@ func (e *evaluator) Evaluate(ctx context.Context, ghe input.GHE) (types.Result,
 	}
 	report := e.newReportBuilder(ctx, ghe)
 	defer e.storeReport(ctx, report)
+	if bundle.Version != "" {
+		report.SetPolicyVersion(bundle.Version)
+	}
 	report.SetInput(model)
-	for _, module := range e.modules {
-		result, err := e.policy.Evaluate(ctx, module, model)
-		report.AddModuleResult(module, evaluation.Result{
-			Result: result,
-			Err:    err,
-		})
+	skipped := evaluation.GetSkippedModules(ctx)
+	evaluate := make([]bool, len(bundle.Modules))
+	abortOnError := make([]bool, len(bundle.Modules))
+	for i, module := range bundle.Modules {
+		mode := modes.Mode(module)
+		evaluate[i] = mode == Shadow || (mode == Enforce && !slices.Contains(skipped, module))
+		abortOnError[i] = mode == Enforce && modes.Failure(module) == FailAbort
+	}
+	results := e.evaluateModules(ctx, ghe, bundle, model, evaluate, abortOnError)
+
+	// results are coalesced in module order, so that the outcome does not depend on which module finished first
+	shadows := make([]shadowResult, 0)
+	merges := make([]*types.Merge, 0)
+	labels := make([]*types.Labels, 0)
+	reviewers := make([]*types.Reviewers, 0)
+	var comments []types.InlineComment
+	for i, module := range bundle.Modules {
+		switch modes.Mode(module) {
+		case Disabled:
+			oplog.Info().Msgf("module %s is disabled", module)
+			continue
+		case Shadow:
+			r := results[i]
+			report.AddModuleResult(module, r.toReport(true))
+			if r.notEvaluated {
+				continue
+			}
+			if r.err != nil {
+				// shadow modules cannot fail the evaluation
+				oplog.Err(r.err).Msgf("failed to evaluate policy for shadow module %s", module)
+			}
+			shadows = append(shadows, shadowResult{module: module, result: r.result, err: r.err})
+			continue
+		case Enforce:
+		}
+		if slices.Contains(skipped, module) {
+			oplog.Info().Msgf("module %s was skipped on request", module)
+			report.AddModuleResult(module, evaluation.Result{
+				Result: types.Result{
+					Track:  false,
+					Review: types.Review{Type: types.Skip, Body: SkippedModuleMsg},
+				},
+			})
+			continue
+		}
+		if results[i].notEvaluated {
+			// a module after this module aborted the evaluation, which fails below
+			report.AddModuleResult(module, results[i].toReport(false))
+			continue
+		}
+		result, err, latency := results[i].result, results[i].err, results[i].latency.Milliseconds()
+		// modules whose circuit is open fail in their failure mode,
+		// a failing module must not let an approval through unless it is configured to fail open
+		failure := modes.Failure(module)
 		if err != nil {
+			e.emitModuleError(ctx, ghe, module, failure)
+		}
+		if err != nil && failure == FailAbort {
+			report.AddModuleResult(module, evaluation.Result{
+				Result:      result,
+				Err:         err,
+				CircuitOpen: results[i].circuitOpen,
+				LatencyMS:   latency,
+			})
 			oplog.Err(err).Msgf("failed to evaluate policy for module %s", module)
 			// single module eval failed stop evaluating other modules
 			// module A -> request changes
@@ -65,31 +195,271 @@ func (e *evaluator) Evaluate(ctx context.Context, ghe input.GHE) (types.Result,
 			// if module A start failing after an update to policies
 			// we should not start approving the PR
 			// therefore fail the entire evaluation if a single module fails
+			// unless the module declares how its failures are handled
 			report.SetOutcome(evaluation.Result{
 				Err: err,
 			})
+			// results of modules after this module are recorded, but not coalesced
+			for j := i + 1; j < len(bundle.Modules); j++ {
+				if evaluate[j] {
+					shadow := modes.Mode(bundle.Modules[j]) == Shadow
+					report.AddModuleResult(bundle.Modules[j], results[j].toReport(shadow))
+				}
+			}
 			return types.Result{}, err
 		}
+		if err != nil {
+			oplog.Err(err).Msgf("failed to evaluate policy for module %s, failure mode is %s", module, failure)
+			result = failedResult(module, failure)
+			report.AddModuleResult(module, evaluation.Result{
+				Result:      result,
+				Err:         err,
+				Failure:     string(failure),
+				CircuitOpen: results[i].circuitOpen,
+				LatencyMS:   latency,
+			})
+		} else {
+			report.AddModuleResult(module, evaluation.Result{
+				Result:    result,
+				LatencyMS: latency,
+			})
+		}
 		oplog.Info().Msgf("%v policy evaluation result: %+v", module, result)
 		if !result.Track {
 			oplog.Info().Msgf("track is false, skipping result for module %s", module)
 			// module ignores a PR, need to continue to evaluate other modules
 			continue
 		}
+		if result.Labels != nil {
+			labels = append(labels, result.Labels)
+		}
+		if result.Reviewers != nil {
+			reviewers = append(reviewers, result.Reviewers)
+		}
+		for _, c := range result.Comments {
+			// modules sharing a rule may return the same comment
+			if !slices.Contains(comments, c) {
+				comments = append(comments, c)
+			}
+		}
+		if result.Review.Type == types.Approve && result.Merge != nil {
+			merges = append(merges, result.Merge)
+		}
 		if result.Review.Type >= coalesced.Review.Type {
 			// higher priority review
 			coalesced = result
 		}
 	}
+	if coalesced.Review.Type == types.Approve {
+		// every approving module has a say in how the PR is merged
+		coalesced.Merge = coalesceMerge(merges)
+	}
+	// labels of every tracked module are applied, not only the labels of the module deciding the review
+	coalesced.Labels = coalesceLabels(labels)
+	coalesced.Reviewers = coalesceReviewers(reviewers)
+	coalesced.Comments = comments
 
 	report.SetOutcome(evaluation.Result{
 		Result: coalesced,
 		Err:    err,
 	})
 	oplog.Info().Interface("coalesced result", coalesced).Msg("coalesced policy evaluation result")
+	e.emitShadowResults(ctx, ghe, coalesced, shadows)
 	return coalesced, nil
 }
 
+// evaluateModules evaluates the modules marked in evaluate concurrently, with at most cfg.Workers at a time.
+// Modules which abort the evaluation on errors stop modules after them from being started.
+// returns the results in module order.
+func (e *evaluator) evaluateModules(ctx context.Context, ghe input.GHE, bundle *Bundle,
+	model *input.Model, evaluate, abortOnError []bool) []moduleResult {
+	results := make([]moduleResult, len(bundle.Modules))
+	indexes := make(chan int)
+	var aborted atomic.Bool
+	var wg sync.WaitGroup
+	for w := 0; w < max(e.cfg.Workers, 1); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if aborted.Load() {
+					// evaluation already failed, modules after the failed module are not started
+					results[i] = moduleResult{notEvaluated: true}
+					continue
+				}
+				results[i] = e.evaluateModule(ctx, ghe, bundle, bundle.Modules[i], model)
+				if results[i].err != nil && abortOnError[i] {
+					aborted.Store(true)
+				}
+			}
+		}()
+	}
+	for i := range bundle.Modules {
+		if evaluate[i] {
+			indexes <- i
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
+
+// toReport returns the result of the module as recorded in the report.
+func (r moduleResult) toReport(shadow bool) evaluation.Result {
+	if r.notEvaluated {
+		return evaluation.Result{Err: ErrNotEvaluated, Shadow: shadow}
+	}
+	return evaluation.Result{
+		Result:      r.result,
+		Err:         r.err,
+		Shadow:      shadow,
+		CircuitOpen: r.circuitOpen,
+		LatencyMS:   r.latency.Milliseconds(),
+	}
+}
+
+// evaluateModule evaluates the module within cfg.ModuleTimeout, unless its circuit is open.
+func (e *evaluator) evaluateModule(ctx context.Context, ghe input.GHE, bundle *Bundle, module string,
+	model *input.Model) moduleResult {
+	if !e.breaker.Allow(module) {
+		return moduleResult{err: ErrCircuitOpen, circuitOpen: true}
+	}
+	moduleCtx := ctx
+	if e.cfg.ModuleTimeout > 0 {
+		var cancel context.CancelFunc
+		moduleCtx, cancel = context.WithTimeout(ctx, e.cfg.ModuleTimeout)
+		defer cancel()
+	}
+	start := e.clock.Now()
+	result, err := bundle.Policy.Evaluate(moduleCtx, module, model)
+	latency := e.clock.Since(start)
+	if err != nil && errors.Is(moduleCtx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("%w after %v: %w", ErrModuleTimeout, e.cfg.ModuleTimeout, err)
+	}
+	e.breaker.Record(ctx, module, err)
+	e.metrics.EmitDist(ctx, "opa.module.latency", float64(latency.Milliseconds()), []string{
+		fmt.Sprintf("module:%s", module),
+		fmt.Sprintf("repoFullName:%s", ghe.Repository.GetFullName()),
+		fmt.Sprintf("error:%t", err != nil),
+	})
+	return moduleResult{result: result, err: err, latency: latency}
+}
+
+// coalesceMerge returns the merge method of the first module choosing one,
+// auto merge is disabled if any module disables it.
+func coalesceMerge(merges []*types.Merge) *types.Merge {
+	if len(merges) == 0 {
+		return nil
+	}
+	coalesced := &types.Merge{}
+	for _, m := range merges {
+		if coalesced.Method == "" {
+			coalesced.Method = m.Method
+		}
+		if m.AutoMerge != nil && (coalesced.AutoMerge == nil || !*m.AutoMerge) {
+			coalesced.AutoMerge = m.AutoMerge
+		}
+	}
+	return coalesced
+}
+
+// coalesceLabels returns the union of the labels of all modules,
+// a label added by any module is not removed.
+func coalesceLabels(labels []*types.Labels) *types.Labels {
+	if len(labels) == 0 {
+		return nil
+	}
+	coalesced := &types.Labels{}
+	for _, l := range labels {
+		for _, label := range l.Add {
+			if !slices.Contains(coalesced.Add, label) {
+				coalesced.Add = append(coalesced.Add, label)
+			}
+		}
+	}
+	for _, l := range labels {
+		for _, label := range l.Remove {
+			if !slices.Contains(coalesced.Add, label) && !slices.Contains(coalesced.Remove, label) {
+				coalesced.Remove = append(coalesced.Remove, label)
+			}
+		}
+	}
+	return coalesced
+}
+
+// coalesceReviewers returns the union of the reviewers of all modules.
+func coalesceReviewers(reviewers []*types.Reviewers) *types.Reviewers {
+	if len(reviewers) == 0 {
+		return nil
+	}
+	coalesced := &types.Reviewers{}
+	for _, r := range reviewers {
+		for _, u := range r.Users {
+			if !slices.Contains(coalesced.Users, u) {
+				coalesced.Users = append(coalesced.Users, u)
+			}
+		}
+		for _, t := range r.Teams {
+			if !slices.Contains(coalesced.Teams, t) {
+				coalesced.Teams = append(coalesced.Teams, t)
+			}
+		}
+	}
+	return coalesced
+}
+
+// failedResult returns the result of a module which failed to evaluate.
+// fail closed modules are tracked with a review of the failure mode,
+// fail open modules are not tracked.
+func failedResult(module string, failure FailureMode) types.Result {
+	switch failure {
+	case FailRequestChanges:
+		return types.Result{
+			Track:  true,
+			Review: types.Review{Type: types.RequestChanges, Body: fmt.Sprintf(FailClosedMsg, module)},
+		}
+	case FailComment:
+		return types.Result{
+			Track:  true,
+			Review: types.Review{Type: types.Comment, Body: fmt.Sprintf(FailClosedMsg, module)},
+		}
+	default:
+		return types.Result{}
+	}
+}
+
+func (e *evaluator) emitModuleError(ctx context.Context, ghe input.GHE, module string, failure FailureMode) {
+	e.metrics.EmitDist(ctx, "opa.module.error", 1, []string{
+		fmt.Sprintf("module:%s", module),
+		fmt.Sprintf("repoFullName:%s", ghe.Repository.GetFullName()),
+		fmt.Sprintf("failure:%s", failure),
+	})
+}
+
+// emitShadowResults emits whether enforcing the shadow modules would have changed the coalesced result.
+func (e *evaluator) emitShadowResults(ctx context.Context, ghe input.GHE, coalesced types.Result, shadows []shadowResult) {
+	oplog := httplog.LogEntry(ctx)
+	for _, s := range shadows {
+		// an enforced module failing fails the entire evaluation
+		changed := s.err != nil || (s.result.Track && s.result.Review.Type > coalesced.Review.Type)
+		shadowType := s.result.Review.Type.String()
+		if s.err != nil {
+			shadowType = "ERROR"
+		}
+		if changed {
+			oplog.Info().Msgf("shadow module %s would have changed the result from %v to %v",
+				s.module, coalesced.Review.Type, shadowType)
+		}
+		e.metrics.EmitDist(ctx, "opa.shadow.evaluated", 1, []string{
+			fmt.Sprintf("module:%s", s.module),
+			fmt.Sprintf("repoFullName:%s", ghe.Repository.GetFullName()),
+			fmt.Sprintf("changed:%t", changed),
+			fmt.Sprintf("outcome:%s", coalesced.Review.Type),
+			fmt.Sprintf("shadow:%s", shadowType),
+		})
+	}
+}
+
 func (e *evaluator) newReportBuilder(ctx context.Context, ghe input.GHE) evaluation.ReportBuilder {
 	reqID := middleware.GetReqID(ctx)
 	deliveryID := evaluation.GetDeliveryID(ctx)
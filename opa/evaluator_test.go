@@ -2,15 +2,23 @@ package opa_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/jonboulle/clockwork"
+	"github.com/marqeta/pr-bot/configstore"
+	"github.com/marqeta/pr-bot/metrics"
 	"github.com/marqeta/pr-bot/opa"
 	"github.com/marqeta/pr-bot/opa/evaluation"
 	"github.com/marqeta/pr-bot/opa/input"
 	"github.com/marqeta/pr-bot/opa/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func Test_evaluator_Evaluate(t *testing.T) {
@@ -240,6 +248,9 @@ func Test_evaluator_Evaluate(t *testing.T) {
 				p.EXPECT().Evaluate(ctx, "m1", randomModel()).Return(notTrack(), randomErr)
 				b.EXPECT().AddModuleResult("m1", evalResult(notTrack(), randomErr))
 				b.EXPECT().SetOutcome(evalResult(notTrack(), randomErr))
+				b.EXPECT().AddModuleResult("m2", evalResult(types.Result{}, opa.ErrNotEvaluated))
+				b.EXPECT().AddModuleResult("m3", evalResult(types.Result{}, opa.ErrNotEvaluated))
+				b.EXPECT().AddModuleResult("m4", evalResult(types.Result{}, opa.ErrNotEvaluated))
 				m.EXPECT().StoreReport(ctx, b).Return(nil)
 			},
 			want:    notTrack(),
@@ -267,10 +278,65 @@ func Test_evaluator_Evaluate(t *testing.T) {
 	}
 }
 
-func evalResult(r types.Result, e error) evaluation.Result {
-	return evaluation.Result{
+func Test_evaluator_Evaluate_SkippedModules(t *testing.T) {
+	ctx := context.WithValue(context.TODO(), middleware.RequestIDKey, "request_id")
+	ctx = context.WithValue(ctx, evaluation.DeliveryIDKey, "delivery_id")
+	ctx = evaluation.SetSkippedModules(ctx, []string{"m2"})
+
+	f := input.NewMockFactory(t)
+	p := opa.NewMockPolicy(t)
+	m := evaluation.NewMockManager(t)
+	b := evaluation.NewMockReportBuilder(t)
+
+	f.EXPECT().CreateModel(ctx, randomGHE()).Return(randomModel(), nil)
+	m.EXPECT().NewReportBuilder(ctx, "ci/terraform-provider-oci/259", "request_id", "delivery_id").Return(b)
+	b.EXPECT().SetInput(randomModel())
+	p.EXPECT().Evaluate(ctx, "m1", randomModel()).Return(approve(), nil)
+	b.EXPECT().AddModuleResult("m1", evalResult(approve(), nil))
+	b.EXPECT().AddModuleResult("m2", evalResult(types.Result{
+		Review: types.Review{Type: types.Skip, Body: opa.SkippedModuleMsg},
+	}, nil))
+	b.EXPECT().SetOutcome(evalResult(approve(), nil))
+	m.EXPECT().StoreReport(ctx, b).Return(nil)
+
+	e := opa.NewEvaluator([]string{"m1", "m2"}, p, f, m)
+	got, err := e.Evaluate(ctx, randomGHE())
+	if err != nil {
+		t.Errorf("evaluator.Evaluate() error = %v", err)
+		return
+	}
+	if !reflect.DeepEqual(got, approve()) {
+		t.Errorf("evaluator.Evaluate() = %v, want %v", got, approve())
+	}
+}
+
+func evalResult(r types.Result, e error) any {
+	return withoutLatency(evaluation.Result{
 		Result: r,
 		Err:    e,
+	})
+}
+
+func shadowResult(r types.Result, e error) any {
+	return withoutLatency(evaluation.Result{
+		Result: r,
+		Err:    e,
+		Shadow: true,
+	})
+}
+
+// withoutLatency matches results regardless of their latency, which is measured with the real clock.
+func withoutLatency(want evaluation.Result) any {
+	return mock.MatchedBy(func(got evaluation.Result) bool {
+		got.LatencyMS = 0
+		return reflect.DeepEqual(want, got)
+	})
+}
+
+func failedClosed(module string, reviewType types.ReviewType) types.Result {
+	return types.Result{
+		Track:  true,
+		Review: types.Review{Type: reviewType, Body: fmt.Sprintf(opa.FailClosedMsg, module)},
 	}
 }
 
@@ -316,3 +382,370 @@ func skip() types.Result {
 func notTrack() types.Result {
 	return types.Result{}
 }
+
+func Test_evaluator_Evaluate_ReloadedBundle(t *testing.T) {
+	ctx := context.WithValue(context.TODO(), middleware.RequestIDKey, "request_id")
+	ctx = context.WithValue(ctx, evaluation.DeliveryIDKey, "delivery_id")
+
+	f := input.NewMockFactory(t)
+	p1 := opa.NewMockPolicy(t)
+	p2 := opa.NewMockPolicy(t)
+	m := evaluation.NewMockManager(t)
+	b := evaluation.NewMockReportBuilder(t)
+
+	holder := opa.NewBundleHolder(&opa.Bundle{Version: "v1", Modules: []string{"m1"}, Policy: p1})
+	modes, err := configstore.NewInMemoryStore(&opa.ModuleCfg{})
+	assert.Nil(t, err)
+	e := opa.NewReloadableEvaluator(holder, modes, opa.NewNoopCircuitBreaker(), f, m,
+		opa.EvaluatorConfig{Workers: 2}, clockwork.NewFakeClock(), metrics.NewNoopEmitter())
+	holder.Swap(&opa.Bundle{Version: "v2", Modules: []string{"m2"}, Policy: p2})
+
+	f.EXPECT().CreateModel(ctx, randomGHE()).Return(randomModel(), nil)
+	m.EXPECT().NewReportBuilder(ctx, "ci/terraform-provider-oci/259", "request_id", "delivery_id").Return(b)
+	b.EXPECT().SetPolicyVersion("v2")
+	b.EXPECT().SetInput(randomModel())
+	p2.EXPECT().Evaluate(ctx, "m2", randomModel()).Return(approve(), nil)
+	b.EXPECT().AddModuleResult("m2", evalResult(approve(), nil))
+	b.EXPECT().SetOutcome(evalResult(approve(), nil))
+	m.EXPECT().StoreReport(ctx, b).Return(nil)
+
+	got, err := e.Evaluate(ctx, randomGHE())
+	assert.Nil(t, err)
+	assert.Equal(t, approve(), got)
+}
+
+func Test_evaluator_Evaluate_Merge(t *testing.T) {
+	ctx := context.WithValue(context.TODO(), middleware.RequestIDKey, "request_id")
+	ctx = context.WithValue(ctx, evaluation.DeliveryIDKey, "delivery_id")
+
+	f := input.NewMockFactory(t)
+	p := opa.NewMockPolicy(t)
+	m := evaluation.NewMockManager(t)
+	b := evaluation.NewMockReportBuilder(t)
+	e := opa.NewEvaluator([]string{"m1", "m2", "m3"}, p, f, m)
+
+	rebase := approve()
+	rebase.Merge = &types.Merge{Method: types.MergeMethodRebase}
+	approveOnly := approve()
+	approveOnly.Merge = &types.Merge{Method: types.MergeMethodSquash, AutoMerge: aws.Bool(false)}
+	want := approve()
+	want.Merge = &types.Merge{Method: types.MergeMethodRebase, AutoMerge: aws.Bool(false)}
+
+	f.EXPECT().CreateModel(ctx, randomGHE()).Return(randomModel(), nil)
+	m.EXPECT().NewReportBuilder(ctx, "ci/terraform-provider-oci/259", "request_id", "delivery_id").Return(b)
+	b.EXPECT().SetInput(randomModel())
+	p.EXPECT().Evaluate(ctx, "m1", randomModel()).Return(rebase, nil)
+	b.EXPECT().AddModuleResult("m1", evalResult(rebase, nil))
+	p.EXPECT().Evaluate(ctx, "m2", randomModel()).Return(approveOnly, nil)
+	b.EXPECT().AddModuleResult("m2", evalResult(approveOnly, nil))
+	p.EXPECT().Evaluate(ctx, "m3", randomModel()).Return(approve(), nil)
+	b.EXPECT().AddModuleResult("m3", evalResult(approve(), nil))
+	b.EXPECT().SetOutcome(evalResult(want, nil))
+	m.EXPECT().StoreReport(ctx, b).Return(nil)
+
+	got, err := e.Evaluate(ctx, randomGHE())
+	assert.Nil(t, err)
+	assert.Equal(t, want, got)
+}
+
+func Test_evaluator_Evaluate_LabelsReviewersAndComments(t *testing.T) {
+	ctx := context.WithValue(context.TODO(), middleware.RequestIDKey, "request_id")
+	ctx = context.WithValue(ctx, evaluation.DeliveryIDKey, "delivery_id")
+
+	f := input.NewMockFactory(t)
+	p := opa.NewMockPolicy(t)
+	m := evaluation.NewMockManager(t)
+	b := evaluation.NewMockReportBuilder(t)
+	e := opa.NewEvaluator([]string{"m1", "m2", "m3"}, p, f, m)
+
+	m1 := comment()
+	m1.Labels = &types.Labels{Add: []string{"size/XL"}, Remove: []string{"auto-approved", "needs-review"}}
+	m2 := approve()
+	m2.Labels = &types.Labels{Add: []string{"auto-approved", "size/XL"}}
+	m1.Reviewers = &types.Reviewers{Users: []string{"alice"}}
+	m2.Reviewers = &types.Reviewers{Users: []string{"bob", "alice"}, Teams: []string{"infra"}}
+	m3 := skip()
+	m3.Labels = &types.Labels{Remove: []string{"stale"}}
+	m3.Reviewers = &types.Reviewers{Teams: []string{"infra", "security"}}
+	want := comment()
+	want.Labels = &types.Labels{Add: []string{"size/XL", "auto-approved"}, Remove: []string{"needs-review", "stale"}}
+	m1.Comments = []types.InlineComment{{Path: "main.tf", Line: 1, Side: types.Right, Severity: types.Notice, Message: "m1"}}
+	m3.Comments = []types.InlineComment{
+		{Path: "main.tf", Line: 1, Side: types.Right, Severity: types.Notice, Message: "m1"},
+		{Path: "main.tf", Line: 2, Side: types.Left, Severity: types.Failure, Message: "m3"},
+	}
+	want.Reviewers = &types.Reviewers{Users: []string{"alice", "bob"}, Teams: []string{"infra", "security"}}
+	want.Comments = []types.InlineComment{
+		{Path: "main.tf", Line: 1, Side: types.Right, Severity: types.Notice, Message: "m1"},
+		{Path: "main.tf", Line: 2, Side: types.Left, Severity: types.Failure, Message: "m3"},
+	}
+
+	f.EXPECT().CreateModel(ctx, randomGHE()).Return(randomModel(), nil)
+	m.EXPECT().NewReportBuilder(ctx, "ci/terraform-provider-oci/259", "request_id", "delivery_id").Return(b)
+	b.EXPECT().SetInput(randomModel())
+	p.EXPECT().Evaluate(ctx, "m1", randomModel()).Return(m1, nil)
+	b.EXPECT().AddModuleResult("m1", evalResult(m1, nil))
+	p.EXPECT().Evaluate(ctx, "m2", randomModel()).Return(m2, nil)
+	b.EXPECT().AddModuleResult("m2", evalResult(m2, nil))
+	p.EXPECT().Evaluate(ctx, "m3", randomModel()).Return(m3, nil)
+	b.EXPECT().AddModuleResult("m3", evalResult(m3, nil))
+	b.EXPECT().SetOutcome(evalResult(want, nil))
+	m.EXPECT().StoreReport(ctx, b).Return(nil)
+
+	got, err := e.Evaluate(ctx, randomGHE())
+	assert.Nil(t, err)
+	assert.Equal(t, want, got)
+}
+
+func Test_evaluator_Evaluate_Concurrently(t *testing.T) {
+	ctx := context.WithValue(context.TODO(), middleware.RequestIDKey, "request_id")
+	ctx = context.WithValue(ctx, evaluation.DeliveryIDKey, "delivery_id")
+
+	f := input.NewMockFactory(t)
+	p := opa.NewMockPolicy(t)
+	m := evaluation.NewMockManager(t)
+	b := evaluation.NewMockReportBuilder(t)
+
+	holder := opa.NewBundleHolder(&opa.Bundle{Modules: []string{"m1", "m2", "m3"}, Policy: p})
+	modes, err := configstore.NewInMemoryStore(&opa.ModuleCfg{})
+	assert.Nil(t, err)
+	e := opa.NewReloadableEvaluator(holder, modes, opa.NewNoopCircuitBreaker(), f, m,
+		opa.EvaluatorConfig{Workers: 3}, clockwork.NewFakeClock(), metrics.NewNoopEmitter())
+
+	lastComment := types.Result{Track: true, Review: types.Review{Type: types.Comment, Body: "m3 comment"}}
+	m3Done := make(chan struct{})
+	f.EXPECT().CreateModel(ctx, randomGHE()).Return(randomModel(), nil)
+	m.EXPECT().NewReportBuilder(ctx, "ci/terraform-provider-oci/259", "request_id", "delivery_id").Return(b)
+	b.EXPECT().SetInput(randomModel())
+	// m1 finishes last, the result is still coalesced in module order
+	p.EXPECT().Evaluate(ctx, "m1", randomModel()).RunAndReturn(
+		func(_ context.Context, _ string, _ *input.Model) (types.Result, error) {
+			<-m3Done
+			return comment(), nil
+		})
+	p.EXPECT().Evaluate(ctx, "m2", randomModel()).Return(approve(), nil)
+	p.EXPECT().Evaluate(ctx, "m3", randomModel()).RunAndReturn(
+		func(_ context.Context, _ string, _ *input.Model) (types.Result, error) {
+			close(m3Done)
+			return lastComment, nil
+		})
+	c1 := b.EXPECT().AddModuleResult("m1", evalResult(comment(), nil)).Call
+	c2 := b.EXPECT().AddModuleResult("m2", evalResult(approve(), nil)).Call.NotBefore(c1)
+	b.EXPECT().AddModuleResult("m3", evalResult(lastComment, nil)).Call.NotBefore(c2)
+	b.EXPECT().SetOutcome(evalResult(lastComment, nil))
+	m.EXPECT().StoreReport(ctx, b).Return(nil)
+
+	got, err := e.Evaluate(ctx, randomGHE())
+	assert.Nil(t, err)
+	assert.Equal(t, lastComment, got)
+}
+
+func Test_evaluator_Evaluate_Aborted(t *testing.T) {
+	ctx := context.WithValue(context.TODO(), middleware.RequestIDKey, "request_id")
+	ctx = context.WithValue(ctx, evaluation.DeliveryIDKey, "delivery_id")
+	//nolint:goerr113
+	randomErr := fmt.Errorf("random error")
+
+	f := input.NewMockFactory(t)
+	p := opa.NewMockPolicy(t)
+	m := evaluation.NewMockManager(t)
+	b := evaluation.NewMockReportBuilder(t)
+
+	holder := opa.NewBundleHolder(&opa.Bundle{Modules: []string{"m1", "m2", "m3"}, Policy: p})
+	modes, err := configstore.NewInMemoryStore(&opa.ModuleCfg{Modes: map[string]string{"m2": "shadow"}})
+	assert.Nil(t, err)
+	e := opa.NewReloadableEvaluator(holder, modes, opa.NewNoopCircuitBreaker(), f, m,
+		opa.EvaluatorConfig{Workers: 1}, clockwork.NewFakeClock(), metrics.NewNoopEmitter())
+
+	f.EXPECT().CreateModel(ctx, randomGHE()).Return(randomModel(), nil)
+	m.EXPECT().NewReportBuilder(ctx, "ci/terraform-provider-oci/259", "request_id", "delivery_id").Return(b)
+	b.EXPECT().SetInput(randomModel())
+	p.EXPECT().Evaluate(ctx, "m1", randomModel()).Return(types.Result{}, randomErr)
+	b.EXPECT().AddModuleResult("m1", evalResult(types.Result{}, randomErr))
+	b.EXPECT().SetOutcome(evalResult(types.Result{}, randomErr))
+	// modules after the failed module are marked as not evaluated
+	b.EXPECT().AddModuleResult("m2", evaluation.Result{Err: opa.ErrNotEvaluated, Shadow: true})
+	b.EXPECT().AddModuleResult("m3", evalResult(types.Result{}, opa.ErrNotEvaluated))
+	m.EXPECT().StoreReport(ctx, b).Return(nil)
+
+	_, err = e.Evaluate(ctx, randomGHE())
+	assert.ErrorIs(t, err, randomErr)
+}
+
+func Test_evaluator_Evaluate_ModuleTimeout(t *testing.T) {
+	ctx := context.WithValue(context.TODO(), middleware.RequestIDKey, "request_id")
+	ctx = context.WithValue(ctx, evaluation.DeliveryIDKey, "delivery_id")
+
+	f := input.NewMockFactory(t)
+	p := opa.NewMockPolicy(t)
+	m := evaluation.NewMockManager(t)
+	b := evaluation.NewMockReportBuilder(t)
+
+	holder := opa.NewBundleHolder(&opa.Bundle{Modules: []string{"m1", "m2"}, Policy: p})
+	modes, err := configstore.NewInMemoryStore(&opa.ModuleCfg{Failures: map[string]string{"m1": "comment"}})
+	assert.Nil(t, err)
+	e := opa.NewReloadableEvaluator(holder, modes, opa.NewNoopCircuitBreaker(), f, m,
+		opa.EvaluatorConfig{Workers: 2, ModuleTimeout: 10 * time.Millisecond},
+		clockwork.NewFakeClock(), metrics.NewNoopEmitter())
+
+	f.EXPECT().CreateModel(ctx, randomGHE()).Return(randomModel(), nil)
+	m.EXPECT().NewReportBuilder(ctx, "ci/terraform-provider-oci/259", "request_id", "delivery_id").Return(b)
+	b.EXPECT().SetInput(randomModel())
+	p.EXPECT().Evaluate(mock.Anything, "m1", randomModel()).RunAndReturn(
+		func(ctx context.Context, _ string, _ *input.Model) (types.Result, error) {
+			<-ctx.Done()
+			return types.Result{}, ctx.Err()
+		})
+	p.EXPECT().Evaluate(mock.Anything, "m2", randomModel()).Return(approve(), nil)
+	b.EXPECT().AddModuleResult("m1", mock.MatchedBy(func(r evaluation.Result) bool {
+		return errors.Is(r.Err, opa.ErrModuleTimeout) && errors.Is(r.Err, context.DeadlineExceeded) &&
+			r.Failure == "comment" && reflect.DeepEqual(r.Result, failedClosed("m1", types.Comment))
+	}))
+	b.EXPECT().AddModuleResult("m2", evalResult(approve(), nil))
+	b.EXPECT().SetOutcome(evalResult(failedClosed("m1", types.Comment), nil))
+	m.EXPECT().StoreReport(ctx, b).Return(nil)
+
+	got, err := e.Evaluate(ctx, randomGHE())
+	assert.Nil(t, err)
+	assert.Equal(t, failedClosed("m1", types.Comment), got)
+}
+
+func Test_evaluator_Evaluate_ModuleModes(t *testing.T) {
+	ctx := context.WithValue(context.TODO(), middleware.RequestIDKey, "request_id")
+	ctx = context.WithValue(ctx, evaluation.DeliveryIDKey, "delivery_id")
+	//nolint:goerr113
+	randomErr := fmt.Errorf("random error")
+	tests := []struct {
+		name            string
+		modes           map[string]string
+		failures        map[string]string
+		setExpectations func(p *opa.MockPolicy, b *evaluation.MockReportBuilder)
+		want            types.Result
+	}{
+		{
+			name:  "should not coalesce result of shadow module",
+			modes: map[string]string{"m2": "shadow"},
+			setExpectations: func(p *opa.MockPolicy, b *evaluation.MockReportBuilder) {
+				p.EXPECT().Evaluate(ctx, "m1", randomModel()).Return(approve(), nil)
+				b.EXPECT().AddModuleResult("m1", evalResult(approve(), nil))
+				p.EXPECT().Evaluate(ctx, "m2", randomModel()).Return(reqChanges(), nil)
+				b.EXPECT().AddModuleResult("m2", shadowResult(reqChanges(), nil))
+				b.EXPECT().SetOutcome(evalResult(approve(), nil))
+			},
+			want: approve(),
+		},
+		{
+			name:  "should not fail evaluation when shadow module fails",
+			modes: map[string]string{"m1": "Shadow"},
+			setExpectations: func(p *opa.MockPolicy, b *evaluation.MockReportBuilder) {
+				p.EXPECT().Evaluate(ctx, "m1", randomModel()).Return(types.Result{}, randomErr)
+				b.EXPECT().AddModuleResult("m1", shadowResult(types.Result{}, randomErr))
+				p.EXPECT().Evaluate(ctx, "m2", randomModel()).Return(comment(), nil)
+				b.EXPECT().AddModuleResult("m2", evalResult(comment(), nil))
+				b.EXPECT().SetOutcome(evalResult(comment(), nil))
+			},
+			want: comment(),
+		},
+		{
+			name:     "should ignore error of fail open module",
+			modes:    map[string]string{},
+			failures: map[string]string{"m1": "open"},
+			setExpectations: func(p *opa.MockPolicy, b *evaluation.MockReportBuilder) {
+				p.EXPECT().Evaluate(ctx, "m1", randomModel()).Return(types.Result{}, randomErr)
+				b.EXPECT().AddModuleResult("m1", evaluation.Result{Err: randomErr, Failure: "open"})
+				p.EXPECT().Evaluate(ctx, "m2", randomModel()).Return(approve(), nil)
+				b.EXPECT().AddModuleResult("m2", evalResult(approve(), nil))
+				b.EXPECT().SetOutcome(evalResult(approve(), nil))
+			},
+			want: approve(),
+		},
+		{
+			name:     "should request changes when fail closed module fails",
+			modes:    map[string]string{},
+			failures: map[string]string{"m1": "request_changes"},
+			setExpectations: func(p *opa.MockPolicy, b *evaluation.MockReportBuilder) {
+				p.EXPECT().Evaluate(ctx, "m1", randomModel()).Return(types.Result{}, randomErr)
+				b.EXPECT().AddModuleResult("m1", evaluation.Result{
+					Result:  failedClosed("m1", types.RequestChanges),
+					Err:     randomErr,
+					Failure: "request_changes",
+				})
+				p.EXPECT().Evaluate(ctx, "m2", randomModel()).Return(approve(), nil)
+				b.EXPECT().AddModuleResult("m2", evalResult(approve(), nil))
+				b.EXPECT().SetOutcome(evalResult(failedClosed("m1", types.RequestChanges), nil))
+			},
+			want: failedClosed("m1", types.RequestChanges),
+		},
+		{
+			name:     "should comment when fail closed module fails",
+			modes:    map[string]string{},
+			failures: map[string]string{"m2": "comment"},
+			setExpectations: func(p *opa.MockPolicy, b *evaluation.MockReportBuilder) {
+				p.EXPECT().Evaluate(ctx, "m1", randomModel()).Return(approve(), nil)
+				b.EXPECT().AddModuleResult("m1", evalResult(approve(), nil))
+				p.EXPECT().Evaluate(ctx, "m2", randomModel()).Return(types.Result{}, randomErr)
+				b.EXPECT().AddModuleResult("m2", evaluation.Result{
+					Result:  failedClosed("m2", types.Comment),
+					Err:     randomErr,
+					Failure: "comment",
+				})
+				b.EXPECT().SetOutcome(evalResult(failedClosed("m2", types.Comment), nil))
+			},
+			want: failedClosed("m2", types.Comment),
+		},
+		{
+			name:  "should not evaluate disabled module",
+			modes: map[string]string{"m1": "disabled", "m2": "enforce"},
+			setExpectations: func(p *opa.MockPolicy, b *evaluation.MockReportBuilder) {
+				p.EXPECT().Evaluate(ctx, "m2", randomModel()).Return(comment(), nil)
+				b.EXPECT().AddModuleResult("m2", evalResult(comment(), nil))
+				b.EXPECT().SetOutcome(evalResult(comment(), nil))
+			},
+			want: comment(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := input.NewMockFactory(t)
+			p := opa.NewMockPolicy(t)
+			m := evaluation.NewMockManager(t)
+			b := evaluation.NewMockReportBuilder(t)
+
+			modes, err := configstore.NewInMemoryStore(&opa.ModuleCfg{Modes: tt.modes, Failures: tt.failures})
+			assert.Nil(t, err)
+			holder := opa.NewBundleHolder(&opa.Bundle{Modules: []string{"m1", "m2"}, Policy: p})
+			e := opa.NewReloadableEvaluator(holder, modes, opa.NewNoopCircuitBreaker(), f, m,
+				opa.EvaluatorConfig{Workers: 2}, clockwork.NewFakeClock(), metrics.NewNoopEmitter())
+
+			f.EXPECT().CreateModel(ctx, randomGHE()).Return(randomModel(), nil)
+			m.EXPECT().NewReportBuilder(ctx, "ci/terraform-provider-oci/259", "request_id", "delivery_id").Return(b)
+			b.EXPECT().SetInput(randomModel())
+			tt.setExpectations(p, b)
+			m.EXPECT().StoreReport(ctx, b).Return(nil)
+
+			got, err := e.Evaluate(ctx, randomGHE())
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestModuleCfg_Update(t *testing.T) {
+	cfg := &opa.ModuleCfg{Modes: map[string]string{"m1": " SHADOW ", "m2": "disabled"}}
+	assert.Nil(t, cfg.Update())
+	assert.Equal(t, opa.Shadow, cfg.Mode("m1"))
+	assert.Equal(t, opa.Disabled, cfg.Mode("m2"))
+	assert.Equal(t, opa.Enforce, cfg.Mode("m3"))
+
+	cfg = &opa.ModuleCfg{Modes: map[string]string{"m1": "audit"}}
+	assert.ErrorIs(t, cfg.Update(), opa.ErrInvalidModuleMode)
+
+	cfg = &opa.ModuleCfg{Failures: map[string]string{"m1": "Open", "m2": "comment"}}
+	assert.Nil(t, cfg.Update())
+	assert.Equal(t, opa.FailOpen, cfg.Failure("m1"))
+	assert.Equal(t, opa.FailComment, cfg.Failure("m2"))
+	assert.Equal(t, opa.FailAbort, cfg.Failure("m3"))
+
+	cfg = &opa.ModuleCfg{Failures: map[string]string{"m1": "closed"}}
+	assert.ErrorIs(t, cfg.Update(), opa.ErrInvalidFailureMode)
+}
@@ -3,6 +3,7 @@ package input
 import (
 	"context"
 	"encoding/json"
+	"errors"
 
 	"github.com/go-chi/httplog"
 )
@@ -29,16 +30,28 @@ func (f *factory) CreateModel(ctx context.Context, ghe GHE) (*Model, error) {
 		Plugins:      make(map[string]json.RawMessage),
 	}
 
+	truncated := make(map[string]bool)
 	for _, plugin := range f.plugins {
 		name := plugin.Name()
 		msg, err := plugin.GetInputMsg(ctx, ghe)
-		if err != nil {
+		switch {
+		case errors.Is(err, ErrTruncated):
+			oplog.Warn().Err(err).Msgf("message for plugin %s is truncated", name)
+			truncated[name] = true
+		case err != nil:
 			// TODO add metric
 			oplog.Err(err).Msgf("failed to get message for plugin %s", name)
 			continue
 		}
 		model.Plugins[name] = msg
 	}
+	if len(truncated) > 0 {
+		data, err := json.Marshal(truncated)
+		if err != nil {
+			return nil, err
+		}
+		model.Plugins[TruncatedKey] = data
+	}
 
 	return &model, nil
 }
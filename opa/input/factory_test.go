@@ -92,6 +92,26 @@ func Test_factory_CreateModel(t *testing.T) {
 			),
 			wantErr: false,
 		},
+		{
+			name:       "Should add truncated msg from plugins and list them as truncated",
+			ghe:        randomGHE(),
+			numPlugins: 2,
+			setExpectations: func(plugins []*input.MockPlugin) {
+				plugins[0].EXPECT().GetInputMsg(ctx, randomGHE()).
+					Return(JSONRaw("random message 0"), fmt.Errorf("%w: 1 of 2 files", input.ErrTruncated))
+				plugins[0].EXPECT().Name().Return("name0")
+				plugins[1].EXPECT().GetInputMsg(ctx, randomGHE()).
+					Return(JSONRaw("random message 1"), nil)
+				plugins[1].EXPECT().Name().Return("name1")
+			},
+			want: toModel(randomGHE(),
+				map[string]json.RawMessage{
+					"name0":     JSONRaw("random message 0"),
+					"name1":     JSONRaw("random message 1"),
+					"truncated": json.RawMessage(`{"name0":true}`)},
+			),
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
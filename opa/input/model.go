@@ -33,5 +33,6 @@ func (ghe GHE) ToID() id.PR {
 		NodeID:       aws.ToString(ghe.PullRequest.NodeID),
 		RepoFullName: aws.ToString(ghe.Repository.FullName),
 		Author:       aws.ToString(ghe.PullRequest.User.Login),
+		HeadSHA:      ghe.PullRequest.GetHead().GetSHA(),
 	}
 }
@@ -3,8 +3,20 @@ package input
 import (
 	"context"
 	"encoding/json"
+	"errors"
 )
 
+// TruncatedKey is the key of the plugins built from partial data in the plugins of the input,
+// e.g. input.plugins.truncated.files_changed is true when some files changed are left out.
+const TruncatedKey = "truncated"
+
+// ErrTruncated is returned by plugins with a message built from partial data,
+// the message is still added to the input.
+var ErrTruncated = errors.New("plugin input is truncated")
+
+// Plugin adds a message to the input, GetInputMsg returns an error wrapping ErrTruncated
+// along with the message when the message is built from partial data.
+//
 //go:generate mockery --name Plugin --testonly
 type Plugin interface {
 	GetInputMsg(ctx context.Context, ghe GHE) (json.RawMessage, error)
@@ -0,0 +1,107 @@
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/go-github/v50/github"
+	gh "github.com/marqeta/pr-bot/github"
+	"github.com/marqeta/pr-bot/opa/input"
+)
+
+var errNoHeadSHA = errors.New("head sha not found in pull request event payload")
+
+// Checks is the input message of the checks plugin.
+// Rollup maps check run names and status contexts to their conclusion or state,
+// so policies do not need to walk check runs and statuses separately.
+type Checks struct {
+	SHA       string                 `json:"sha"`
+	State     string                 `json:"state"`
+	CheckRuns []*github.CheckRun     `json:"check_runs"`
+	Statuses  []*github.RepoStatus   `json:"statuses"`
+	Rollup    map[string]CheckResult `json:"rollup"`
+}
+
+type CheckResult struct {
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+}
+
+type checks struct {
+	dao gh.API
+	// ignore is the name of the check run published by pr-bot, its previous conclusion is not an input
+	ignore string
+}
+
+// GetInputMsg implements input.Plugin.
+func (c *checks) GetInputMsg(ctx context.Context, ghe input.GHE) (json.RawMessage, error) {
+	id := ghe.ToID()
+	sha := ghe.PullRequest.GetHead().GetSHA()
+	if len(sha) == 0 {
+		return json.RawMessage{}, errNoHeadSHA
+	}
+
+	runs, err := c.dao.ListCheckRunsForRef(ctx, id, sha)
+	listTruncated := errors.Is(err, gh.ErrTruncated)
+	if err != nil && !listTruncated {
+		return json.RawMessage{}, err
+	}
+	combined, err := c.dao.GetCombinedStatus(ctx, id, sha)
+	if err != nil {
+		return json.RawMessage{}, err
+	}
+
+	runs = slices.DeleteFunc(slices.Clone(runs), func(r *github.CheckRun) bool {
+		return c.ignore != "" && r.GetName() == c.ignore
+	})
+
+	msg := Checks{
+		SHA:       sha,
+		State:     combined.GetState(),
+		CheckRuns: runs,
+		Statuses:  combined.Statuses,
+		Rollup:    make(map[string]CheckResult),
+	}
+	for _, s := range combined.Statuses {
+		result := CheckResult{Status: "completed", Conclusion: s.GetState()}
+		if s.GetState() == "pending" {
+			result = CheckResult{Status: "pending"}
+		}
+		msg.Rollup[s.GetContext()] = result
+	}
+	for _, r := range runs {
+		// check runs take precedence over statuses with the same name
+		msg.Rollup[r.GetName()] = CheckResult{
+			Status:     r.GetStatus(),
+			Conclusion: r.GetConclusion(),
+		}
+	}
+	if msg.CheckRuns == nil {
+		msg.CheckRuns = []*github.CheckRun{}
+	}
+	if msg.Statuses == nil {
+		msg.Statuses = []*github.RepoStatus{}
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return json.RawMessage{}, err
+	}
+	if listTruncated {
+		return json.RawMessage(data), fmt.Errorf("%w: %d check runs", input.ErrTruncated, len(runs))
+	}
+	return json.RawMessage(data), nil
+}
+
+// Name implements input.Plugin.
+func (c *checks) Name() string {
+	return "checks"
+}
+
+// NewChecks returns the checks plugin, check runs named ignore are left out of the input.
+func NewChecks(dao gh.API, ignore string) input.Plugin {
+	return &checks{dao: dao, ignore: ignore}
+}
@@ -0,0 +1,206 @@
+package plugins_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/google/go-github/v50/github"
+	gh "github.com/marqeta/pr-bot/github"
+	"github.com/marqeta/pr-bot/opa/input"
+	"github.com/marqeta/pr-bot/opa/input/plugins"
+)
+
+func TestChecks_GetInputMsg(t *testing.T) {
+	ctx := context.TODO()
+	//nolint:goerr113
+	randomErr := errors.New("random error")
+	type args struct {
+		ghe             input.GHE
+		setExpectations func(d *gh.MockAPI)
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    json.RawMessage
+		wantErr bool
+	}{
+		{
+			name: "Should return check runs, statuses and rollup for head sha",
+			args: args{
+				ghe: checksGHE(),
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().ListCheckRunsForRef(ctx, checksGHE().ToID(), "sha1").
+						Return(randomCheckRuns(), nil)
+					d.EXPECT().GetCombinedStatus(ctx, checksGHE().ToID(), "sha1").
+						Return(randomCombinedStatus(), nil)
+				},
+			},
+			want: toJSON(t, plugins.Checks{
+				SHA:       "sha1",
+				State:     "pending",
+				CheckRuns: randomCheckRuns(),
+				Statuses:  randomCombinedStatus().Statuses,
+				Rollup: map[string]plugins.CheckResult{
+					"build":   {Status: "completed", Conclusion: "success"},
+					"lint":    {Status: "in_progress", Conclusion: ""},
+					"deploy":  {Status: "pending", Conclusion: ""},
+					"jenkins": {Status: "completed", Conclusion: "failure"},
+				},
+			}),
+			wantErr: false,
+		},
+		{
+			name: "Should leave out check run of pr-bot",
+			args: args{
+				ghe: checksGHE(),
+				setExpectations: func(d *gh.MockAPI) {
+					runs := append(randomCheckRuns(), &github.CheckRun{Name: aws.String("pr-bot"),
+						Status: aws.String("completed"), Conclusion: aws.String("failure")})
+					d.EXPECT().ListCheckRunsForRef(ctx, checksGHE().ToID(), "sha1").Return(runs, nil)
+					d.EXPECT().GetCombinedStatus(ctx, checksGHE().ToID(), "sha1").
+						Return(&github.CombinedStatus{State: aws.String("success")}, nil)
+				},
+			},
+			want: toJSON(t, plugins.Checks{
+				SHA:       "sha1",
+				State:     "success",
+				CheckRuns: randomCheckRuns(),
+				Statuses:  []*github.RepoStatus{},
+				Rollup: map[string]plugins.CheckResult{
+					"build": {Status: "completed", Conclusion: "success"},
+					"lint":  {Status: "in_progress", Conclusion: ""},
+				},
+			}),
+			wantErr: false,
+		},
+		{
+			name: "Should return empty lists when commit has no checks",
+			args: args{
+				ghe: checksGHE(),
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().ListCheckRunsForRef(ctx, checksGHE().ToID(), "sha1").
+						Return(nil, nil)
+					d.EXPECT().GetCombinedStatus(ctx, checksGHE().ToID(), "sha1").
+						Return(&github.CombinedStatus{State: aws.String("pending")}, nil)
+				},
+			},
+			want: toJSON(t, plugins.Checks{
+				SHA:       "sha1",
+				State:     "pending",
+				CheckRuns: []*github.CheckRun{},
+				Statuses:  []*github.RepoStatus{},
+				Rollup:    map[string]plugins.CheckResult{},
+			}),
+			wantErr: false,
+		},
+		{
+			name: "Should return error when listing check runs fails",
+			args: args{
+				ghe: checksGHE(),
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().ListCheckRunsForRef(ctx, checksGHE().ToID(), "sha1").
+						Return(nil, randomErr)
+				},
+			},
+			want:    json.RawMessage([]byte{}),
+			wantErr: true,
+		},
+		{
+			name: "Should return error when getting combined status fails",
+			args: args{
+				ghe: checksGHE(),
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().ListCheckRunsForRef(ctx, checksGHE().ToID(), "sha1").
+						Return(randomCheckRuns(), nil)
+					d.EXPECT().GetCombinedStatus(ctx, checksGHE().ToID(), "sha1").
+						Return(nil, randomErr)
+				},
+			},
+			want:    json.RawMessage([]byte{}),
+			wantErr: true,
+		},
+		{
+			name: "Should return error when head sha is empty",
+			args: args{
+				ghe:             randomGHE(),
+				setExpectations: func(_ *gh.MockAPI) {},
+			},
+			want:    json.RawMessage([]byte{}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dao := gh.NewMockAPI(t)
+			c := plugins.NewChecks(dao, "pr-bot")
+			tt.args.setExpectations(dao)
+			got, err := c.GetInputMsg(ctx, tt.args.ghe)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Checks.GetInputMsg() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Checks.GetInputMsg() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func checksGHE() input.GHE {
+	ghe := randomGHE()
+	ghe.PullRequest.Head = &github.PullRequestBranch{
+		SHA: aws.String("sha1"),
+	}
+	return ghe
+}
+
+func randomCheckRuns() []*github.CheckRun {
+	return []*github.CheckRun{
+		{
+			Name:       aws.String("build"),
+			Status:     aws.String("completed"),
+			Conclusion: aws.String("success"),
+		},
+		{
+			Name:   aws.String("lint"),
+			Status: aws.String("in_progress"),
+		},
+	}
+}
+
+func randomCombinedStatus() *github.CombinedStatus {
+	return &github.CombinedStatus{
+		State: aws.String("pending"),
+		Statuses: []*github.RepoStatus{
+			{Context: aws.String("deploy"), State: aws.String("pending")},
+			{Context: aws.String("jenkins"), State: aws.String("failure")},
+			{Context: aws.String("build"), State: aws.String("error")},
+		},
+	}
+}
+
+func TestChecks_GetInputMsg_Truncated(t *testing.T) {
+	ctx := context.TODO()
+	dao := gh.NewMockAPI(t)
+	dao.EXPECT().ListCheckRunsForRef(ctx, checksGHE().ToID(), "sha1").
+		Return(randomCheckRuns(), fmt.Errorf("check runs of PR: %w", gh.ErrTruncated))
+	dao.EXPECT().GetCombinedStatus(ctx, checksGHE().ToID(), "sha1").
+		Return(&github.CombinedStatus{State: aws.String("success")}, nil)
+
+	got, err := plugins.NewChecks(dao, "pr-bot").GetInputMsg(ctx, checksGHE())
+	if !errors.Is(err, input.ErrTruncated) {
+		t.Errorf("Checks.GetInputMsg() error = %v, want %v", err, input.ErrTruncated)
+	}
+	var msg plugins.Checks
+	if err := json.Unmarshal(got, &msg); err != nil {
+		t.Fatalf("error unmarshalling checks %v", err)
+	}
+	if len(msg.CheckRuns) != len(randomCheckRuns()) {
+		t.Errorf("Checks.GetInputMsg() check runs = %d, want %d", len(msg.CheckRuns), len(randomCheckRuns()))
+	}
+}
@@ -0,0 +1,203 @@
+package plugins
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/httplog"
+	gh "github.com/marqeta/pr-bot/github"
+	"github.com/marqeta/pr-bot/opa/input"
+)
+
+// CodeownersPaths are the locations of the CODEOWNERS file, in the order GitHub looks for it.
+var CodeownersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// Owners is the input message of the codeowners plugin.
+// Files maps every file changed in the PR to its owners, files without owners map to an empty list.
+// Teams and Users are the owning teams, as org/team, and the owning users of the files changed.
+type Owners struct {
+	Path  string              `json:"path"`
+	Files map[string][]string `json:"files"`
+	Teams []string            `json:"teams"`
+	Users []string            `json:"users"`
+}
+
+// CodeownersRule is a line of a CODEOWNERS file.
+type CodeownersRule struct {
+	Pattern string
+	Owners  []string
+	re      *regexp.Regexp
+}
+
+// Codeowners is a parsed CODEOWNERS file.
+type Codeowners []CodeownersRule
+
+type codeowners struct {
+	dao gh.API
+}
+
+// GetInputMsg implements input.Plugin.
+func (c *codeowners) GetInputMsg(ctx context.Context, ghe input.GHE) (json.RawMessage, error) {
+	oplog := httplog.LogEntry(ctx)
+	id := ghe.ToID()
+	if ghe.PullRequest.GetBase() == nil {
+		return json.RawMessage{}, errNoBaseBranch
+	}
+	base := ghe.PullRequest.GetBase().GetRef()
+
+	msg := Owners{
+		Files: make(map[string][]string),
+		Teams: make([]string, 0),
+		Users: make([]string, 0),
+	}
+	var rules Codeowners
+	for _, path := range CodeownersPaths {
+		content, err := c.dao.GetFileContent(ctx, id, path, base)
+		if errors.Is(err, gh.ErrFileNotFound) {
+			continue
+		}
+		if err != nil {
+			return json.RawMessage{}, err
+		}
+		msg.Path = path
+		rules = ParseCodeowners(content)
+		break
+	}
+	if msg.Path == "" {
+		oplog.Info().Msgf("CODEOWNERS not found on %v", base)
+	}
+
+	files, err := c.dao.ListNamesOfFilesChangedInPR(ctx, id)
+	listTruncated := errors.Is(err, gh.ErrTruncated)
+	if err != nil && !listTruncated {
+		return json.RawMessage{}, err
+	}
+	for _, f := range files {
+		owners := rules.Owners(f)
+		msg.Files[f] = owners
+		for _, o := range owners {
+			login, isUser := strings.CutPrefix(o, "@")
+			switch {
+			case isUser && strings.Contains(login, "/"):
+				msg.Teams = appendUnique(msg.Teams, login)
+			case isUser:
+				msg.Users = appendUnique(msg.Users, login)
+			default:
+				// owners can be emails of users
+				msg.Users = appendUnique(msg.Users, o)
+			}
+		}
+	}
+	slices.Sort(msg.Teams)
+	slices.Sort(msg.Users)
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return json.RawMessage{}, err
+	}
+	if listTruncated {
+		return json.RawMessage(data), fmt.Errorf("%w: %d files changed", input.ErrTruncated, len(files))
+	}
+	return json.RawMessage(data), nil
+}
+
+// Name implements input.Plugin.
+func (c *codeowners) Name() string {
+	return "codeowners"
+}
+
+// NewCodeowners returns a plugin matching the files changed in the PR
+// to their owners in the CODEOWNERS file of the base branch.
+func NewCodeowners(dao gh.API) input.Plugin {
+	return &codeowners{dao: dao}
+}
+
+// ParseCodeowners parses a CODEOWNERS file, lines with invalid patterns are skipped.
+func ParseCodeowners(content string) Codeowners {
+	rules := make(Codeowners, 0)
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		re, err := regexp.Compile(patternToRegexp(fields[0]))
+		if err != nil {
+			continue
+		}
+		rules = append(rules, CodeownersRule{Pattern: fields[0], Owners: fields[1:], re: re})
+	}
+	return rules
+}
+
+// Owners returns the owners of path, the last rule matching path wins.
+// returns an empty list when path has no owners.
+func (c Codeowners) Owners(path string) []string {
+	for i := len(c) - 1; i >= 0; i-- {
+		if c[i].re.MatchString(path) {
+			return slices.Clone(c[i].Owners)
+		}
+	}
+	return []string{}
+}
+
+// patternToRegexp converts a CODEOWNERS pattern to a regexp matching paths relative to the root of the repo.
+// patterns containing a slash other than a trailing slash are relative to the root,
+// other patterns match at any depth. patterns match files and every file in directories they match,
+// unless their last segment has a wildcard. patterns ending with a slash only match directories.
+// e.g. docs/* matches docs/a.md but not docs/b/a.md, docs/ and **/docs match both, and web/docs/a.md too.
+func patternToRegexp(pattern string) string {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			b.WriteString("[^/]*")
+		case pattern[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		}
+	}
+	switch {
+	case dirOnly:
+		b.WriteString("/.*$")
+	case strings.ContainsAny(pattern[strings.LastIndex(pattern, "/")+1:], "*?"):
+		// like GitHub, docs/* matches files in docs but not in its subdirectories
+		b.WriteString("$")
+	default:
+		b.WriteString("(?:/.*)?$")
+	}
+	return b.String()
+}
+
+func appendUnique(s []string, v string) []string {
+	if slices.Contains(s, v) {
+		return s
+	}
+	return append(s, v)
+}
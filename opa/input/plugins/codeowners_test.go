@@ -0,0 +1,210 @@
+package plugins_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	gh "github.com/marqeta/pr-bot/github"
+	"github.com/marqeta/pr-bot/opa/input"
+	"github.com/marqeta/pr-bot/opa/input/plugins"
+	"github.com/stretchr/testify/assert"
+)
+
+const randomCodeowners = `# default owners
+*       @org/platform
+
+/docs/  @org/docs jane@example.com
+*.go    @org/backend @john
+vendor/ # no owners
+`
+
+func TestCodeowners_GetInputMsg(t *testing.T) {
+	ctx := context.TODO()
+	//nolint:goerr113
+	randomErr := errors.New("random error")
+	notFound := fmt.Errorf("random path: %w", gh.ErrFileNotFound)
+	files := []string{"README.md", "docs/index.md", "cmd/main.go", "vendor/lib/lib.go"}
+	type args struct {
+		ghe             input.GHE
+		setExpectations func(d *gh.MockAPI)
+	}
+	tests := []struct {
+		name          string
+		args          args
+		want          json.RawMessage
+		wantErr       bool
+		wantTruncated bool
+	}{
+		{
+			name: "Should return owners of files changed",
+			args: args{
+				ghe: randomGHE(),
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().GetFileContent(ctx, randomGHE().ToID(), ".github/CODEOWNERS", "random Ref").
+						Return("", notFound)
+					d.EXPECT().GetFileContent(ctx, randomGHE().ToID(), "CODEOWNERS", "random Ref").
+						Return(randomCodeowners, nil)
+					d.EXPECT().ListNamesOfFilesChangedInPR(ctx, randomGHE().ToID()).Return(files, nil)
+				},
+			},
+			want: toJSON(t, plugins.Owners{
+				Path: "CODEOWNERS",
+				Files: map[string][]string{
+					"README.md":         {"@org/platform"},
+					"docs/index.md":     {"@org/docs", "jane@example.com"},
+					"cmd/main.go":       {"@org/backend", "@john"},
+					"vendor/lib/lib.go": {},
+				},
+				Teams: []string{"org/backend", "org/docs", "org/platform"},
+				Users: []string{"jane@example.com", "john"},
+			}),
+			wantErr: false,
+		},
+		{
+			name: "Should return files without owners when CODEOWNERS is not found",
+			args: args{
+				ghe: randomGHE(),
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().GetFileContent(ctx, randomGHE().ToID(), ".github/CODEOWNERS", "random Ref").
+						Return("", notFound)
+					d.EXPECT().GetFileContent(ctx, randomGHE().ToID(), "CODEOWNERS", "random Ref").
+						Return("", notFound)
+					d.EXPECT().GetFileContent(ctx, randomGHE().ToID(), "docs/CODEOWNERS", "random Ref").
+						Return("", notFound)
+					d.EXPECT().ListNamesOfFilesChangedInPR(ctx, randomGHE().ToID()).Return(files[:1], nil)
+				},
+			},
+			want: toJSON(t, plugins.Owners{
+				Files: map[string][]string{"README.md": {}},
+				Teams: []string{},
+				Users: []string{},
+			}),
+			wantErr: false,
+		},
+		{
+			name: "Should return owners of files listed before the cap",
+			args: args{
+				ghe: randomGHE(),
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().GetFileContent(ctx, randomGHE().ToID(), ".github/CODEOWNERS", "random Ref").
+						Return(randomCodeowners, nil)
+					d.EXPECT().ListNamesOfFilesChangedInPR(ctx, randomGHE().ToID()).
+						Return(files[:1], fmt.Errorf("files of PR: %w", gh.ErrTruncated))
+				},
+			},
+			want: toJSON(t, plugins.Owners{
+				Path:  ".github/CODEOWNERS",
+				Files: map[string][]string{"README.md": {"@org/platform"}},
+				Teams: []string{"org/platform"},
+				Users: []string{},
+			}),
+			wantErr:       false,
+			wantTruncated: true,
+		},
+		{
+			name: "Should return error when dao returns error getting CODEOWNERS",
+			args: args{
+				ghe: randomGHE(),
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().GetFileContent(ctx, randomGHE().ToID(), ".github/CODEOWNERS", "random Ref").
+						Return("", randomErr)
+				},
+			},
+			want:    json.RawMessage{},
+			wantErr: true,
+		},
+		{
+			name: "Should return error when dao returns error listing files",
+			args: args{
+				ghe: randomGHE(),
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().GetFileContent(ctx, randomGHE().ToID(), ".github/CODEOWNERS", "random Ref").
+						Return(randomCodeowners, nil)
+					d.EXPECT().ListNamesOfFilesChangedInPR(ctx, randomGHE().ToID()).Return(nil, randomErr)
+				},
+			},
+			want:    json.RawMessage{},
+			wantErr: true,
+		},
+		{
+			name: "Should return error when base branch is empty",
+			args: args{
+				ghe:             emptyBaseBranch(),
+				setExpectations: func(_ *gh.MockAPI) {},
+			},
+			want:    json.RawMessage{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := gh.NewMockAPI(t)
+			tt.args.setExpectations(d)
+			c := plugins.NewCodeowners(d)
+			got, err := c.GetInputMsg(ctx, tt.args.ghe)
+			truncated := errors.Is(err, input.ErrTruncated)
+			if (err != nil && !truncated) != tt.wantErr {
+				t.Errorf("Codeowners.GetInputMsg() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if truncated != tt.wantTruncated {
+				t.Errorf("Codeowners.GetInputMsg() truncated = %v, wantTruncated %v", truncated, tt.wantTruncated)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Codeowners.GetInputMsg() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodeowners_Owners(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		path    string
+		want    bool
+	}{
+		{name: "Should match file at any depth", pattern: "*.js", path: "web/src/app.js", want: true},
+		{name: "Should match directory at any depth", pattern: "build", path: "web/build/app.js", want: true},
+		{name: "Should match directory only with trailing slash", pattern: "apps/", path: "web/apps/app.js", want: true},
+		{name: "Should not match file with trailing slash", pattern: "apps/", path: "web/apps", want: false},
+		{name: "Should anchor pattern with leading slash", pattern: "/build", path: "web/build/app.js", want: false},
+		{name: "Should anchor pattern with slash", pattern: "docs/*", path: "docs/index.md", want: true},
+		{name: "Should not match nested file with trailing star", pattern: "docs/*", path: "docs/a/index.md", want: false},
+		{name: "Should match nested file with trailing double star", pattern: "docs/**", path: "docs/a/index.md", want: true},
+		{name: "Should not match nested file with single star", pattern: "docs/*.md", path: "docs/a/index.md", want: false},
+		{name: "Should match nested file with double star", pattern: "docs/**/*.md", path: "docs/a/b/index.md", want: true},
+		{name: "Should match top file with double star", pattern: "docs/**/*.md", path: "docs/index.md", want: true},
+		{name: "Should match leading double star", pattern: "**/logs", path: "app/logs/out.log", want: true},
+		// dir/* matches files directly in dir, dir/ and **/dir match every file in dir at any depth
+		{name: "Should match direct file with trailing star", pattern: "docs/*", path: "docs/start.md", want: true},
+		{name: "Should not match deeper file with trailing star", pattern: "docs/*", path: "docs/app/faq.md", want: false},
+		{name: "Should not match nested dir with trailing star", pattern: "docs/*", path: "web/docs/faq.md", want: false},
+		{name: "Should match deeper file with trailing slash", pattern: "docs/", path: "docs/app/faq.md", want: true},
+		{name: "Should match nested dir with trailing slash", pattern: "docs/", path: "web/docs/app/faq.md", want: true},
+		{name: "Should not match nested dir with leading slash", pattern: "/docs/", path: "web/docs/faq.md", want: false},
+		{name: "Should match top dir with leading double star", pattern: "**/docs", path: "docs/app/faq.md", want: true},
+		{name: "Should match nested dir with leading double star", pattern: "**/docs", path: "web/docs/app/faq.md", want: true},
+		{name: "Should not match prefix with leading double star", pattern: "**/docs", path: "web/docsite/faq.md", want: false},
+		{name: "Should match single char", pattern: "file?.txt", path: "file1.txt", want: true},
+		{name: "Should quote regexp chars", pattern: "a+b.txt", path: "aab.txt", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := plugins.ParseCodeowners(tt.pattern + " @owner")
+			assert.Equal(t, tt.want, len(rules.Owners(tt.path)) > 0)
+		})
+	}
+}
+
+func TestCodeowners_LastMatchWins(t *testing.T) {
+	rules := plugins.ParseCodeowners(randomCodeowners)
+	assert.Len(t, rules, 4)
+	assert.Equal(t, []string{"@org/backend", "@john"}, rules.Owners("docs/main.go"))
+	assert.Equal(t, []string{"@org/docs", "jane@example.com"}, rules.Owners("docs/index.md"))
+	assert.Equal(t, []string{}, rules.Owners("vendor/main.go"))
+}
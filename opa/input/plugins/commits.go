@@ -0,0 +1,132 @@
+package plugins
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/go-chi/httplog"
+	"github.com/google/go-github/v50/github"
+	gh "github.com/marqeta/pr-bot/github"
+	"github.com/marqeta/pr-bot/opa/input"
+)
+
+const coAuthorTrailer = "co-authored-by:"
+
+// Commit is an entry of the input message of the commits plugin.
+type Commit struct {
+	SHA       string     `json:"sha"`
+	Author    CommitUser `json:"author"`
+	Committer CommitUser `json:"committer"`
+	Message   string     `json:"message"`
+	// Verified is true when GitHub verified the signature of the commit, Reason explains why it is not.
+	Verified           bool         `json:"verified"`
+	VerificationReason string       `json:"verification_reason"`
+	Parents            int          `json:"parents"`
+	CoAuthors          []CommitUser `json:"co_authors"`
+}
+
+// CommitUser is the author or committer of a commit,
+// Login is empty when the email is not linked to a GitHub user.
+type CommitUser struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Login string `json:"login,omitempty"`
+}
+
+type commits struct {
+	dao       gh.API
+	sizeLimit int
+}
+
+// GetInputMsg implements input.Plugin.
+func (c *commits) GetInputMsg(ctx context.Context, ghe input.GHE) (json.RawMessage, error) {
+	oplog := httplog.LogEntry(ctx)
+	listed, err := c.dao.ListCommits(ctx, ghe.ToID())
+	listTruncated := errors.Is(err, gh.ErrTruncated)
+	if err != nil && !listTruncated {
+		return json.RawMessage{}, err
+	}
+
+	msg := make([]Commit, 0, len(listed))
+	size := 0
+	isSkipped := false
+	for _, rc := range listed {
+		commit := toCommit(rc)
+		if size+len(commit.Message) > c.sizeLimit {
+			isSkipped = true
+			continue
+		}
+		msg = append(msg, commit)
+		size += len(commit.Message)
+	}
+	if isSkipped {
+		oplog.Info().Msg("Some commits are skipped in input payload since their messages exceed the size limit")
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return json.RawMessage{}, err
+	}
+	// GitHub lists at most 250 commits of a PR
+	if listTruncated || isSkipped || len(listed) < ghe.PullRequest.GetCommits() {
+		return json.RawMessage(data), fmt.Errorf("%w: %d of %d commits",
+			input.ErrTruncated, len(msg), max(len(listed), ghe.PullRequest.GetCommits()))
+	}
+	return json.RawMessage(data), nil
+}
+
+// Name implements input.Plugin.
+func (c *commits) Name() string {
+	return "commits"
+}
+
+// NewCommits returns a plugin listing the commits of the PR,
+// commits are left out once the size of their messages exceeds sizeLimit.
+func NewCommits(dao gh.API, sizeLimit int) input.Plugin {
+	return &commits{dao: dao, sizeLimit: sizeLimit}
+}
+
+func toCommit(rc *github.RepositoryCommit) Commit {
+	c := rc.GetCommit()
+	return Commit{
+		SHA: rc.GetSHA(),
+		Author: CommitUser{
+			Name:  c.GetAuthor().GetName(),
+			Email: c.GetAuthor().GetEmail(),
+			Login: rc.GetAuthor().GetLogin(),
+		},
+		Committer: CommitUser{
+			Name:  c.GetCommitter().GetName(),
+			Email: c.GetCommitter().GetEmail(),
+			Login: rc.GetCommitter().GetLogin(),
+		},
+		Message:            c.GetMessage(),
+		Verified:           c.GetVerification().GetVerified(),
+		VerificationReason: c.GetVerification().GetReason(),
+		Parents:            len(rc.Parents),
+		CoAuthors:          CoAuthors(c.GetMessage()),
+	}
+}
+
+// CoAuthors returns the users in the Co-authored-by trailers of a commit message,
+// e.g. Co-authored-by: Jane Doe <jane@example.com>
+func CoAuthors(message string) []CommitUser {
+	coAuthors := make([]CommitUser, 0)
+	scanner := bufio.NewScanner(strings.NewReader(message))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) < len(coAuthorTrailer) || !strings.EqualFold(line[:len(coAuthorTrailer)], coAuthorTrailer) {
+			continue
+		}
+		addr, err := mail.ParseAddress(strings.TrimSpace(line[len(coAuthorTrailer):]))
+		if err != nil {
+			continue
+		}
+		coAuthors = append(coAuthors, CommitUser{Name: addr.Name, Email: addr.Address})
+	}
+	return coAuthors
+}
@@ -0,0 +1,207 @@
+package plugins_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/google/go-github/v50/github"
+	gh "github.com/marqeta/pr-bot/github"
+	"github.com/marqeta/pr-bot/opa/input"
+	"github.com/marqeta/pr-bot/opa/input/plugins"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommits_GetInputMsg(t *testing.T) {
+	ctx := context.TODO()
+	//nolint:goerr113
+	randomErr := errors.New("random error")
+	signed := &github.RepositoryCommit{
+		SHA: aws.String("sha1"),
+		Commit: &github.Commit{
+			Author:    &github.CommitAuthor{Name: aws.String("Jane"), Email: aws.String("jane@example.com")},
+			Committer: &github.CommitAuthor{Name: aws.String("GitHub"), Email: aws.String("noreply@github.com")},
+			Message:   aws.String("feat: add plugin\n\nCo-authored-by: John Doe <john@example.com>"),
+			Verification: &github.SignatureVerification{
+				Verified: aws.Bool(true),
+				Reason:   aws.String("valid"),
+			},
+		},
+		Author:    &github.User{Login: aws.String("jane")},
+		Committer: &github.User{Login: aws.String("web-flow")},
+		Parents:   []*github.Commit{{SHA: aws.String("sha0")}},
+	}
+	merge := &github.RepositoryCommit{
+		SHA: aws.String("sha2"),
+		Commit: &github.Commit{
+			Author:    &github.CommitAuthor{Name: aws.String("Jane"), Email: aws.String("jane@example.com")},
+			Committer: &github.CommitAuthor{Name: aws.String("Jane"), Email: aws.String("jane@example.com")},
+			Message:   aws.String("Merge branch 'main'"),
+			Verification: &github.SignatureVerification{
+				Verified: aws.Bool(false),
+				Reason:   aws.String("unsigned"),
+			},
+		},
+		Parents: []*github.Commit{{SHA: aws.String("sha1")}, {SHA: aws.String("main")}},
+	}
+	wantSigned := plugins.Commit{
+		SHA:                "sha1",
+		Author:             plugins.CommitUser{Name: "Jane", Email: "jane@example.com", Login: "jane"},
+		Committer:          plugins.CommitUser{Name: "GitHub", Email: "noreply@github.com", Login: "web-flow"},
+		Message:            "feat: add plugin\n\nCo-authored-by: John Doe <john@example.com>",
+		Verified:           true,
+		VerificationReason: "valid",
+		Parents:            1,
+		CoAuthors:          []plugins.CommitUser{{Name: "John Doe", Email: "john@example.com"}},
+	}
+	wantMerge := plugins.Commit{
+		SHA:                "sha2",
+		Author:             plugins.CommitUser{Name: "Jane", Email: "jane@example.com"},
+		Committer:          plugins.CommitUser{Name: "Jane", Email: "jane@example.com"},
+		Message:            "Merge branch 'main'",
+		VerificationReason: "unsigned",
+		Parents:            2,
+		CoAuthors:          []plugins.CommitUser{},
+	}
+	type args struct {
+		ghe             input.GHE
+		sizeLimit       int
+		setExpectations func(d *gh.MockAPI)
+	}
+	tests := []struct {
+		name          string
+		args          args
+		want          json.RawMessage
+		wantErr       bool
+		wantTruncated bool
+	}{
+		{
+			name: "Should return commits of PR",
+			args: args{
+				ghe:       randomGHE(),
+				sizeLimit: 1000,
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().ListCommits(ctx, randomGHE().ToID()).
+						Return([]*github.RepositoryCommit{signed, merge}, nil)
+				},
+			},
+			want:    toJSON(t, []plugins.Commit{wantSigned, wantMerge}),
+			wantErr: false,
+		},
+		{
+			name: "Should skip commits after total size of messages is > limit",
+			args: args{
+				ghe:       randomGHE(),
+				sizeLimit: len(wantMerge.Message),
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().ListCommits(ctx, randomGHE().ToID()).
+						Return([]*github.RepositoryCommit{signed, merge}, nil)
+				},
+			},
+			want:          toJSON(t, []plugins.Commit{wantMerge}),
+			wantErr:       false,
+			wantTruncated: true,
+		},
+		{
+			name: "Should return commits listed before the cap",
+			args: args{
+				ghe:       randomGHE(),
+				sizeLimit: 1000,
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().ListCommits(ctx, randomGHE().ToID()).
+						Return([]*github.RepositoryCommit{signed}, fmt.Errorf("commits of PR: %w", gh.ErrTruncated))
+				},
+			},
+			want:          toJSON(t, []plugins.Commit{wantSigned}),
+			wantErr:       false,
+			wantTruncated: true,
+		},
+		{
+			name: "Should return truncated commits when PR has more commits than listed",
+			args: args{
+				ghe:       commitCount(randomGHE(), 300),
+				sizeLimit: 1000,
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().ListCommits(ctx, randomGHE().ToID()).
+						Return([]*github.RepositoryCommit{signed}, nil)
+				},
+			},
+			want:          toJSON(t, []plugins.Commit{wantSigned}),
+			wantErr:       false,
+			wantTruncated: true,
+		},
+		{
+			name: "Should return error when dao returns error",
+			args: args{
+				ghe:       randomGHE(),
+				sizeLimit: 1000,
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().ListCommits(ctx, randomGHE().ToID()).Return(nil, randomErr)
+				},
+			},
+			want:    json.RawMessage{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := gh.NewMockAPI(t)
+			tt.args.setExpectations(d)
+			c := plugins.NewCommits(d, tt.args.sizeLimit)
+			got, err := c.GetInputMsg(ctx, tt.args.ghe)
+			truncated := errors.Is(err, input.ErrTruncated)
+			if (err != nil && !truncated) != tt.wantErr {
+				t.Errorf("Commits.GetInputMsg() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if truncated != tt.wantTruncated {
+				t.Errorf("Commits.GetInputMsg() truncated = %v, wantTruncated %v", truncated, tt.wantTruncated)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Commits.GetInputMsg() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCoAuthors(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    []plugins.CommitUser
+	}{
+		{
+			name: "Should return co-authors in trailers",
+			message: "fix: typo\n\nCo-authored-by: John Doe <john@example.com>\n" +
+				"co-authored-by: Jane <jane@example.com>",
+			want: []plugins.CommitUser{
+				{Name: "John Doe", Email: "john@example.com"},
+				{Name: "Jane", Email: "jane@example.com"},
+			},
+		},
+		{
+			name:    "Should skip trailers without a valid address",
+			message: "fix: typo\n\nCo-authored-by: John Doe",
+			want:    []plugins.CommitUser{},
+		},
+		{
+			name:    "Should return no co-authors",
+			message: "fix: typo",
+			want:    []plugins.CommitUser{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, plugins.CoAuthors(tt.message))
+		})
+	}
+}
+
+func commitCount(ghe input.GHE, n int) input.GHE {
+	ghe.PullRequest.Commits = aws.Int(n)
+	return ghe
+}
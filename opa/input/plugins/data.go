@@ -0,0 +1,61 @@
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/go-chi/httplog"
+	"github.com/marqeta/pr-bot/datastore"
+	"github.com/marqeta/pr-bot/opa/input"
+)
+
+type storedPayloads struct {
+	dao datastore.Dao
+}
+
+// GetInputMsg implements input.Plugin.
+// the input message maps services to their jobs to the payloads they stored for the head and base sha of the PR.
+func (d *storedPayloads) GetInputMsg(ctx context.Context, ghe input.GHE) (json.RawMessage, error) {
+	oplog := httplog.LogEntry(ctx)
+	if ghe.PullRequest.GetBase() == nil {
+		return json.RawMessage{}, errNoBaseBranch
+	}
+	head := ghe.PullRequest.GetHead().GetSHA()
+	base := ghe.PullRequest.GetBase().GetSHA()
+	payloads, err := d.dao.ListPayloads(ctx, ghe.ToID(), head, base)
+	if err != nil {
+		return json.RawMessage{}, err
+	}
+
+	msg := make(map[string]map[string]json.RawMessage)
+	for _, p := range payloads {
+		if _, ok := msg[p.Service]; !ok {
+			msg[p.Service] = make(map[string]json.RawMessage)
+		}
+		payload := p.Payload
+		if !json.Valid(payload) {
+			// payloads are stored as posted, pass payloads which are not JSON as strings
+			oplog.Info().Msgf("payload of service %v job %v is not JSON", p.Service, p.Job)
+			payload, err = json.Marshal(string(p.Payload))
+			if err != nil {
+				return json.RawMessage{}, err
+			}
+		}
+		msg[p.Service][p.Job] = payload
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return json.RawMessage{}, err
+	}
+	return json.RawMessage(data), nil
+}
+
+// Name implements input.Plugin.
+func (d *storedPayloads) Name() string {
+	return "data"
+}
+
+// NewData returns a plugin with the payloads stored through the data endpoint for the head and base sha of the PR.
+func NewData(dao datastore.Dao) input.Plugin {
+	return &storedPayloads{dao: dao}
+}
@@ -0,0 +1,121 @@
+package plugins_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/google/go-github/v50/github"
+	"github.com/marqeta/pr-bot/datastore"
+	"github.com/marqeta/pr-bot/opa/input"
+	"github.com/marqeta/pr-bot/opa/input/plugins"
+)
+
+func TestData_GetInputMsg(t *testing.T) {
+	ctx := context.TODO()
+	//nolint:goerr113
+	randomErr := errors.New("random error")
+	ghe := withSHAs(randomGHE(), "head1", "base1")
+	plan := storedPayload("terraform", "plan", `{"resource_changes":[]}`)
+	apply := storedPayload("terraform", "apply", `{"applied":true}`)
+	lint := storedPayload("lint", "golangci", "0 issues")
+	type args struct {
+		ghe             input.GHE
+		setExpectations func(d *datastore.MockDao)
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    json.RawMessage
+		wantErr bool
+	}{
+		{
+			name: "Should return payloads by service and job",
+			args: args{
+				ghe: ghe,
+				setExpectations: func(d *datastore.MockDao) {
+					d.EXPECT().ListPayloads(ctx, ghe.ToID(), "head1", "base1").
+						Return([]datastore.StoredPayload{plan, apply}, nil)
+				},
+			},
+			want: json.RawMessage(
+				`{"terraform":{"apply":{"applied":true},"plan":{"resource_changes":[]}}}`),
+			wantErr: false,
+		},
+		{
+			name: "Should return payloads which are not JSON as strings",
+			args: args{
+				ghe: ghe,
+				setExpectations: func(d *datastore.MockDao) {
+					d.EXPECT().ListPayloads(ctx, ghe.ToID(), "head1", "base1").
+						Return([]datastore.StoredPayload{lint}, nil)
+				},
+			},
+			want:    json.RawMessage(`{"lint":{"golangci":"0 issues"}}`),
+			wantErr: false,
+		},
+		{
+			name: "Should return empty object when no payloads are stored",
+			args: args{
+				ghe: ghe,
+				setExpectations: func(d *datastore.MockDao) {
+					d.EXPECT().ListPayloads(ctx, ghe.ToID(), "head1", "base1").
+						Return([]datastore.StoredPayload{}, nil)
+				},
+			},
+			want:    json.RawMessage(`{}`),
+			wantErr: false,
+		},
+		{
+			name: "Should return error when dao returns error",
+			args: args{
+				ghe: ghe,
+				setExpectations: func(d *datastore.MockDao) {
+					d.EXPECT().ListPayloads(ctx, ghe.ToID(), "head1", "base1").Return(nil, randomErr)
+				},
+			},
+			want:    json.RawMessage{},
+			wantErr: true,
+		},
+		{
+			name: "Should return error when base branch is empty",
+			args: args{
+				ghe:             emptyBaseBranch(),
+				setExpectations: func(_ *datastore.MockDao) {},
+			},
+			want:    json.RawMessage{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := datastore.NewMockDao(t)
+			tt.args.setExpectations(d)
+			p := plugins.NewData(d)
+			got, err := p.GetInputMsg(ctx, tt.args.ghe)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Data.GetInputMsg() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Data.GetInputMsg() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func withSHAs(ghe input.GHE, head, base string) input.GHE {
+	ghe.PullRequest.Head = &github.PullRequestBranch{SHA: aws.String(head)}
+	ghe.PullRequest.Base.SHA = aws.String(base)
+	return ghe
+}
+
+func storedPayload(service, job, payload string) datastore.StoredPayload {
+	return datastore.StoredPayload{
+		Metadata: datastore.Metadata{Service: service, Job: job, Head: "head1", Base: "base1"},
+		Payload:  json.RawMessage(payload),
+	}
+}
@@ -0,0 +1,182 @@
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/go-chi/httplog"
+	"github.com/google/go-github/v50/github"
+	gh "github.com/marqeta/pr-bot/github"
+	"github.com/marqeta/pr-bot/id"
+	"github.com/marqeta/pr-bot/opa/input"
+)
+
+// ManifestDiff is an entry of the input message of the dependencies plugin,
+// the dependencies added, removed and upgraded by the PR in a manifest.
+type ManifestDiff struct {
+	Path      string `json:"path"`
+	Ecosystem string `json:"ecosystem"`
+	// Error is set when a version of the manifest could not be parsed, dependencies are left empty.
+	Error    string             `json:"error,omitempty"`
+	Added    []Dependency       `json:"added"`
+	Removed  []Dependency       `json:"removed"`
+	Upgraded []DependencyUpdate `json:"upgraded"`
+}
+
+// Dependency is a dependency added or removed from a manifest.
+// Kind is the field of package.json listing the dependency, e.g. devDependencies, empty in other ecosystems.
+type Dependency struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Kind    string `json:"kind,omitempty"`
+}
+
+// DependencyUpdate is a dependency whose version changed, Bump is one of major, minor, patch, downgrade or other.
+type DependencyUpdate struct {
+	Name string `json:"name"`
+	Kind string `json:"kind,omitempty"`
+	From string `json:"from"`
+	To   string `json:"to"`
+	Bump string `json:"bump"`
+}
+
+// dependencyKey identifies a dependency in a manifest,
+// a dependency moved to another kind is removed from the old kind and added to the new kind.
+type dependencyKey struct {
+	name string
+	kind string
+}
+
+type dependencies struct {
+	dao          gh.API
+	maxManifests int
+}
+
+// GetInputMsg implements input.Plugin.
+func (d *dependencies) GetInputMsg(ctx context.Context, ghe input.GHE) (json.RawMessage, error) {
+	oplog := httplog.LogEntry(ctx)
+	if ghe.PullRequest.GetBase() == nil {
+		return json.RawMessage{}, errNoBaseBranch
+	}
+	id := ghe.ToID()
+	files, err := d.dao.ListFilesChangedInPR(ctx, id)
+	listTruncated := errors.Is(err, gh.ErrTruncated)
+	if err != nil && !listTruncated {
+		return json.RawMessage{}, err
+	}
+
+	msg := make([]ManifestDiff, 0)
+	isSkipped := false
+	for _, file := range files {
+		ecosystem := Ecosystem(file.GetFilename())
+		if ecosystem == "" {
+			continue
+		}
+		if len(msg) == d.maxManifests {
+			isSkipped = true
+			continue
+		}
+		diff, err := d.diff(ctx, id, file, ecosystem,
+			ghe.PullRequest.GetBase().GetSHA(), ghe.PullRequest.GetHead().GetSHA())
+		if err != nil {
+			return json.RawMessage{}, err
+		}
+		msg = append(msg, diff)
+	}
+	if isSkipped {
+		oplog.Info().Msgf("Some manifests are skipped in input payload since the PR changes more than %d manifests",
+			d.maxManifests)
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return json.RawMessage{}, err
+	}
+	if listTruncated || isSkipped || len(files) < ghe.PullRequest.GetChangedFiles() {
+		return json.RawMessage(data), fmt.Errorf("%w: %d manifests", input.ErrTruncated, len(msg))
+	}
+	return json.RawMessage(data), nil
+}
+
+// Name implements input.Plugin.
+func (d *dependencies) Name() string {
+	return "dependencies"
+}
+
+// NewDependencies returns a plugin diffing the dependencies in the manifests changed by the PR,
+// between the base and head sha of the PR. at most maxManifests manifests are diffed.
+func NewDependencies(dao gh.API, maxManifests int) input.Plugin {
+	return &dependencies{dao: dao, maxManifests: maxManifests}
+}
+
+func (d *dependencies) diff(ctx context.Context, id id.PR, file *github.CommitFile,
+	ecosystem, base, head string) (ManifestDiff, error) {
+	diff := ManifestDiff{
+		Path:      file.GetFilename(),
+		Ecosystem: ecosystem,
+		Added:     make([]Dependency, 0),
+		Removed:   make([]Dependency, 0),
+		Upgraded:  make([]DependencyUpdate, 0),
+	}
+	basePath := file.GetFilename()
+	if file.GetPreviousFilename() != "" {
+		basePath = file.GetPreviousFilename()
+	}
+	before, err := d.manifest(ctx, id, basePath, base, ecosystem, file.GetStatus() != "added")
+	if errors.Is(err, ErrInvalidManifest) {
+		diff.Error = err.Error()
+		return diff, nil
+	}
+	if err != nil {
+		return ManifestDiff{}, err
+	}
+	after, err := d.manifest(ctx, id, diff.Path, head, ecosystem, file.GetStatus() != "removed")
+	if errors.Is(err, ErrInvalidManifest) {
+		diff.Error = err.Error()
+		return diff, nil
+	}
+	if err != nil {
+		return ManifestDiff{}, err
+	}
+
+	versions := make(map[dependencyKey]string, len(before))
+	for _, dep := range before {
+		versions[dependencyKey{name: dep.Name, kind: dep.Kind}] = dep.Version
+	}
+	for _, dep := range after {
+		key := dependencyKey{name: dep.Name, kind: dep.Kind}
+		old, ok := versions[key]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, dep)
+		case old != dep.Version:
+			diff.Upgraded = append(diff.Upgraded, DependencyUpdate{Name: dep.Name, Kind: dep.Kind,
+				From: old, To: dep.Version, Bump: Bump(old, dep.Version)})
+		}
+		delete(versions, key)
+	}
+	// dependencies are sorted by name and kind, so that diffs are sorted too
+	for _, dep := range before {
+		if _, ok := versions[dependencyKey{name: dep.Name, kind: dep.Kind}]; ok {
+			diff.Removed = append(diff.Removed, dep)
+		}
+	}
+	return diff, nil
+}
+
+// manifest returns the dependencies in the manifest at path on ref, none when the manifest does not exist on ref.
+func (d *dependencies) manifest(ctx context.Context, id id.PR, path, ref, ecosystem string,
+	exists bool) ([]Dependency, error) {
+	if !exists {
+		return []Dependency{}, nil
+	}
+	content, err := d.dao.GetFileContent(ctx, id, path, ref)
+	if errors.Is(err, gh.ErrFileNotFound) {
+		return []Dependency{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ParseManifest(ecosystem, content)
+}
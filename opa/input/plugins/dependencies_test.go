@@ -0,0 +1,235 @@
+package plugins_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/google/go-github/v50/github"
+	gh "github.com/marqeta/pr-bot/github"
+	"github.com/marqeta/pr-bot/opa/input"
+	"github.com/marqeta/pr-bot/opa/input/plugins"
+)
+
+func TestDependencies_GetInputMsg(t *testing.T) {
+	ctx := context.TODO()
+	//nolint:goerr113
+	randomErr := errors.New("random error")
+	notFound := fmt.Errorf("random path: %w", gh.ErrFileNotFound)
+	ghe := withSHAs(randomGHE(), "head1", "base1")
+	pr := ghe.ToID()
+	goMod := commitFile("go.mod", "modified", "")
+	pkg := commitFile("web/package.json", "added", "")
+	reqs := commitFile("requirements.txt", "renamed", "requirements-old.txt")
+	baseGoMod := "require (\n\tgithub.com/google/uuid v1.5.0\n\tgithub.com/pkg/errors v0.9.1\n)\n"
+	headGoMod := "require (\n\tgithub.com/google/uuid v1.5.1\n\tgolang.org/x/net v0.30.0\n)\n"
+	type args struct {
+		ghe             input.GHE
+		maxManifests    int
+		setExpectations func(d *gh.MockAPI)
+	}
+	tests := []struct {
+		name          string
+		args          args
+		want          json.RawMessage
+		wantErr       bool
+		wantTruncated bool
+	}{
+		{
+			name: "Should return dependencies added, removed and upgraded in manifests changed",
+			args: args{
+				ghe:          ghe,
+				maxManifests: 20,
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().ListFilesChangedInPR(ctx, pr).
+						Return([]*github.CommitFile{commitFile("main.go", "modified", ""), goMod, pkg, reqs}, nil)
+					d.EXPECT().GetFileContent(ctx, pr, "go.mod", "base1").Return(baseGoMod, nil)
+					d.EXPECT().GetFileContent(ctx, pr, "go.mod", "head1").Return(headGoMod, nil)
+					d.EXPECT().GetFileContent(ctx, pr, "web/package.json", "head1").
+						Return(`{"dependencies": {"react": "^18.2.0"}}`, nil)
+					d.EXPECT().GetFileContent(ctx, pr, "requirements-old.txt", "base1").Return("django==4.2.7", nil)
+					d.EXPECT().GetFileContent(ctx, pr, "requirements.txt", "head1").Return("Django==4.2.8", nil)
+				},
+			},
+			want: toJSON(t, []plugins.ManifestDiff{
+				{
+					Path:      "go.mod",
+					Ecosystem: plugins.EcosystemGo,
+					Added:     []plugins.Dependency{{Name: "golang.org/x/net", Version: "v0.30.0"}},
+					Removed:   []plugins.Dependency{{Name: "github.com/pkg/errors", Version: "v0.9.1"}},
+					Upgraded: []plugins.DependencyUpdate{
+						{Name: "github.com/google/uuid", From: "v1.5.0", To: "v1.5.1", Bump: "patch"},
+					},
+				},
+				{
+					Path:      "web/package.json",
+					Ecosystem: plugins.EcosystemNPM,
+					Added:     []plugins.Dependency{{Name: "react", Version: "^18.2.0", Kind: "dependencies"}},
+					Removed:   []plugins.Dependency{},
+					Upgraded:  []plugins.DependencyUpdate{},
+				},
+				{
+					Path:      "requirements.txt",
+					Ecosystem: plugins.EcosystemPip,
+					Added:     []plugins.Dependency{},
+					Removed:   []plugins.Dependency{},
+					Upgraded: []plugins.DependencyUpdate{
+						{Name: "django", From: "4.2.7", To: "4.2.8", Bump: "patch"},
+					},
+				},
+			}),
+			wantErr: false,
+		},
+		{
+			name: "Should return dependencies moved to another kind",
+			args: args{
+				ghe:          ghe,
+				maxManifests: 20,
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().ListFilesChangedInPR(ctx, pr).
+						Return([]*github.CommitFile{commitFile("web/package.json", "modified", "")}, nil)
+					d.EXPECT().GetFileContent(ctx, pr, "web/package.json", "base1").
+						Return(`{"dependencies": {"react": "^18.2.0"}, "devDependencies": {"jest": "~29.7.0"}}`, nil)
+					d.EXPECT().GetFileContent(ctx, pr, "web/package.json", "head1").
+						Return(`{"dependencies": {"jest": "~29.7.0", "react": "^18.3.0"}}`, nil)
+				},
+			},
+			want: toJSON(t, []plugins.ManifestDiff{
+				{
+					Path:      "web/package.json",
+					Ecosystem: plugins.EcosystemNPM,
+					Added:     []plugins.Dependency{{Name: "jest", Version: "~29.7.0", Kind: "dependencies"}},
+					Removed:   []plugins.Dependency{{Name: "jest", Version: "~29.7.0", Kind: "devDependencies"}},
+					Upgraded: []plugins.DependencyUpdate{
+						{Name: "react", Kind: "dependencies", From: "^18.2.0", To: "^18.3.0", Bump: "minor"},
+					},
+				},
+			}),
+			wantErr: false,
+		},
+		{
+			name: "Should return error of manifests which cannot be parsed",
+			args: args{
+				ghe:          ghe,
+				maxManifests: 20,
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().ListFilesChangedInPR(ctx, pr).Return([]*github.CommitFile{pkg}, nil)
+					d.EXPECT().GetFileContent(ctx, pr, "web/package.json", "head1").Return(`{"name": `, nil)
+				},
+			},
+			want: toJSON(t, []plugins.ManifestDiff{
+				{
+					Path:      "web/package.json",
+					Ecosystem: plugins.EcosystemNPM,
+					Error:     "invalid manifest: unexpected end of JSON input",
+					Added:     []plugins.Dependency{},
+					Removed:   []plugins.Dependency{},
+					Upgraded:  []plugins.DependencyUpdate{},
+				},
+			}),
+			wantErr: false,
+		},
+		{
+			name: "Should skip manifests after the max number of manifests",
+			args: args{
+				ghe:          ghe,
+				maxManifests: 1,
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().ListFilesChangedInPR(ctx, pr).Return([]*github.CommitFile{pkg, goMod}, nil)
+					d.EXPECT().GetFileContent(ctx, pr, "web/package.json", "head1").Return("", notFound)
+				},
+			},
+			want: toJSON(t, []plugins.ManifestDiff{
+				{
+					Path:      "web/package.json",
+					Ecosystem: plugins.EcosystemNPM,
+					Added:     []plugins.Dependency{},
+					Removed:   []plugins.Dependency{},
+					Upgraded:  []plugins.DependencyUpdate{},
+				},
+			}),
+			wantErr:       false,
+			wantTruncated: true,
+		},
+		{
+			name: "Should return manifests in files listed before the cap",
+			args: args{
+				ghe:          ghe,
+				maxManifests: 20,
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().ListFilesChangedInPR(ctx, pr).
+						Return([]*github.CommitFile{}, fmt.Errorf("files of PR: %w", gh.ErrTruncated))
+				},
+			},
+			want:          toJSON(t, []plugins.ManifestDiff{}),
+			wantErr:       false,
+			wantTruncated: true,
+		},
+		{
+			name: "Should return error when dao returns error getting manifest",
+			args: args{
+				ghe:          ghe,
+				maxManifests: 20,
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().ListFilesChangedInPR(ctx, pr).Return([]*github.CommitFile{goMod}, nil)
+					d.EXPECT().GetFileContent(ctx, pr, "go.mod", "base1").Return("", randomErr)
+				},
+			},
+			want:    json.RawMessage{},
+			wantErr: true,
+		},
+		{
+			name: "Should return error when dao returns error listing files",
+			args: args{
+				ghe:          ghe,
+				maxManifests: 20,
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().ListFilesChangedInPR(ctx, pr).Return(nil, randomErr)
+				},
+			},
+			want:    json.RawMessage{},
+			wantErr: true,
+		},
+		{
+			name: "Should return error when base branch is empty",
+			args: args{
+				ghe:             emptyBaseBranch(),
+				maxManifests:    20,
+				setExpectations: func(_ *gh.MockAPI) {},
+			},
+			want:    json.RawMessage{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := gh.NewMockAPI(t)
+			tt.args.setExpectations(d)
+			p := plugins.NewDependencies(d, tt.args.maxManifests)
+			got, err := p.GetInputMsg(ctx, tt.args.ghe)
+			truncated := errors.Is(err, input.ErrTruncated)
+			if (err != nil && !truncated) != tt.wantErr {
+				t.Errorf("Dependencies.GetInputMsg() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if truncated != tt.wantTruncated {
+				t.Errorf("Dependencies.GetInputMsg() truncated = %v, wantTruncated %v", truncated, tt.wantTruncated)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Dependencies.GetInputMsg() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func commitFile(name, status, previous string) *github.CommitFile {
+	f := &github.CommitFile{Filename: aws.String(name), Status: aws.String(status)}
+	if previous != "" {
+		f.PreviousFilename = aws.String(previous)
+	}
+	return f
+}
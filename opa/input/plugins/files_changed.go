@@ -3,6 +3,8 @@ package plugins
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 
 	"github.com/go-chi/httplog"
 	"github.com/google/go-github/v50/github"
@@ -19,7 +21,8 @@ type FilesChanged struct {
 func (fc *FilesChanged) GetInputMsg(ctx context.Context, ghe input.GHE) (json.RawMessage, error) {
 	oplog := httplog.LogEntry(ctx)
 	filesChanged, err := fc.dao.ListFilesChangedInPR(ctx, ghe.ToID())
-	if err != nil {
+	listTruncated := errors.Is(err, gh.ErrTruncated)
+	if err != nil && !listTruncated {
 		return json.RawMessage{}, err
 	}
 	smallFilesChanged := make([]*github.CommitFile, 0)
@@ -40,6 +43,11 @@ func (fc *FilesChanged) GetInputMsg(ctx context.Context, ghe input.GHE) (json.Ra
 	if err != nil {
 		return json.RawMessage{}, err
 	}
+	// GitHub lists at most 3000 files changed in a PR
+	if listTruncated || isSkipped || len(filesChanged) < ghe.PullRequest.GetChangedFiles() {
+		return json.RawMessage(data), fmt.Errorf("%w: %d of %d files changed",
+			input.ErrTruncated, len(smallFilesChanged), max(len(filesChanged), ghe.PullRequest.GetChangedFiles()))
+	}
 	return json.RawMessage(data), nil
 }
 
@@ -3,6 +3,7 @@ package plugins_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"slices"
@@ -26,10 +27,11 @@ func TestFilesChanged_GetMessage(t *testing.T) {
 		sizeLimit      int
 	}
 	tests := []struct {
-		name    string
-		args    args
-		want    json.RawMessage
-		wantErr bool
+		name          string
+		args          args
+		want          json.RawMessage
+		wantErr       bool
+		wantTruncated bool
 	}{
 		{
 			name: "Should return single file changed",
@@ -67,8 +69,9 @@ func TestFilesChanged_GetMessage(t *testing.T) {
 				},
 				sizeLimit: 0,
 			},
-			want:    toJSON(t, []*github.CommitFile{}),
-			wantErr: false,
+			want:          toJSON(t, []*github.CommitFile{}),
+			wantErr:       false,
+			wantTruncated: true,
 		},
 		{
 			name: "Should skip files with large patch",
@@ -80,8 +83,9 @@ func TestFilesChanged_GetMessage(t *testing.T) {
 				},
 				sizeLimit: 7 * 3, // 7 is the size of each file in randomFilesChanged(3)
 			},
-			want:    toJSON(t, randomFilesChanged(3)),
-			wantErr: false,
+			want:          toJSON(t, randomFilesChanged(3)),
+			wantErr:       false,
+			wantTruncated: true,
 		},
 		{
 			name: "Should skip files after total size is > limit",
@@ -93,8 +97,37 @@ func TestFilesChanged_GetMessage(t *testing.T) {
 				},
 				sizeLimit: 20 + 7, // 20 is the size of large(1) and 7 is the size of each file in randomFilesChanged(5)
 			},
-			want:    toJSON(t, slices.Concat(large(20), randomFilesChanged(1))),
-			wantErr: false,
+			want:          toJSON(t, slices.Concat(large(20), randomFilesChanged(1))),
+			wantErr:       false,
+			wantTruncated: true,
+		},
+		{
+			name: "Should return files changed listed before the cap",
+			args: args{
+				ghe: randomGHE(),
+				setExpectaions: func(d *gh.MockAPI) {
+					d.EXPECT().ListFilesChangedInPR(ctx, randomGHE().ToID()).
+						Return(randomFilesChanged(2), fmt.Errorf("files of PR: %w", gh.ErrTruncated))
+				},
+				sizeLimit: 100,
+			},
+			want:          toJSON(t, randomFilesChanged(2)),
+			wantErr:       false,
+			wantTruncated: true,
+		},
+		{
+			name: "Should return truncated files changed when PR has more files than listed",
+			args: args{
+				ghe: changedFiles(randomGHE(), 3000),
+				setExpectaions: func(d *gh.MockAPI) {
+					d.EXPECT().ListFilesChangedInPR(ctx, randomGHE().ToID()).
+						Return(randomFilesChanged(2), nil)
+				},
+				sizeLimit: 100,
+			},
+			want:          toJSON(t, randomFilesChanged(2)),
+			wantErr:       false,
+			wantTruncated: true,
 		},
 		{
 			name: "Should return error when dao returns error",
@@ -116,10 +149,14 @@ func TestFilesChanged_GetMessage(t *testing.T) {
 			tt.args.setExpectaions(d)
 			fc := plugins.NewFilesChanged(d, tt.args.sizeLimit)
 			got, err := fc.GetInputMsg(ctx, tt.args.ghe)
-			if (err != nil) != tt.wantErr {
+			truncated := errors.Is(err, input.ErrTruncated)
+			if (err != nil && !truncated) != tt.wantErr {
 				t.Errorf("FilesChanged.GetMessage() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if truncated != tt.wantTruncated {
+				t.Errorf("FilesChanged.GetMessage() truncated = %v, wantTruncated %v", truncated, tt.wantTruncated)
+			}
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("FilesChanged.GetMessage() = %v, want %v", string(got), string(tt.want))
 			}
@@ -158,6 +195,11 @@ func randomGHE() input.GHE {
 	}
 }
 
+func changedFiles(ghe input.GHE, n int) input.GHE {
+	ghe.PullRequest.ChangedFiles = aws.Int(n)
+	return ghe
+}
+
 func randomFilesChanged(n int) []*github.CommitFile {
 
 	files := make([]*github.CommitFile, 0, n)
@@ -0,0 +1,203 @@
+package plugins
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	EcosystemGo  = "go"
+	EcosystemNPM = "npm"
+	EcosystemPip = "pip"
+)
+
+// ErrInvalidManifest is returned when a manifest cannot be parsed.
+var ErrInvalidManifest = errors.New("invalid manifest")
+
+var (
+	requirementRe = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)(\[[^\]]*\])?\s*(.*)$`)
+	separatorsRe  = regexp.MustCompile(`[-_.]+`)
+)
+
+// Ecosystem returns the ecosystem of a dependency manifest, empty when file is not a manifest.
+// manifests are go.mod, package.json and requirements*.txt files.
+func Ecosystem(file string) string {
+	name := path.Base(file)
+	switch {
+	case name == "go.mod":
+		return EcosystemGo
+	case name == "package.json":
+		return EcosystemNPM
+	case strings.HasPrefix(name, "requirements") && strings.HasSuffix(name, ".txt"):
+		return EcosystemPip
+	default:
+		return ""
+	}
+}
+
+// ParseManifest returns the dependencies in a manifest of ecosystem, sorted by name and kind.
+func ParseManifest(ecosystem, content string) ([]Dependency, error) {
+	var deps []Dependency
+	switch ecosystem {
+	case EcosystemGo:
+		deps = toDependencies(parseGoMod(content), "")
+	case EcosystemNPM:
+		var err error
+		deps, err = parsePackageJSON(content)
+		if err != nil {
+			return nil, err
+		}
+	case EcosystemPip:
+		deps = toDependencies(parseRequirements(content), "")
+	default:
+		deps = make([]Dependency, 0)
+	}
+	slices.SortFunc(deps, compareDependencies)
+	return deps, nil
+}
+
+func toDependencies(versions map[string]string, kind string) []Dependency {
+	deps := make([]Dependency, 0, len(versions))
+	for name, version := range versions {
+		deps = append(deps, Dependency{Name: name, Version: version, Kind: kind})
+	}
+	return deps
+}
+
+func compareDependencies(a, b Dependency) int {
+	return cmp.Or(strings.Compare(a.Name, b.Name), strings.Compare(a.Kind, b.Kind))
+}
+
+// Bump returns how a dependency changed from version to version, one of major, minor, patch, downgrade,
+// or other when versions are not numeric or differ only in their pre-release, build or range.
+func Bump(from, to string) string {
+	f, okFrom := versionCore(from)
+	t, okTo := versionCore(to)
+	if !okFrom || !okTo {
+		return "other"
+	}
+	for i, level := range []string{"major", "minor", "patch"} {
+		switch {
+		case t[i] > f[i]:
+			return level
+		case t[i] < f[i]:
+			return "downgrade"
+		}
+	}
+	return "other"
+}
+
+// versionCore returns major, minor and patch of a version like v1.2.3, ^1.2, ~1.2.3-rc.1 or ==1.2.3.
+func versionCore(version string) ([3]int, bool) {
+	var core [3]int
+	version = strings.TrimLeft(strings.TrimSpace(version), "^~=<>!v ")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	parts := strings.Split(version, ".")
+	if len(parts) > 3 {
+		return core, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return core, false
+		}
+		core[i] = n
+	}
+	return core, true
+}
+
+// parseGoMod returns the modules in the require directives of a go.mod file.
+func parseGoMod(content string) map[string]string {
+	deps := make(map[string]string)
+	block := ""
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			continue
+		case block != "" && fields[0] == ")":
+			block = ""
+			continue
+		case block == "" && fields[len(fields)-1] == "(":
+			block = fields[0]
+			continue
+		case block == "" && fields[0] == "require":
+			fields = fields[1:]
+		case block != "require":
+			continue
+		}
+		if len(fields) == 2 {
+			deps[strings.Trim(fields[0], `"`)] = fields[1]
+		}
+	}
+	return deps
+}
+
+// parsePackageJSON returns the dependencies of every kind in a package.json file,
+// the kind is the field listing the dependency, e.g. devDependencies.
+func parsePackageJSON(content string) ([]Dependency, error) {
+	var pkg struct {
+		Dependencies         map[string]string `json:"dependencies"`
+		DevDependencies      map[string]string `json:"devDependencies"`
+		PeerDependencies     map[string]string `json:"peerDependencies"`
+		OptionalDependencies map[string]string `json:"optionalDependencies"`
+	}
+	if err := json.Unmarshal([]byte(content), &pkg); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidManifest, err)
+	}
+	deps := toDependencies(pkg.Dependencies, "dependencies")
+	deps = append(deps, toDependencies(pkg.DevDependencies, "devDependencies")...)
+	deps = append(deps, toDependencies(pkg.PeerDependencies, "peerDependencies")...)
+	deps = append(deps, toDependencies(pkg.OptionalDependencies, "optionalDependencies")...)
+	return deps, nil
+}
+
+// parseRequirements returns the requirements of a pip requirements file, by normalized name.
+// pinned requirements map to their version, other requirements to their specifier, e.g. >=1.0,<2.
+func parseRequirements(content string) map[string]string {
+	deps := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		if i := strings.Index(line, ";"); i >= 0 {
+			// environment markers
+			line = line[:i]
+		}
+		// options like -r other.txt or -e ., and paths are skipped
+		match := requirementRe.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		spec := strings.ReplaceAll(match[3], " ", "")
+		if url, ok := strings.CutPrefix(spec, "@"); ok {
+			// direct reference, e.g. name @ https://example.com/name.whl
+			spec = url
+		} else if strings.Contains(spec, "://") {
+			// urls without a name
+			continue
+		}
+		if pinned, ok := strings.CutPrefix(spec, "=="); ok && !strings.Contains(pinned, ",") {
+			spec = pinned
+		}
+		deps[strings.ToLower(separatorsRe.ReplaceAllString(match[1], "-"))] = spec
+	}
+	return deps
+}
@@ -0,0 +1,136 @@
+package plugins_test
+
+import (
+	"testing"
+
+	"github.com/marqeta/pr-bot/opa/input/plugins"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEcosystem(t *testing.T) {
+	tests := []struct {
+		file string
+		want string
+	}{
+		{file: "go.mod", want: plugins.EcosystemGo},
+		{file: "tools/go.mod", want: plugins.EcosystemGo},
+		{file: "go.sum", want: ""},
+		{file: "web/package.json", want: plugins.EcosystemNPM},
+		{file: "web/package-lock.json", want: ""},
+		{file: "requirements.txt", want: plugins.EcosystemPip},
+		{file: "requirements-dev.txt", want: plugins.EcosystemPip},
+		{file: "README.md", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			assert.Equal(t, tt.want, plugins.Ecosystem(tt.file))
+		})
+	}
+}
+
+func TestParseManifest(t *testing.T) {
+	tests := []struct {
+		name      string
+		ecosystem string
+		content   string
+		want      []plugins.Dependency
+		wantErr   bool
+	}{
+		{
+			name:      "Should return required modules of go.mod",
+			ecosystem: plugins.EcosystemGo,
+			content: `module github.com/example/app
+
+go 1.24
+
+require github.com/google/uuid v1.6.0
+
+require (
+	github.com/stretchr/testify v1.9.0
+	golang.org/x/net v0.30.0 // indirect
+)
+
+replace (
+	github.com/google/uuid => ../uuid
+)
+`,
+			want: []plugins.Dependency{
+				{Name: "github.com/google/uuid", Version: "v1.6.0"},
+				{Name: "github.com/stretchr/testify", Version: "v1.9.0"},
+				{Name: "golang.org/x/net", Version: "v0.30.0"},
+			},
+		},
+		{
+			name:      "Should return every kind of dependency of package.json",
+			ecosystem: plugins.EcosystemNPM,
+			content: `{"name": "app", "dependencies": {"react": "^18.2.0"},
+				"devDependencies": {"jest": "~29.7.0", "react": "^18.3.0"}, "peerDependencies": {"react-dom": ">=18"},
+				"optionalDependencies": {"fsevents": "^2.3.0"}}`,
+			want: []plugins.Dependency{
+				{Name: "fsevents", Version: "^2.3.0", Kind: "optionalDependencies"},
+				{Name: "jest", Version: "~29.7.0", Kind: "devDependencies"},
+				{Name: "react", Version: "^18.2.0", Kind: "dependencies"},
+				{Name: "react", Version: "^18.3.0", Kind: "devDependencies"},
+				{Name: "react-dom", Version: ">=18", Kind: "peerDependencies"},
+			},
+		},
+		{
+			name:      "Should return error for invalid package.json",
+			ecosystem: plugins.EcosystemNPM,
+			content:   `{"dependencies": [}`,
+			wantErr:   true,
+		},
+		{
+			name:      "Should return requirements by normalized name",
+			ecosystem: plugins.EcosystemPip,
+			content: `# pinned
+Django==4.2.7
+requests[security] >= 2.31, < 3  # range
+typing_extensions==4.8.0 ; python_version < "3.11"
+my-pkg @ https://example.com/my_pkg-1.0.whl
+-r requirements-base.txt
+-e .
+https://example.com/other.whl
+`,
+			want: []plugins.Dependency{
+				{Name: "django", Version: "4.2.7"},
+				{Name: "my-pkg", Version: "https://example.com/my_pkg-1.0.whl"},
+				{Name: "requests", Version: ">=2.31,<3"},
+				{Name: "typing-extensions", Version: "4.8.0"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := plugins.ParseManifest(tt.ecosystem, tt.content)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, plugins.ErrInvalidManifest)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBump(t *testing.T) {
+	tests := []struct {
+		from string
+		to   string
+		want string
+	}{
+		{from: "v1.2.3", to: "v2.0.0", want: "major"},
+		{from: "^1.2.3", to: "^1.3.0", want: "minor"},
+		{from: "4.2.7", to: "4.2.8", want: "patch"},
+		{from: "~1.2", to: "~1.2.1", want: "patch"},
+		{from: "v1.2.3", to: "v1.2.2", want: "downgrade"},
+		{from: "v1.2.3-rc.1", to: "v1.2.3", want: "other"},
+		{from: ">=2.31,<3", to: ">=2.32,<3", want: "other"},
+		{from: "latest", to: "1.0.0", want: "other"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			assert.Equal(t, tt.want, plugins.Bump(tt.from, tt.to))
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v50/github"
+	"github.com/jonboulle/clockwork"
+	gh "github.com/marqeta/pr-bot/github"
+	"github.com/marqeta/pr-bot/id"
+	"github.com/marqeta/pr-bot/opa/input"
+)
+
+// latestReviewers is the number of reviewers, latest first, whose membership is resolved.
+const latestReviewers = 20
+
+// Member is the membership of a user in the org and repo of the PR.
+type Member struct {
+	Login string `json:"login"`
+	// Bot is true for GitHub apps, whose org role, permission and teams are not resolved.
+	Bot bool `json:"bot"`
+	// ServiceAccount is true for users configured as service accounts.
+	ServiceAccount bool `json:"service_account"`
+	// OrgRole is admin or member, empty when the user is not a member of the org.
+	OrgRole string `json:"org_role"`
+	// OutsideCollaborator is true when the user is a collaborator of the repo without being a member of the org.
+	OutsideCollaborator bool `json:"outside_collaborator"`
+	// Permission is admin, write, read or none.
+	Permission string   `json:"permission"`
+	Teams      []string `json:"teams"`
+}
+
+// Memberships is the input message of the membership plugin.
+type Memberships struct {
+	Author    Member   `json:"author"`
+	Reviewers []Member `json:"reviewers"`
+}
+
+// orgMembership is the role and teams of a user in an org.
+type orgMembership struct {
+	role  string
+	teams []string
+}
+
+type cached[T any] struct {
+	value    T
+	expireAt time.Time
+}
+
+// ttlCache holds values for ttl, expired values are swept on put.
+type ttlCache[T any] struct {
+	mu        sync.Mutex
+	entries   map[string]cached[T]
+	nextSweep time.Time
+}
+
+func newTTLCache[T any]() *ttlCache[T] {
+	return &ttlCache[T]{entries: make(map[string]cached[T])}
+}
+
+func (c *ttlCache[T]) get(key string, now time.Time) (T, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok || !now.Before(e.expireAt) {
+		var zero T
+		return zero, false
+	}
+	return e.value, true
+}
+
+func (c *ttlCache[T]) put(key string, value T, now time.Time, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if now.After(c.nextSweep) {
+		// drop expired values of users who are not seen anymore
+		for k, e := range c.entries {
+			if !now.Before(e.expireAt) {
+				delete(c.entries, k)
+			}
+		}
+		c.nextSweep = now.Add(ttl)
+	}
+	c.entries[key] = cached[T]{value: value, expireAt: now.Add(ttl)}
+}
+
+type membership struct {
+	dao             gh.API
+	serviceAccounts []string
+	ttl             time.Duration
+	clock           clockwork.Clock
+	// orgs caches org memberships by owner and login, permissions and collaborators by owner, repo and login
+	orgs          *ttlCache[orgMembership]
+	permissions   *ttlCache[string]
+	collaborators *ttlCache[bool]
+}
+
+// GetInputMsg implements input.Plugin.
+func (m *membership) GetInputMsg(ctx context.Context, ghe input.GHE) (json.RawMessage, error) {
+	id := ghe.ToID()
+	isOrg := ghe.Organization != nil
+	author, err := m.member(ctx, id, ghe.PullRequest.GetUser(), isOrg)
+	teamsTruncated := errors.Is(err, gh.ErrTruncated)
+	if err != nil && !teamsTruncated {
+		return json.RawMessage{}, err
+	}
+
+	reviews, err := m.dao.ListReviews(ctx, id)
+	reviewsTruncated := errors.Is(err, gh.ErrTruncated)
+	if err != nil && !reviewsTruncated {
+		return json.RawMessage{}, err
+	}
+	msg := Memberships{Author: author, Reviewers: make([]Member, 0)}
+	for _, user := range reviewers(reviews, author.Login) {
+		reviewer, err := m.member(ctx, id, user, isOrg)
+		if errors.Is(err, gh.ErrTruncated) {
+			teamsTruncated = true
+		} else if err != nil {
+			return json.RawMessage{}, err
+		}
+		msg.Reviewers = append(msg.Reviewers, reviewer)
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return json.RawMessage{}, err
+	}
+	if reviewsTruncated {
+		// the latest reviewers may be left out
+		return json.RawMessage(data), fmt.Errorf("%w: %d reviews", input.ErrTruncated, len(reviews))
+	}
+	if teamsTruncated {
+		return json.RawMessage(data), fmt.Errorf("%w: teams of users", input.ErrTruncated)
+	}
+	return json.RawMessage(data), nil
+}
+
+// Name implements input.Plugin.
+func (m *membership) Name() string {
+	return "membership"
+}
+
+// NewMembership returns a plugin resolving the org role, teams and repo permission of the author
+// and latest reviewers of the PR, users whose login is in serviceAccounts are flagged as service accounts.
+// org roles and teams are cached per org and user for ttl, repo permissions and collaborators per repo and user.
+func NewMembership(dao gh.API, serviceAccounts []string, ttl time.Duration, clock clockwork.Clock) input.Plugin {
+	return &membership{
+		dao:             dao,
+		serviceAccounts: serviceAccounts,
+		ttl:             ttl,
+		clock:           clock,
+		orgs:            newTTLCache[orgMembership](),
+		permissions:     newTTLCache[string](),
+		collaborators:   newTTLCache[bool](),
+	}
+}
+
+// member returns the membership of user, from the cache when it is not expired.
+// memberships with truncated teams are returned with an error wrapping gh.ErrTruncated and are not cached.
+func (m *membership) member(ctx context.Context, pr id.PR, user *github.User, isOrg bool) (Member, error) {
+	member := Member{
+		Login:          user.GetLogin(),
+		Bot:            user.GetType() == "Bot" || strings.HasSuffix(user.GetLogin(), "[bot]"),
+		ServiceAccount: slices.Contains(m.serviceAccounts, user.GetLogin()),
+		Teams:          make([]string, 0),
+	}
+	if member.Bot {
+		return member, nil
+	}
+
+	var err error
+	member.Permission, err = m.permission(ctx, pr, member.Login)
+	if err != nil {
+		return Member{}, err
+	}
+	if !isOrg {
+		return member, nil
+	}
+	org, err := m.orgMembership(ctx, pr, member.Login)
+	if err != nil && !errors.Is(err, gh.ErrTruncated) {
+		return Member{}, err
+	}
+	member.OrgRole = org.role
+	member.Teams = org.teams
+	if member.OrgRole == "" {
+		// non members can read public repos without being collaborators, teams are not listed for them
+		member.OutsideCollaborator, err = m.collaborator(ctx, pr, member.Login)
+		if err != nil {
+			return Member{}, err
+		}
+	}
+	return member, err
+}
+
+func (m *membership) permission(ctx context.Context, pr id.PR, login string) (string, error) {
+	key := fmt.Sprintf("%s/%s/%s", pr.Owner, pr.Repo, login)
+	if permission, ok := m.permissions.get(key, m.clock.Now()); ok {
+		return permission, nil
+	}
+	permission, err := m.dao.GetPermissionLevel(ctx, pr, login)
+	if err != nil {
+		return "", err
+	}
+	m.permissions.put(key, permission, m.clock.Now(), m.ttl)
+	return permission, nil
+}
+
+func (m *membership) collaborator(ctx context.Context, pr id.PR, login string) (bool, error) {
+	key := fmt.Sprintf("%s/%s/%s", pr.Owner, pr.Repo, login)
+	if collaborator, ok := m.collaborators.get(key, m.clock.Now()); ok {
+		return collaborator, nil
+	}
+	collaborator, err := m.dao.IsCollaborator(ctx, pr, login)
+	if err != nil {
+		return false, err
+	}
+	m.collaborators.put(key, collaborator, m.clock.Now(), m.ttl)
+	return collaborator, nil
+}
+
+func (m *membership) orgMembership(ctx context.Context, pr id.PR, login string) (orgMembership, error) {
+	key := fmt.Sprintf("%s/%s", pr.Owner, login)
+	if org, ok := m.orgs.get(key, m.clock.Now()); ok {
+		return org, nil
+	}
+	role, err := m.dao.GetOrgRole(ctx, pr, login)
+	if err != nil {
+		return orgMembership{}, err
+	}
+	org := orgMembership{role: role, teams: make([]string, 0)}
+	if role != "" {
+		org.teams, err = m.dao.ListTeamsOfUser(ctx, pr, login)
+		if errors.Is(err, gh.ErrTruncated) {
+			return org, err
+		}
+		if err != nil {
+			return orgMembership{}, err
+		}
+	}
+	m.orgs.put(key, org, m.clock.Now(), m.ttl)
+	return org, nil
+}
+
+// reviewers returns the users who reviewed the PR, latest first, leaving out the author.
+func reviewers(reviews []*github.PullRequestReview, author string) []*github.User {
+	reviews = slices.Clone(reviews)
+	sort.SliceStable(reviews, func(i, j int) bool {
+		return reviews[i].GetSubmittedAt().After(reviews[j].GetSubmittedAt().Time)
+	})
+	users := make([]*github.User, 0)
+	seen := map[string]bool{author: true}
+	for _, r := range reviews {
+		login := r.GetUser().GetLogin()
+		if login == "" || seen[login] {
+			continue
+		}
+		seen[login] = true
+		users = append(users, r.GetUser())
+		if len(users) == latestReviewers {
+			break
+		}
+	}
+	return users
+}
@@ -0,0 +1,311 @@
+package plugins_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/google/go-github/v50/github"
+	"github.com/jonboulle/clockwork"
+	gh "github.com/marqeta/pr-bot/github"
+	"github.com/marqeta/pr-bot/opa/input"
+	"github.com/marqeta/pr-bot/opa/input/plugins"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMembership_GetInputMsg(t *testing.T) {
+	ctx := context.TODO()
+	//nolint:goerr113
+	randomErr := errors.New("random error")
+	ghe := withOrg(randomGHE())
+	pr := ghe.ToID()
+	author := plugins.Member{
+		Login:      "random Login",
+		OrgRole:    "member",
+		Permission: "write",
+		Teams:      []string{"random Login/backend"},
+	}
+	outside := plugins.Member{
+		Login:               "outside",
+		OutsideCollaborator: true,
+		Permission:          "read",
+		Teams:               []string{},
+	}
+	svc := plugins.Member{
+		Login:          "svc-deploy",
+		ServiceAccount: true,
+		OrgRole:        "admin",
+		Permission:     "admin",
+		Teams:          []string{},
+	}
+	reader := plugins.Member{
+		Login:      "reader",
+		Permission: "read",
+		Teams:      []string{},
+	}
+	bot := plugins.Member{
+		Login: "dependabot[bot]",
+		Bot:   true,
+		Teams: []string{},
+	}
+	expectAuthor := func(d *gh.MockAPI) {
+		d.EXPECT().GetPermissionLevel(ctx, pr, "random Login").Return("write", nil)
+		d.EXPECT().GetOrgRole(ctx, pr, "random Login").Return("member", nil)
+		d.EXPECT().ListTeamsOfUser(ctx, pr, "random Login").Return([]string{"random Login/backend"}, nil)
+	}
+	type args struct {
+		ghe             input.GHE
+		setExpectations func(d *gh.MockAPI)
+	}
+	tests := []struct {
+		name          string
+		args          args
+		want          json.RawMessage
+		wantErr       bool
+		wantTruncated bool
+	}{
+		{
+			name: "Should return membership of author and latest reviewers",
+			args: args{
+				ghe: ghe,
+				setExpectations: func(d *gh.MockAPI) {
+					expectAuthor(d)
+					d.EXPECT().ListReviews(ctx, pr).Return([]*github.PullRequestReview{
+						reviewBy("outside", 1), reviewBy("random Login", 2), reviewBy("svc-deploy", 3),
+						reviewBy("dependabot[bot]", 4), reviewBy("outside", 5),
+					}, nil)
+					d.EXPECT().GetPermissionLevel(ctx, pr, "outside").Return("read", nil)
+					d.EXPECT().GetOrgRole(ctx, pr, "outside").Return("", nil)
+					d.EXPECT().IsCollaborator(ctx, pr, "outside").Return(true, nil)
+					d.EXPECT().GetPermissionLevel(ctx, pr, "svc-deploy").Return("admin", nil)
+					d.EXPECT().GetOrgRole(ctx, pr, "svc-deploy").Return("admin", nil)
+					d.EXPECT().ListTeamsOfUser(ctx, pr, "svc-deploy").Return([]string{}, nil)
+				},
+			},
+			want: toJSON(t, plugins.Memberships{
+				Author:    author,
+				Reviewers: []plugins.Member{outside, bot, svc},
+			}),
+			wantErr: false,
+		},
+		{
+			name: "Should not flag users reading a public repo as outside collaborators",
+			args: args{
+				ghe: ghe,
+				setExpectations: func(d *gh.MockAPI) {
+					expectAuthor(d)
+					d.EXPECT().ListReviews(ctx, pr).Return([]*github.PullRequestReview{reviewBy("reader", 1)}, nil)
+					d.EXPECT().GetPermissionLevel(ctx, pr, "reader").Return("read", nil)
+					d.EXPECT().GetOrgRole(ctx, pr, "reader").Return("", nil)
+					d.EXPECT().IsCollaborator(ctx, pr, "reader").Return(false, nil)
+				},
+			},
+			want: toJSON(t, plugins.Memberships{
+				Author:    author,
+				Reviewers: []plugins.Member{reader},
+			}),
+			wantErr: false,
+		},
+		{
+			name: "Should flag only configured logins as service accounts",
+			args: args{
+				ghe: ghe,
+				setExpectations: func(d *gh.MockAPI) {
+					expectAuthor(d)
+					d.EXPECT().ListReviews(ctx, pr).Return([]*github.PullRequestReview{reviewBy("svc-other", 1)}, nil)
+					d.EXPECT().GetPermissionLevel(ctx, pr, "svc-other").Return("write", nil)
+					d.EXPECT().GetOrgRole(ctx, pr, "svc-other").Return("member", nil)
+					d.EXPECT().ListTeamsOfUser(ctx, pr, "svc-other").Return([]string{}, nil)
+				},
+			},
+			want: toJSON(t, plugins.Memberships{
+				Author: author,
+				Reviewers: []plugins.Member{
+					{Login: "svc-other", OrgRole: "member", Permission: "write", Teams: []string{}},
+				},
+			}),
+			wantErr: false,
+		},
+		{
+			name: "Should return error when dao returns error checking collaborators",
+			args: args{
+				ghe: ghe,
+				setExpectations: func(d *gh.MockAPI) {
+					expectAuthor(d)
+					d.EXPECT().ListReviews(ctx, pr).Return([]*github.PullRequestReview{reviewBy("reader", 1)}, nil)
+					d.EXPECT().GetPermissionLevel(ctx, pr, "reader").Return("read", nil)
+					d.EXPECT().GetOrgRole(ctx, pr, "reader").Return("", nil)
+					d.EXPECT().IsCollaborator(ctx, pr, "reader").Return(false, randomErr)
+				},
+			},
+			want:    json.RawMessage{},
+			wantErr: true,
+		},
+		{
+			name: "Should not resolve org role of users when repo is not owned by an org",
+			args: args{
+				ghe: randomGHE(),
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().GetPermissionLevel(ctx, pr, "random Login").Return("admin", nil)
+					d.EXPECT().ListReviews(ctx, pr).Return([]*github.PullRequestReview{}, nil)
+				},
+			},
+			want: toJSON(t, plugins.Memberships{
+				Author:    plugins.Member{Login: "random Login", Permission: "admin", Teams: []string{}},
+				Reviewers: []plugins.Member{},
+			}),
+			wantErr: false,
+		},
+		{
+			name: "Should return reviewers listed before the cap",
+			args: args{
+				ghe: ghe,
+				setExpectations: func(d *gh.MockAPI) {
+					expectAuthor(d)
+					d.EXPECT().ListReviews(ctx, pr).Return([]*github.PullRequestReview{reviewBy("dependabot[bot]", 1)},
+						fmt.Errorf("reviews of PR: %w", gh.ErrTruncated))
+				},
+			},
+			want: toJSON(t, plugins.Memberships{
+				Author:    author,
+				Reviewers: []plugins.Member{bot},
+			}),
+			wantErr:       false,
+			wantTruncated: true,
+		},
+		{
+			name: "Should return teams listed before the cap",
+			args: args{
+				ghe: ghe,
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().GetPermissionLevel(ctx, pr, "random Login").Return("write", nil)
+					d.EXPECT().GetOrgRole(ctx, pr, "random Login").Return("member", nil)
+					d.EXPECT().ListTeamsOfUser(ctx, pr, "random Login").
+						Return([]string{"random Login/backend"}, fmt.Errorf("teams of user: %w", gh.ErrTruncated))
+					d.EXPECT().ListReviews(ctx, pr).Return([]*github.PullRequestReview{}, nil)
+				},
+			},
+			want: toJSON(t, plugins.Memberships{
+				Author:    author,
+				Reviewers: []plugins.Member{},
+			}),
+			wantErr:       false,
+			wantTruncated: true,
+		},
+		{
+			name: "Should return error when dao returns error resolving author",
+			args: args{
+				ghe: ghe,
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().GetPermissionLevel(ctx, pr, "random Login").Return("write", nil)
+					d.EXPECT().GetOrgRole(ctx, pr, "random Login").Return("", randomErr)
+				},
+			},
+			want:    json.RawMessage{},
+			wantErr: true,
+		},
+		{
+			name: "Should return error when dao returns error listing reviews",
+			args: args{
+				ghe: ghe,
+				setExpectations: func(d *gh.MockAPI) {
+					expectAuthor(d)
+					d.EXPECT().ListReviews(ctx, pr).Return(nil, randomErr)
+				},
+			},
+			want:    json.RawMessage{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := gh.NewMockAPI(t)
+			tt.args.setExpectations(d)
+			m := plugins.NewMembership(d, []string{"svc-deploy"}, time.Minute, clockwork.NewFakeClock())
+			got, err := m.GetInputMsg(ctx, tt.args.ghe)
+			truncated := errors.Is(err, input.ErrTruncated)
+			if (err != nil && !truncated) != tt.wantErr {
+				t.Errorf("Membership.GetInputMsg() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if truncated != tt.wantTruncated {
+				t.Errorf("Membership.GetInputMsg() truncated = %v, wantTruncated %v", truncated, tt.wantTruncated)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Membership.GetInputMsg() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMembership_Cache(t *testing.T) {
+	ctx := context.TODO()
+	ghe := withOrg(randomGHE())
+	pr := ghe.ToID()
+	clock := clockwork.NewFakeClock()
+	d := gh.NewMockAPI(t)
+	d.EXPECT().ListReviews(ctx, pr).Return([]*github.PullRequestReview{}, nil).Times(3)
+	d.EXPECT().GetPermissionLevel(ctx, pr, "random Login").Return("write", nil).Times(2)
+	d.EXPECT().GetOrgRole(ctx, pr, "random Login").Return("member", nil).Times(2)
+	d.EXPECT().ListTeamsOfUser(ctx, pr, "random Login").Return([]string{}, nil).Times(2)
+	m := plugins.NewMembership(d, []string{"svc-deploy"}, time.Minute, clock)
+
+	first, err := m.GetInputMsg(ctx, ghe)
+	assert.Nil(t, err)
+	// cached membership is returned until it expires
+	clock.Advance(59 * time.Second)
+	cached, err := m.GetInputMsg(ctx, ghe)
+	assert.Nil(t, err)
+	assert.Equal(t, first, cached)
+	// expired membership is resolved again
+	clock.Advance(time.Second)
+	_, err = m.GetInputMsg(ctx, ghe)
+	assert.Nil(t, err)
+}
+
+func TestMembership_Cache_OtherRepo(t *testing.T) {
+	ctx := context.TODO()
+	ghe := withOrg(randomGHE())
+	other := withOrg(randomGHE())
+	other.Repository = &github.Repository{
+		Name:     aws.String("other Repo"),
+		FullName: aws.String("random Login/other Repo"),
+		Owner:    ghe.Repository.Owner,
+	}
+	d := gh.NewMockAPI(t)
+	d.EXPECT().ListReviews(ctx, ghe.ToID()).Return([]*github.PullRequestReview{}, nil)
+	d.EXPECT().ListReviews(ctx, other.ToID()).Return([]*github.PullRequestReview{}, nil)
+	d.EXPECT().GetPermissionLevel(ctx, ghe.ToID(), "random Login").Return("write", nil)
+	d.EXPECT().GetPermissionLevel(ctx, other.ToID(), "random Login").Return("read", nil)
+	// org role and teams are shared by the repos of the org
+	d.EXPECT().GetOrgRole(ctx, ghe.ToID(), "random Login").Return("member", nil).Once()
+	d.EXPECT().ListTeamsOfUser(ctx, ghe.ToID(), "random Login").Return([]string{"org/team1"}, nil).Once()
+	m := plugins.NewMembership(d, []string{"svc-deploy"}, time.Minute, clockwork.NewFakeClock())
+
+	_, err := m.GetInputMsg(ctx, ghe)
+	assert.Nil(t, err)
+	got, err := m.GetInputMsg(ctx, other)
+	assert.Nil(t, err)
+	assert.Equal(t, toJSON(t, plugins.Memberships{
+		Author: plugins.Member{Login: "random Login", OrgRole: "member", Permission: "read",
+			Teams: []string{"org/team1"}},
+		Reviewers: []plugins.Member{},
+	}), got)
+}
+
+func withOrg(ghe input.GHE) input.GHE {
+	ghe.Organization = &github.Organization{Login: aws.String("random Login")}
+	return ghe
+}
+
+func reviewBy(login string, minute int) *github.PullRequestReview {
+	return &github.PullRequestReview{
+		User:        &github.User{Login: aws.String(login)},
+		SubmittedAt: &github.Timestamp{Time: time.Date(2024, 1, 1, 0, minute, 0, 0, time.UTC)},
+	}
+}
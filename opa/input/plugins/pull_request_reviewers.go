@@ -3,6 +3,8 @@ package plugins
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"sort"
 
 	"github.com/google/go-github/v50/github"
@@ -17,7 +19,8 @@ type PullRequestReviewers struct {
 func (prr *PullRequestReviewers) GetInputMsg(ctx context.Context, ghe input.GHE) (json.RawMessage, error) {
 	// 1. Fetch all reviews (note: []*github.PullRequestReview)
 	reviews, err := prr.dao.ListReviews(ctx, ghe.ToID())
-	if err != nil {
+	listTruncated := errors.Is(err, gh.ErrTruncated)
+	if err != nil && !listTruncated {
 		return nil, err
 	}
 
@@ -55,6 +58,10 @@ func (prr *PullRequestReviewers) GetInputMsg(ctx context.Context, ghe input.GHE)
 	if err != nil {
 		return json.RawMessage{}, err
 	}
+	if listTruncated {
+		// the latest reviews of some users may be left out
+		return json.RawMessage(data), fmt.Errorf("%w: %d reviews", input.ErrTruncated, len(reviews))
+	}
 
 	return json.RawMessage(data), nil
 }
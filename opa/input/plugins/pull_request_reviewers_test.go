@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -179,3 +180,21 @@ func sampleGHE() input.GHE {
 		},
 	}
 }
+
+func TestPullRequestReviewers_GetInputMsg_Truncated(t *testing.T) {
+	ctx := context.Background()
+	api := gh.NewMockAPI(t)
+	api.EXPECT().ListReviews(ctx, sampleGHE().ToID()).
+		Return([]*github.PullRequestReview{
+			{
+				User:  &github.User{Login: github.String("user1")},
+				State: github.String("APPROVED"),
+			},
+		}, fmt.Errorf("reviews of PR: %w", gh.ErrTruncated))
+
+	raw, err := NewPullRequestReviewers(api).GetInputMsg(ctx, sampleGHE())
+	assert.ErrorIs(t, err, input.ErrTruncated)
+	var reviews []*github.PullRequestReview
+	assert.NoError(t, json.Unmarshal(raw, &reviews))
+	assert.Len(t, reviews, 1)
+}
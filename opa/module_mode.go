@@ -0,0 +1,91 @@
+package opa
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ModuleMode controls how the result of a module is used.
+type ModuleMode string
+
+const (
+	// Enforce modules are evaluated and their results are coalesced into the review.
+	Enforce ModuleMode = "enforce"
+	// Shadow modules are evaluated and recorded in the report, but do not affect the review.
+	Shadow ModuleMode = "shadow"
+	// Disabled modules are not evaluated.
+	Disabled ModuleMode = "disabled"
+)
+
+// FailureMode controls how an error evaluating a module is handled.
+type FailureMode string
+
+const (
+	// FailAbort fails the entire evaluation, no review is posted.
+	FailAbort FailureMode = "abort"
+	// FailOpen records the error in the report and ignores the module.
+	FailOpen FailureMode = "open"
+	// FailRequestChanges turns the error into a request changes review.
+	FailRequestChanges FailureMode = "request_changes"
+	// FailComment turns the error into a comment review.
+	FailComment FailureMode = "comment"
+)
+
+// FailClosedMsg is the review body of modules which failed closed.
+const FailClosedMsg = "Policy module `%s` could not be evaluated, see the evaluation report for the error."
+
+var (
+	ErrInvalidModuleMode  = errors.New("invalid module mode")
+	ErrInvalidFailureMode = errors.New("invalid module failure mode")
+)
+
+// ModuleCfg is the dynamic config holding the mode and failure mode of each module,
+// modules which are not configured are enforced and abort the evaluation on errors.
+type ModuleCfg struct {
+	Modes    map[string]string `dynamodbav:"modes"`
+	Failures map[string]string `dynamodbav:"failures"`
+	modes    map[string]ModuleMode
+	failures map[string]FailureMode
+}
+
+// Update implements configstore.DynamicConfig.
+func (c *ModuleCfg) Update() error {
+	c.modes = make(map[string]ModuleMode, len(c.Modes))
+	for module, m := range c.Modes {
+		mode := ModuleMode(strings.ToLower(strings.TrimSpace(m)))
+		switch mode {
+		case Enforce, Shadow, Disabled:
+			c.modes[module] = mode
+		default:
+			return fmt.Errorf("%w: %v for module %v", ErrInvalidModuleMode, m, module)
+		}
+	}
+	c.failures = make(map[string]FailureMode, len(c.Failures))
+	for module, f := range c.Failures {
+		failure := FailureMode(strings.ToLower(strings.TrimSpace(f)))
+		switch failure {
+		case FailAbort, FailOpen, FailRequestChanges, FailComment:
+			c.failures[module] = failure
+		default:
+			return fmt.Errorf("%w: %v for module %v", ErrInvalidFailureMode, f, module)
+		}
+	}
+	return nil
+}
+
+// Mode returns the mode of the module.
+func (c *ModuleCfg) Mode(module string) ModuleMode {
+	if mode, ok := c.modes[module]; ok {
+		return mode
+	}
+	return Enforce
+}
+
+// Failure returns the failure mode of the module.
+func (c *ModuleCfg) Failure(module string) FailureMode {
+	if failure, ok := c.failures[module]; ok {
+		return failure
+	}
+	return FailAbort
+}
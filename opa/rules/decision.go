@@ -0,0 +1,249 @@
+package rules
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/marqeta/pr-bot/opa/client"
+	"github.com/marqeta/pr-bot/opa/input"
+	"github.com/marqeta/pr-bot/opa/types"
+	"github.com/open-policy-agent/opa/v1/sdk"
+)
+
+var ErrInvalidDecision = errors.New("invalid decision")
+
+// Decision evaluates a single rule returning the entire decision of a module.
+type Decision struct {
+	RuleName string
+	client   client.Client
+}
+
+// decisionDoc is the object returned by the decision rule.
+// enums are decoded as strings, so that invalid values are reported with the path of the field.
+type decisionDoc struct {
+	Track     *bool            `json:"track"`
+	Review    *reviewDoc       `json:"review"`
+	Labels    *types.Labels    `json:"labels"`
+	Reviewers *types.Reviewers `json:"reviewers"`
+	Merge     *mergeDoc        `json:"merge"`
+	Comments  []commentDoc     `json:"comments"`
+}
+
+type reviewDoc struct {
+	Type string `json:"type"`
+	Body string `json:"body"`
+}
+
+type mergeDoc struct {
+	Method    string `json:"method"`
+	AutoMerge *bool  `json:"auto_merge"`
+}
+
+type commentDoc struct {
+	Path     string `json:"path"`
+	Line     int    `json:"line"`
+	Side     string `json:"side"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// Evaluate implements Rules.
+func (d *Decision) Evaluate(
+	ctx context.Context, module string, input *input.Model) (types.Result, error) {
+
+	opt := sdk.DecisionOptions{
+		Path:       d.client.Path(module, d.RuleName),
+		Input:      input,
+		DecisionID: d.client.DecisionID(ctx),
+	}
+
+	result, err := d.client.Decision(ctx, opt)
+	if err != nil {
+		return types.Result{}, err
+	}
+	if _, ok := result.Result.(map[string]any); !ok {
+		return types.Result{}, fmt.Errorf("%w rule: %s, expected: object, got: %T", ErrInvalidReturnType, d.RuleName, result.Result)
+	}
+
+	doc, err := decode(result.Result)
+	if err != nil {
+		return types.Result{}, fmt.Errorf("%w of module %s: %s", ErrInvalidDecision, module, d.describe(err))
+	}
+	r, problems := validate(doc)
+	if len(problems) > 0 {
+		for i, p := range problems {
+			problems[i] = fmt.Sprintf("%s.%s", d.RuleName, p)
+		}
+		return types.Result{}, fmt.Errorf("%w of module %s: %s", ErrInvalidDecision, module, strings.Join(problems, "; "))
+	}
+	return r, nil
+}
+
+func decode(v any) (decisionDoc, error) {
+	var doc decisionDoc
+	b, err := json.Marshal(v)
+	if err != nil {
+		return doc, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	err = dec.Decode(&doc)
+	return doc, err
+}
+
+// describe returns a decoding error in terms of the fields of the decision.
+func (d *Decision) describe(err error) string {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return fmt.Sprintf("%s.%s: expected %s, got %s", d.RuleName, typeErr.Field, jsonType(typeErr.Type), typeErr.Value)
+	}
+	if msg, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return fmt.Sprintf("%s: unknown field %s", d.RuleName, msg)
+	}
+	return err.Error()
+}
+
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "integer"
+	case reflect.String:
+		return "string"
+	case reflect.Slice:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// validate converts the decision into a result,
+// returns all problems with the decision, so that they can be fixed at once.
+func validate(doc decisionDoc) (types.Result, []string) {
+	var problems []string
+	invalid := func(format string, a ...any) {
+		problems = append(problems, fmt.Sprintf(format, a...))
+	}
+	r := types.Result{}
+
+	if doc.Track == nil {
+		invalid("track: is required")
+	} else {
+		r.Track = *doc.Track
+	}
+
+	if doc.Review == nil {
+		if r.Track {
+			invalid("review: is required when track is true")
+		}
+	} else {
+		reviewType, err := types.ParseReviewType(doc.Review.Type)
+		if err != nil {
+			invalid("review.type: %q is not one of SKIP, APPROVE, COMMENT, REQUEST_CHANGES, DISMISS", doc.Review.Type)
+		}
+		r.Review = types.Review{Type: reviewType}
+		switch reviewType {
+		case types.Skip:
+			// body is not used when skipping
+		case types.Comment, types.RequestChanges, types.Dismiss:
+			if strings.TrimSpace(doc.Review.Body) == "" {
+				invalid("review.body: is required for review type %v", reviewType)
+			}
+			r.Review.Body = doc.Review.Body
+		default:
+			r.Review.Body = doc.Review.Body
+		}
+	}
+
+	if doc.Labels != nil {
+		for i, l := range doc.Labels.Add {
+			if strings.TrimSpace(l) == "" {
+				invalid("labels.add[%d]: must not be empty", i)
+			}
+		}
+		for i, l := range doc.Labels.Remove {
+			if strings.TrimSpace(l) == "" {
+				invalid("labels.remove[%d]: must not be empty", i)
+			}
+			for _, a := range doc.Labels.Add {
+				if strings.EqualFold(a, l) {
+					invalid("labels.remove[%d]: %q is also in labels.add", i, l)
+				}
+			}
+		}
+		r.Labels = doc.Labels
+	}
+
+	if doc.Reviewers != nil {
+		for i, u := range doc.Reviewers.Users {
+			if strings.TrimSpace(u) == "" {
+				invalid("reviewers.users[%d]: must not be empty", i)
+			}
+		}
+		for i, t := range doc.Reviewers.Teams {
+			if strings.TrimSpace(t) == "" {
+				invalid("reviewers.teams[%d]: must not be empty", i)
+			}
+		}
+		r.Reviewers = doc.Reviewers
+	}
+
+	if doc.Merge != nil {
+		method := types.MergeMethod(strings.ToUpper(strings.TrimSpace(doc.Merge.Method)))
+		switch method {
+		case "", types.MergeMethodMerge, types.MergeMethodSquash, types.MergeMethodRebase:
+		default:
+			invalid("merge.method: %q is not one of MERGE, SQUASH, REBASE", doc.Merge.Method)
+		}
+		r.Merge = &types.Merge{Method: method, AutoMerge: doc.Merge.AutoMerge}
+	}
+
+	for i, c := range doc.Comments {
+		comment := types.InlineComment{
+			Path:     c.Path,
+			Line:     c.Line,
+			Side:     types.Side(strings.ToUpper(strings.TrimSpace(c.Side))),
+			Severity: types.Severity(strings.ToLower(strings.TrimSpace(c.Severity))),
+			Message:  c.Message,
+		}
+		if strings.TrimSpace(c.Path) == "" {
+			invalid("comments[%d].path: is required", i)
+		}
+		if c.Line < 1 {
+			invalid("comments[%d].line: must be greater than 0, got %d", i, c.Line)
+		}
+		switch comment.Side {
+		case "":
+			comment.Side = types.Right
+		case types.Left, types.Right:
+		default:
+			invalid("comments[%d].side: %q is not one of LEFT, RIGHT", i, c.Side)
+		}
+		switch comment.Severity {
+		case "":
+			comment.Severity = types.Notice
+		case types.Notice, types.Warning, types.Failure:
+		default:
+			invalid("comments[%d].severity: %q is not one of notice, warning, failure", i, c.Severity)
+		}
+		if strings.TrimSpace(c.Message) == "" {
+			invalid("comments[%d].message: is required", i)
+		}
+		r.Comments = append(r.Comments, comment)
+	}
+
+	return r, problems
+}
+
+func NewDecision(ruleName string, client client.Client) Rules[types.Result] {
+	return &Decision{
+		RuleName: ruleName,
+		client:   client,
+	}
+}
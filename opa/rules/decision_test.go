@@ -0,0 +1,185 @@
+package rules_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/marqeta/pr-bot/opa/client"
+	"github.com/marqeta/pr-bot/opa/rules"
+	"github.com/marqeta/pr-bot/opa/types"
+	"github.com/open-policy-agent/opa/v1/sdk"
+)
+
+func TestDecision_Evaluate(t *testing.T) {
+	ctx := context.TODO()
+	//nolint:goerr113
+	randomErr := fmt.Errorf("random error")
+	tests := []struct {
+		name    string
+		result  any
+		err     error
+		want    types.Result
+		wantErr error
+		// wantMsg are substrings of the error, which policy authors would read
+		wantMsg []string
+	}{
+		{
+			name: "Should decode full decision",
+			result: map[string]any{
+				"track":     true,
+				"review":    map[string]any{"type": "approve", "body": "LGTM"},
+				"labels":    map[string]any{"add": []any{"auto-approved"}, "remove": []any{"needs-review"}},
+				"reviewers": map[string]any{"users": []any{"alice"}, "teams": []any{"infra"}},
+				"merge":     map[string]any{"method": "squash", "auto_merge": false},
+				"comments": []any{
+					map[string]any{"path": "main.tf", "line": 10, "message": "pin the version"},
+					map[string]any{"path": "old.tf", "line": 3, "side": "left", "severity": "FAILURE", "message": "do not delete"},
+				},
+			},
+			want: types.Result{
+				Track:     true,
+				Review:    types.Review{Type: types.Approve, Body: "LGTM"},
+				Labels:    &types.Labels{Add: []string{"auto-approved"}, Remove: []string{"needs-review"}},
+				Reviewers: &types.Reviewers{Users: []string{"alice"}, Teams: []string{"infra"}},
+				Merge:     &types.Merge{Method: types.MergeMethodSquash, AutoMerge: aws.Bool(false)},
+				Comments: []types.InlineComment{
+					{Path: "main.tf", Line: 10, Side: types.Right, Severity: types.Notice, Message: "pin the version"},
+					{Path: "old.tf", Line: 3, Side: types.Left, Severity: types.Failure, Message: "do not delete"},
+				},
+			},
+		},
+		{
+			name: "Should decode decision with only track and review",
+			result: map[string]any{
+				"track":  true,
+				"review": map[string]any{"type": "COMMENT", "body": "nit"},
+			},
+			want: types.Result{
+				Track:  true,
+				Review: types.Review{Type: types.Comment, Body: "nit"},
+			},
+		},
+		{
+			name: "Should drop body when skipping",
+			result: map[string]any{
+				"track":  true,
+				"review": map[string]any{"type": "SKIP", "body": "ignored"},
+			},
+			want: types.Result{
+				Track:  true,
+				Review: types.Review{Type: types.Skip},
+			},
+		},
+		{
+			name:   "Should not require review when track is false",
+			result: map[string]any{"track": false},
+			want:   types.Result{},
+		},
+		{
+			name:    "Should return error when decision evaluation fails",
+			err:     randomErr,
+			wantErr: randomErr,
+		},
+		{
+			name:    "Should return error when decision is not an object",
+			result:  true,
+			wantErr: rules.ErrInvalidReturnType,
+		},
+		{
+			name:    "Should return error for unknown fields",
+			result:  map[string]any{"track": true, "lables": map[string]any{}},
+			wantErr: rules.ErrInvalidDecision,
+			wantMsg: []string{`decision: unknown field "lables"`},
+		},
+		{
+			name:    "Should return error for fields of the wrong type",
+			result:  map[string]any{"track": "yes"},
+			wantErr: rules.ErrInvalidDecision,
+			wantMsg: []string{"decision.track: expected boolean, got string"},
+		},
+		{
+			name: "Should return error for nested fields of the wrong type",
+			result: map[string]any{
+				"track":    true,
+				"review":   map[string]any{"type": "APPROVE"},
+				"comments": []any{map[string]any{"path": "main.tf", "line": "ten", "message": "m"}},
+			},
+			wantErr: rules.ErrInvalidDecision,
+			wantMsg: []string{"decision.comments.", "line: expected integer, got string"},
+		},
+		{
+			name: "Should return all validation errors",
+			result: map[string]any{
+				"review":   map[string]any{"type": "LGTM"},
+				"labels":   map[string]any{"add": []any{"size/XL"}, "remove": []any{"size/xl", ""}},
+				"merge":    map[string]any{"method": "fast-forward"},
+				"comments": []any{map[string]any{"line": 0, "side": "up", "severity": "error"}},
+			},
+			wantErr: rules.ErrInvalidDecision,
+			wantMsg: []string{
+				"of module ci/module/asd",
+				"decision.track: is required",
+				`decision.review.type: "LGTM" is not one of`,
+				`decision.labels.remove[0]: "size/xl" is also in labels.add`,
+				"decision.labels.remove[1]: must not be empty",
+				`decision.merge.method: "fast-forward" is not one of MERGE, SQUASH, REBASE`,
+				"decision.comments[0].path: is required",
+				"decision.comments[0].line: must be greater than 0, got 0",
+				`decision.comments[0].side: "up" is not one of LEFT, RIGHT`,
+				`decision.comments[0].severity: "error" is not one of notice, warning, failure`,
+				"decision.comments[0].message: is required",
+			},
+		},
+		{
+			name:    "Should require review when track is true",
+			result:  map[string]any{"track": true},
+			wantErr: rules.ErrInvalidDecision,
+			wantMsg: []string{"decision.review: is required when track is true"},
+		},
+		{
+			name: "Should require body when requesting changes",
+			result: map[string]any{
+				"track":  true,
+				"review": map[string]any{"type": "REQUEST_CHANGES", "body": " "},
+			},
+			wantErr: rules.ErrInvalidDecision,
+			wantMsg: []string{"decision.review.body: is required for review type REQUEST_CHANGES"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := client.NewMockClient(t)
+			c.EXPECT().Path("ci/module/asd", "decision").Return("ci/module/asd/decision")
+			c.EXPECT().DecisionID(ctx).Return("random decision id")
+			var result *sdk.DecisionResult
+			if tt.err == nil {
+				result = &sdk.DecisionResult{ID: "random decision id", Result: tt.result}
+			}
+			c.EXPECT().Decision(ctx, sdk.DecisionOptions{
+				Path:       "ci/module/asd/decision",
+				Input:      randomModel(),
+				DecisionID: "random decision id",
+			}).Return(result, tt.err)
+
+			d := rules.NewDecision("decision", c)
+			got, err := d.Evaluate(ctx, "ci/module/asd", randomModel())
+			if !errors.Is(err, tt.wantErr) || (err != nil) != (tt.wantErr != nil) {
+				t.Errorf("Decision.Evaluate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			for _, msg := range tt.wantMsg {
+				if !strings.Contains(err.Error(), msg) {
+					t.Errorf("Decision.Evaluate() error = %v, want it to contain %v", err, msg)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Decision.Evaluate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package types
+
+// MergeMethod is the method used to merge an approved PR.
+type MergeMethod string
+
+const (
+	MergeMethodMerge  MergeMethod = "MERGE"
+	MergeMethodSquash MergeMethod = "SQUASH"
+	MergeMethodRebase MergeMethod = "REBASE"
+)
+
+// Merge controls how an approved PR is merged.
+type Merge struct {
+	// Method is chosen from the repo settings when empty.
+	Method MergeMethod `json:"method,omitempty"`
+	// AutoMerge enables auto merge when the PR is approved, defaults to true.
+	AutoMerge *bool `json:"auto_merge,omitempty"`
+}
+
+// Labels to add to and remove from a PR.
+type Labels struct {
+	Add    []string `json:"add,omitempty"`
+	Remove []string `json:"remove,omitempty"`
+}
+
+// Reviewers to request a review from.
+type Reviewers struct {
+	Users []string `json:"users,omitempty"`
+	// Teams are team slugs in the org of the repo.
+	Teams []string `json:"teams,omitempty"`
+}
+
+// Side of the diff an inline comment is on.
+type Side string
+
+const (
+	// Left is the base of the diff, for deleted lines.
+	Left Side = "LEFT"
+	// Right is the head of the diff, for added and unchanged lines.
+	Right Side = "RIGHT"
+)
+
+// Severity of an inline comment, policies which leave it out post notices.
+type Severity string
+
+const (
+	Notice  Severity = "notice"
+	Warning Severity = "warning"
+	Failure Severity = "failure"
+)
+
+// InlineComment annotates a line of a file changed in a PR.
+type InlineComment struct {
+	Path     string   `json:"path"`
+	Line     int      `json:"line"`
+	Side     Side     `json:"side,omitempty"`
+	Severity Severity `json:"severity,omitempty"`
+	Message  string   `json:"message"`
+}
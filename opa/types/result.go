@@ -93,4 +93,9 @@ type Review struct {
 type Result struct {
 	Track  bool
 	Review Review
+	// fields below are only returned by v2 policies
+	Labels    *Labels         `json:"Labels,omitempty"`
+	Reviewers *Reviewers      `json:"Reviewers,omitempty"`
+	Merge     *Merge          `json:"Merge,omitempty"`
+	Comments  []InlineComment `json:"Comments,omitempty"`
 }
@@ -0,0 +1,46 @@
+package opa
+
+import (
+	"context"
+
+	"github.com/marqeta/pr-bot/opa/client"
+	"github.com/marqeta/pr-bot/opa/input"
+	"github.com/marqeta/pr-bot/opa/rules"
+	"github.com/marqeta/pr-bot/opa/types"
+)
+
+const DecisionRuleName = "decision"
+
+// V2 evaluates a single decision object per module,
+// which holds the review and the actions to take on the PR.
+type V2 struct {
+	decision rules.Rules[types.Result]
+}
+
+func NewV2PolicyFromRules(d rules.Rules[types.Result]) Policy {
+	return &V2{
+		decision: d,
+	}
+}
+
+func NewV2Policy(client client.Client) Policy {
+	return NewV2PolicyFromRules(
+		rules.NewDecision(DecisionRuleName, client),
+	)
+}
+
+// Evaluate implements Policy.
+func (v2 *V2) Evaluate(
+	ctx context.Context, module string, input *input.Model) (types.Result, error) {
+
+	result, err := v2.decision.Evaluate(ctx, module, input)
+	if err != nil {
+		return types.Result{}, err
+	}
+	if !result.Track {
+		return types.Result{
+			Track: false,
+		}, nil
+	}
+	return result, nil
+}
@@ -0,0 +1,68 @@
+package opa_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/marqeta/pr-bot/opa"
+	"github.com/marqeta/pr-bot/opa/rules"
+	"github.com/marqeta/pr-bot/opa/types"
+)
+
+func TestV2_Evaluate(t *testing.T) {
+	ctx := context.TODO()
+	//nolint:goerr113
+	randomErr := fmt.Errorf("random error")
+	withLabels := approve()
+	withLabels.Labels = &types.Labels{Add: []string{"auto-approved"}}
+	tests := []struct {
+		name            string
+		want            types.Result
+		setExpectations func(d *rules.MockRules[types.Result])
+		wantErr         bool
+	}{
+		{
+			name: "return result of the decision",
+			want: withLabels,
+			setExpectations: func(d *rules.MockRules[types.Result]) {
+				d.EXPECT().Evaluate(ctx, "ci/module/asd", randomModel()).Return(withLabels, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "should drop the rest of the decision when track is false",
+			want: types.Result{},
+			setExpectations: func(d *rules.MockRules[types.Result]) {
+				notTracked := withLabels
+				notTracked.Track = false
+				d.EXPECT().Evaluate(ctx, "ci/module/asd", randomModel()).Return(notTracked, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "should throw error when decision evaluation fails",
+			want: types.Result{},
+			setExpectations: func(d *rules.MockRules[types.Result]) {
+				d.EXPECT().Evaluate(ctx, "ci/module/asd", randomModel()).Return(types.Result{}, randomErr)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := rules.NewMockRules[types.Result](t)
+			v2 := opa.NewV2PolicyFromRules(decision)
+			tt.setExpectations(decision)
+			got, err := v2.Evaluate(ctx, "ci/module/asd", randomModel())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("V2.Evaluate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("V2.Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package pullrequest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/httplog"
+	"github.com/google/go-github/v50/github"
+	gh "github.com/marqeta/pr-bot/github"
+	"github.com/marqeta/pr-bot/id"
+	"github.com/marqeta/pr-bot/metrics"
+	"github.com/marqeta/pr-bot/opa/evaluation"
+	"github.com/marqeta/pr-bot/opa/input"
+	"github.com/marqeta/pr-bot/opa/types"
+)
+
+const (
+	ConclusionSuccess        = "success"
+	ConclusionNeutral        = "neutral"
+	ConclusionFailure        = "failure"
+	ConclusionActionRequired = "action_required"
+
+	// ConclusionsNote explains the conclusions of the check run at the end of its summary.
+	ConclusionsNote = "\n_Approvals succeed, comments require action, requested changes and failed evaluations fail, " +
+		"and every other outcome is neutral._\n"
+
+	// GitHub rejects check run summaries longer than 65535 characters.
+	maxSummaryLength = 65535
+)
+
+type checkPublisher struct {
+	api     gh.API
+	manager evaluation.Manager
+	metrics metrics.Emitter
+	name    string
+}
+
+// NewCheckPublisher returns a Publisher creating a check run called name on the head commit of the PR,
+// or updating it if the head commit already has one.
+// the summary of the check run is built from the report of the evaluation stored in manager.
+func NewCheckPublisher(api gh.API, manager evaluation.Manager, m metrics.Emitter, name string) Publisher {
+	return &checkPublisher{
+		api:     api,
+		manager: manager,
+		metrics: m,
+		name:    name,
+	}
+}
+
+// Publish implements Publisher.
+func (p *checkPublisher) Publish(ctx context.Context, id id.PR, ghe input.GHE, result types.Result, evalErr error) error {
+	oplog := httplog.LogEntry(ctx)
+	sha := ghe.PullRequest.GetHead().GetSHA()
+	if sha == "" {
+		oplog.Info().Msg("head sha of PR is unknown, skipping check run")
+		return nil
+	}
+	conclusion, title := Conclusion(result, evalErr)
+	output := &github.CheckRunOutput{
+		Title:   github.String(title),
+		Summary: github.String(truncate(summarize(ctx, p.manager, ghe, result, evalErr) + ConclusionsNote)),
+	}
+	detailsURL := p.api.UI(id)
+	if deliveryID := evaluation.GetDeliveryID(ctx); deliveryID != "" {
+		detailsURL = fmt.Sprintf("%s/events/%s", detailsURL, deliveryID)
+	}
+
+	runs, err := p.api.ListCheckRunsForRef(ctx, id, sha)
+	switch {
+	case errors.Is(err, gh.ErrTruncated):
+		// a check run left out of the list is created again
+		oplog.Warn().Err(err).Msgf("looking for the check run in the first %d check runs of %v", len(runs), sha)
+	case err != nil:
+		oplog.Err(err).Msgf("error listing check runs of %v", sha)
+		return err
+	}
+	i := slices.IndexFunc(runs, func(r *github.CheckRun) bool {
+		return r.GetName() == p.name
+	})
+	if i >= 0 {
+		err = p.api.UpdateCheckRun(ctx, id, runs[i].GetID(), github.UpdateCheckRunOptions{
+			Name:       p.name,
+			DetailsURL: github.String(detailsURL),
+			Status:     github.String("completed"),
+			Conclusion: github.String(conclusion),
+			Output:     output,
+		})
+	} else {
+		err = p.api.CreateCheckRun(ctx, id, github.CreateCheckRunOptions{
+			Name:       p.name,
+			HeadSHA:    sha,
+			DetailsURL: github.String(detailsURL),
+			Status:     github.String("completed"),
+			Conclusion: github.String(conclusion),
+			Output:     output,
+		})
+	}
+	if err != nil {
+		oplog.Err(err).Msgf("error publishing check run %v on %v", p.name, sha)
+		return err
+	}
+	oplog.Info().Msgf("published check run %v on %v with conclusion %v", p.name, sha, conclusion)
+	tags := append(id.ToTags(), fmt.Sprintf("conclusion:%s", conclusion))
+	p.metrics.EmitDist(ctx, "checkRunsPublished", 1.0, tags)
+	return nil
+}
+
+// Conclusion returns the conclusion and title of the check run of an evaluation.
+// Only approvals succeed and only requested changes and failed evaluations fail,
+// so that the check run can be required by branch protection. Comments require action,
+// so that they are not mistaken for a passing check.
+func Conclusion(result types.Result, evalErr error) (string, string) {
+	if evalErr != nil {
+		return ConclusionFailure, "Policy evaluation failed"
+	}
+	if !result.Track {
+		return ConclusionNeutral, "PR is not tracked by any policy"
+	}
+	switch result.Review.Type {
+	case types.Approve:
+		return ConclusionSuccess, "Approved"
+	case types.RequestChanges:
+		return ConclusionFailure, "Changes requested"
+	case types.Comment:
+		return ConclusionActionRequired, "Commented"
+	case types.Dismiss:
+		return ConclusionNeutral, "Requested changes dismissed"
+	default:
+		return ConclusionNeutral, "Skipped"
+	}
+}
+
+func truncate(s string) string {
+	if len(s) <= maxSummaryLength {
+		return s
+	}
+	suffix := "\n\n_summary truncated, see details for the full report_"
+	return strings.ToValidUTF8(s[:maxSummaryLength-len(suffix)], "") + suffix
+}
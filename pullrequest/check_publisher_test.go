@@ -0,0 +1,214 @@
+package pullrequest_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v50/github"
+	gh "github.com/marqeta/pr-bot/github"
+	"github.com/marqeta/pr-bot/metrics"
+	"github.com/marqeta/pr-bot/opa/evaluation"
+	"github.com/marqeta/pr-bot/opa/types"
+	"github.com/marqeta/pr-bot/pullrequest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_checkPublisher_Publish(t *testing.T) {
+	ctx := evaluation.SetDeliveryID(context.TODO(), "delivery1")
+	report := &evaluation.Report{
+		Breakdown: map[string]evaluation.Result{
+			"m2": {Result: types.Result{Track: true, Review: types.Review{Type: types.RequestChanges, Body: "no"}}},
+			"m1": {Result: types.Result{Track: true, Review: types.Review{Type: types.Approve}}},
+			"m3": {Result: types.Result{Track: false}, Err: errRandom, Shadow: true},
+		},
+	}
+	result := types.Result{Track: true, Review: types.Review{Type: types.RequestChanges, Body: "no"}}
+	wantSummary := "**Outcome:** REQUEST_CHANGES\n\nno\n\n" +
+		"| Module | Review | Notes |\n| --- | --- | --- |\n" +
+		"| m1 | APPROVE |  |\n" +
+		"| m2 | REQUEST_CHANGES |  |\n" +
+		"| m3 | SKIP | shadow, not tracked, error: random error |\n" +
+		pullrequest.ConclusionsNote
+	wantURL := "https://pr-bot/ui/eval/owner1/repo1/pull/1/events/delivery1"
+
+	tests := []struct {
+		name            string
+		sha             string
+		setExpectations func(api *gh.MockAPI, m *evaluation.MockManager)
+		wantErr         bool
+	}{
+		{
+			name: "Should create check run on head commit",
+			sha:  "sha1",
+			setExpectations: func(api *gh.MockAPI, m *evaluation.MockManager) {
+				m.EXPECT().GetReport(ctx, "owner1/repo1/1", "delivery1").Return(report, nil)
+				api.EXPECT().UI(sampleID()).Return("https://pr-bot/ui/eval/owner1/repo1/pull/1")
+				api.EXPECT().ListCheckRunsForRef(ctx, sampleID(), "sha1").
+					Return([]*github.CheckRun{{ID: github.Int64(1), Name: github.String("lint")}}, nil)
+				api.EXPECT().CreateCheckRun(ctx, sampleID(), github.CreateCheckRunOptions{
+					Name:       "pr-bot",
+					HeadSHA:    "sha1",
+					DetailsURL: github.String(wantURL),
+					Status:     github.String("completed"),
+					Conclusion: github.String("failure"),
+					Output: &github.CheckRunOutput{
+						Title:   github.String("Changes requested"),
+						Summary: github.String(wantSummary),
+					},
+				}).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should update check run of head commit",
+			sha:  "sha1",
+			setExpectations: func(api *gh.MockAPI, m *evaluation.MockManager) {
+				m.EXPECT().GetReport(ctx, "owner1/repo1/1", "delivery1").Return(report, nil)
+				api.EXPECT().UI(sampleID()).Return("https://pr-bot/ui/eval/owner1/repo1/pull/1")
+				api.EXPECT().ListCheckRunsForRef(ctx, sampleID(), "sha1").
+					Return([]*github.CheckRun{{ID: github.Int64(2), Name: github.String("pr-bot")}}, nil)
+				api.EXPECT().UpdateCheckRun(ctx, sampleID(), int64(2), github.UpdateCheckRunOptions{
+					Name:       "pr-bot",
+					DetailsURL: github.String(wantURL),
+					Status:     github.String("completed"),
+					Conclusion: github.String("failure"),
+					Output: &github.CheckRunOutput{
+						Title:   github.String("Changes requested"),
+						Summary: github.String(wantSummary),
+					},
+				}).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should publish check run without breakdown when report is not found",
+			sha:  "sha1",
+			setExpectations: func(api *gh.MockAPI, m *evaluation.MockManager) {
+				m.EXPECT().GetReport(ctx, "owner1/repo1/1", "delivery1").Return(nil, evaluation.ErrReportNotFound)
+				api.EXPECT().UI(sampleID()).Return("https://pr-bot/ui/eval/owner1/repo1/pull/1")
+				api.EXPECT().ListCheckRunsForRef(ctx, sampleID(), "sha1").Return(nil, nil)
+				api.EXPECT().CreateCheckRun(ctx, sampleID(), mock.MatchedBy(func(opts github.CreateCheckRunOptions) bool {
+					return opts.GetOutput().GetSummary() == "**Outcome:** REQUEST_CHANGES\n\nno\n"+pullrequest.ConclusionsNote
+				})).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should return error when check runs cannot be listed",
+			sha:  "sha1",
+			setExpectations: func(api *gh.MockAPI, m *evaluation.MockManager) {
+				m.EXPECT().GetReport(ctx, "owner1/repo1/1", "delivery1").Return(report, nil)
+				api.EXPECT().UI(sampleID()).Return("https://pr-bot/ui/eval/owner1/repo1/pull/1")
+				api.EXPECT().ListCheckRunsForRef(ctx, sampleID(), "sha1").Return(nil, errRandom)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should return error when check run cannot be created",
+			sha:  "sha1",
+			setExpectations: func(api *gh.MockAPI, m *evaluation.MockManager) {
+				m.EXPECT().GetReport(ctx, "owner1/repo1/1", "delivery1").Return(report, nil)
+				api.EXPECT().UI(sampleID()).Return("https://pr-bot/ui/eval/owner1/repo1/pull/1")
+				api.EXPECT().ListCheckRunsForRef(ctx, sampleID(), "sha1").Return(nil, nil)
+				api.EXPECT().CreateCheckRun(ctx, sampleID(), mock.Anything).Return(errRandom)
+			},
+			wantErr: true,
+		},
+		{
+			name:            "Should not publish check run when head sha is unknown",
+			sha:             "",
+			setExpectations: func(_ *gh.MockAPI, _ *evaluation.MockManager) {},
+			wantErr:         false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := gh.NewMockAPI(t)
+			m := evaluation.NewMockManager(t)
+			tt.setExpectations(api, m)
+			event := prEvent(github.String("synchronize"), sampleID())
+			if tt.sha != "" {
+				event.PullRequest.Head = &github.PullRequestBranch{SHA: github.String(tt.sha)}
+			}
+			p := pullrequest.NewCheckPublisher(api, m, metrics.NewNoopEmitter(), "pr-bot")
+			if err := p.Publish(ctx, sampleID(), ToGHE(event), result, nil); (err != nil) != tt.wantErr {
+				t.Errorf("checkPublisher.Publish() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkPublisher_Publish_TruncatesSummary(t *testing.T) {
+	ctx := context.TODO()
+	api := gh.NewMockAPI(t)
+	m := evaluation.NewMockManager(t)
+	event := prEvent(github.String("synchronize"), sampleID())
+	event.PullRequest.Head = &github.PullRequestBranch{SHA: github.String("sha1")}
+	result := types.Result{Track: true, Review: types.Review{Type: types.Comment, Body: strings.Repeat("é", 40000)}}
+
+	m.EXPECT().GetReport(ctx, "owner1/repo1/1", "").Return(nil, evaluation.ErrReportNotFound)
+	api.EXPECT().UI(sampleID()).Return("https://pr-bot/ui/eval/owner1/repo1/pull/1")
+	api.EXPECT().ListCheckRunsForRef(ctx, sampleID(), "sha1").Return(nil, nil)
+	api.EXPECT().CreateCheckRun(ctx, sampleID(), mock.MatchedBy(func(opts github.CreateCheckRunOptions) bool {
+		summary := opts.GetOutput().GetSummary()
+		return len(summary) <= 65535 && strings.HasSuffix(summary, "see details for the full report_") &&
+			opts.GetDetailsURL() == "https://pr-bot/ui/eval/owner1/repo1/pull/1"
+	})).Return(nil)
+
+	p := pullrequest.NewCheckPublisher(api, m, metrics.NewNoopEmitter(), "pr-bot")
+	assert.Nil(t, p.Publish(ctx, sampleID(), ToGHE(event), result, nil))
+}
+
+func TestConclusion(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  types.Result
+		evalErr error
+		want    string
+	}{
+		{
+			name:   "Should succeed when approved",
+			result: types.Result{Track: true, Review: types.Review{Type: types.Approve}},
+			want:   pullrequest.ConclusionSuccess,
+		},
+		{
+			name:   "Should fail when changes are requested",
+			result: types.Result{Track: true, Review: types.Review{Type: types.RequestChanges}},
+			want:   pullrequest.ConclusionFailure,
+		},
+		{
+			name:    "Should fail when evaluation fails",
+			evalErr: errors.New("random error"), //nolint:goerr113
+			want:    pullrequest.ConclusionFailure,
+		},
+		{
+			name:   "Should require action when commented",
+			result: types.Result{Track: true, Review: types.Review{Type: types.Comment}},
+			want:   pullrequest.ConclusionActionRequired,
+		},
+		{
+			name:   "Should be neutral when dismissed",
+			result: types.Result{Track: true, Review: types.Review{Type: types.Dismiss}},
+			want:   pullrequest.ConclusionNeutral,
+		},
+		{
+			name:   "Should be neutral when skipped",
+			result: types.Result{Track: true, Review: types.Review{Type: types.Skip}},
+			want:   pullrequest.ConclusionNeutral,
+		},
+		{
+			name:   "Should be neutral when not tracked",
+			result: types.Result{Track: false, Review: types.Review{Type: types.Approve}},
+			want:   pullrequest.ConclusionNeutral,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := pullrequest.Conclusion(tt.result, tt.evalErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package pullrequest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/httplog"
+	gh "github.com/marqeta/pr-bot/github"
+	"github.com/marqeta/pr-bot/id"
+	"github.com/marqeta/pr-bot/metrics"
+	"github.com/marqeta/pr-bot/opa"
+	"github.com/marqeta/pr-bot/opa/evaluation"
+	"github.com/marqeta/pr-bot/opa/input"
+)
+
+const (
+	// CommandPrefix is the prefix of a comment that is a command to the bot.
+	CommandPrefix = "/pr-bot"
+
+	CommandReevaluate = "reevaluate"
+	CommandExplain    = "explain"
+	CommandSkip       = "skip"
+
+	CommandUsage = "Usage: `/pr-bot reevaluate`, `/pr-bot explain` or `/pr-bot skip <module>`"
+
+	CommandReplyTemplate   = "@%v `%v` completed. Evaluation report: %v"
+	CommandExplainTemplate = "@%v `%v` completed without reviewing the PR.\n\n%v\nEvaluation report: %v"
+	CommandDeniedTemplate  = "@%v you do not have permission to run `%v`"
+	CommandInvalidTemplate = "@%v `%v` is not a valid command. %v"
+	CommandModuleTemplate  = "@%v `%v` is not a module of the policy bundle. Modules: %v"
+)
+
+// Command is a command posted as a PR comment.
+// e.g. /pr-bot skip module1
+type Command struct {
+	Name string
+	Args []string
+}
+
+func (c Command) String() string {
+	return strings.TrimSpace(fmt.Sprintf("%s %s %s", CommandPrefix, c.Name, strings.Join(c.Args, " ")))
+}
+
+// ParseCommand returns the first command in the comment body.
+// returns false if the comment does not contain a command.
+func ParseCommand(body string) (Command, bool) {
+	for _, line := range strings.Split(body, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || fields[0] != CommandPrefix {
+			continue
+		}
+		cmd := Command{Args: []string{}}
+		if len(fields) > 1 {
+			cmd.Name = strings.ToLower(fields[1])
+			cmd.Args = fields[2:]
+		}
+		return cmd, true
+	}
+	return Command{}, false
+}
+
+//go:generate mockery --name CommandHandler
+type CommandHandler interface {
+	Handle(ctx context.Context, id id.PR, cmd Command, user string, ghe input.GHE) error
+}
+
+type commandHandler struct {
+	api       gh.API
+	handler   EventHandler
+	evaluator opa.Evaluator
+	manager   evaluation.Manager
+	bundles   *opa.BundleHolder
+	metrics   metrics.Emitter
+}
+
+// NewCommandHandler returns a CommandHandler, modules to skip must be in the active bundle of bundles.
+// explain evaluates the PR with evaluator and replies with the results of the modules read from manager.
+func NewCommandHandler(api gh.API, eh EventHandler, evaluator opa.Evaluator, manager evaluation.Manager,
+	bundles *opa.BundleHolder, m metrics.Emitter) CommandHandler {
+	return &commandHandler{
+		api:       api,
+		handler:   eh,
+		evaluator: evaluator,
+		manager:   manager,
+		bundles:   bundles,
+		metrics:   m,
+	}
+}
+
+// Handle implements CommandHandler.
+// PR author and collaborators with write access can reevaluate and explain,
+// only collaborators with write access can skip modules.
+func (ch *commandHandler) Handle(ctx context.Context, id id.PR, cmd Command, user string, ghe input.GHE) error {
+	oplog := httplog.LogEntry(ctx)
+	tags := append(id.ToTags(), fmt.Sprintf("command:%s", cmd.Name))
+
+	if !isValid(cmd) {
+		ch.metrics.EmitDist(ctx, "chatops.invalid", 1, tags)
+		return ch.api.IssueComment(ctx, id, fmt.Sprintf(CommandInvalidTemplate, user, cmd, CommandUsage))
+	}
+
+	permission, err := ch.api.GetPermissionLevel(ctx, id, user)
+	if err != nil {
+		oplog.Err(err).Msgf("error getting permission level of %v", user)
+		return err
+	}
+
+	if !isAllowed(cmd, id, user, permission) {
+		oplog.Info().Msgf("%v with permission %v is not allowed to run %v", user, permission, cmd)
+		ch.metrics.EmitDist(ctx, "chatops.denied", 1, tags)
+		return ch.api.IssueComment(ctx, id, fmt.Sprintf(CommandDeniedTemplate, user, cmd))
+	}
+
+	if cmd.Name == CommandExplain {
+		return ch.explain(ctx, id, cmd, user, ghe, tags)
+	}
+
+	evalCtx := ctx
+	if cmd.Name == CommandSkip {
+		modules := ch.bundles.Bundle().Modules
+		if module := cmd.Args[0]; !slices.Contains(modules, module) {
+			oplog.Info().Msgf("%v is not a module of the policy bundle", module)
+			ch.metrics.EmitDist(ctx, "chatops.invalid", 1, tags)
+			return ch.api.IssueComment(ctx, id, fmt.Sprintf(CommandModuleTemplate, user, module, listModules(modules)))
+		}
+		evalCtx = evaluation.SetSkippedModules(ctx, cmd.Args)
+	}
+
+	err = ch.handler.EvalAndReview(evalCtx, id, ghe)
+	if err != nil {
+		oplog.Err(err).Msgf("error running %v", cmd)
+		return err
+	}
+
+	ch.metrics.EmitDist(ctx, "chatops.command", 1, tags)
+	return ch.api.IssueComment(ctx, id, fmt.Sprintf(CommandReplyTemplate, user, cmd, ch.api.UI(id)))
+}
+
+// explain evaluates the PR without reviewing it,
+// and replies with the outcome and the result of every module.
+func (ch *commandHandler) explain(ctx context.Context, id id.PR, cmd Command, user string, ghe input.GHE,
+	tags []string) error {
+	oplog := httplog.LogEntry(ctx)
+	result, err := ch.evaluator.Evaluate(ctx, ghe)
+	if err != nil {
+		oplog.Err(err).Msgf("error running %v", cmd)
+		return err
+	}
+
+	ch.metrics.EmitDist(ctx, "chatops.command", 1, tags)
+	explanation := truncate(summarize(ctx, ch.manager, ghe, result, nil))
+	return ch.api.IssueComment(ctx, id, fmt.Sprintf(CommandExplainTemplate, user, cmd, explanation, ch.api.UI(id)))
+}
+
+func isValid(cmd Command) bool {
+	switch cmd.Name {
+	case CommandReevaluate, CommandExplain:
+		return len(cmd.Args) == 0
+	case CommandSkip:
+		return len(cmd.Args) == 1
+	default:
+		return false
+	}
+}
+
+func listModules(modules []string) string {
+	quoted := make([]string, 0, len(modules))
+	for _, m := range modules {
+		quoted = append(quoted, fmt.Sprintf("`%s`", m))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func isAllowed(cmd Command, id id.PR, user, permission string) bool {
+	canWrite := permission == "admin" || permission == "maintain" || permission == "write"
+	if cmd.Name == CommandSkip {
+		return canWrite
+	}
+	return canWrite || user == id.Author
+}
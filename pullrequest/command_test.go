@@ -0,0 +1,253 @@
+package pullrequest_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	gh "github.com/marqeta/pr-bot/github"
+	"github.com/marqeta/pr-bot/id"
+	"github.com/marqeta/pr-bot/metrics"
+	"github.com/marqeta/pr-bot/opa"
+	"github.com/marqeta/pr-bot/opa/evaluation"
+	"github.com/marqeta/pr-bot/opa/input"
+	"github.com/marqeta/pr-bot/opa/types"
+	"github.com/marqeta/pr-bot/pullrequest"
+	mock "github.com/stretchr/testify/mock"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		want   pullrequest.Command
+		wantOk bool
+	}{
+		{
+			name:   "should parse command without args",
+			body:   "/pr-bot reevaluate",
+			want:   pullrequest.Command{Name: "reevaluate", Args: []string{}},
+			wantOk: true,
+		},
+		{
+			name:   "should parse command with args",
+			body:   "  /pr-bot   SKIP module1  ",
+			want:   pullrequest.Command{Name: "skip", Args: []string{"module1"}},
+			wantOk: true,
+		},
+		{
+			name:   "should parse first command in comment",
+			body:   "please take another look\r\n/pr-bot explain\n/pr-bot reevaluate",
+			want:   pullrequest.Command{Name: "explain", Args: []string{}},
+			wantOk: true,
+		},
+		{
+			name:   "should parse prefix without command",
+			body:   "/pr-bot",
+			want:   pullrequest.Command{Args: []string{}},
+			wantOk: true,
+		},
+		{
+			name:   "should ignore comment without command",
+			body:   "LGTM, see /pr-bot docs",
+			want:   pullrequest.Command{},
+			wantOk: false,
+		},
+		{
+			name:   "should ignore other bots",
+			body:   "/pr-bot2 reevaluate",
+			want:   pullrequest.Command{},
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := pullrequest.ParseCommand(tt.body)
+			if ok != tt.wantOk {
+				t.Errorf("ParseCommand() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCommand() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_commandHandler_Handle(t *testing.T) {
+	ctx := context.Background()
+	ghe := input.GHE{Event: pullrequest.EventNameComment, Action: "created"}
+	ui := "https://pr-bot/ui/eval/owner1/repo1/pull/1"
+	tests := []struct {
+		name            string
+		cmd             pullrequest.Command
+		user            string
+		setExpectations func(id id.PR, api *gh.MockAPI, h *pullrequest.MockEventHandler, e *opa.MockEvaluator,
+			m *evaluation.MockManager)
+		wantErr error
+	}{
+		{
+			name: "should reevaluate PR for author",
+			cmd:  pullrequest.Command{Name: "reevaluate", Args: []string{}},
+			user: "user1",
+			setExpectations: func(id id.PR, api *gh.MockAPI, h *pullrequest.MockEventHandler, _ *opa.MockEvaluator,
+				_ *evaluation.MockManager) {
+				api.EXPECT().GetPermissionLevel(ctx, id, "user1").Return("read", nil).Once()
+				h.EXPECT().EvalAndReview(ctx, id, ghe).Return(nil).Once()
+				api.EXPECT().UI(id).Return(ui).Once()
+				api.EXPECT().IssueComment(ctx, id,
+					"@user1 `/pr-bot reevaluate` completed. Evaluation report: "+ui).Return(nil).Once()
+			},
+			wantErr: nil,
+		},
+		{
+			name: "should explain PR for collaborator without reviewing it",
+			cmd:  pullrequest.Command{Name: "explain", Args: []string{}},
+			user: "user2",
+			// no review is posted, since the event handler mock expects no call
+			setExpectations: func(id id.PR, api *gh.MockAPI, _ *pullrequest.MockEventHandler, e *opa.MockEvaluator,
+				m *evaluation.MockManager) {
+				api.EXPECT().GetPermissionLevel(ctx, id, "user2").Return("write", nil).Once()
+				e.EXPECT().Evaluate(ctx, ghe).Return(types.Result{
+					Track:  true,
+					Review: types.Review{Type: types.RequestChanges, Body: "tests are missing"},
+				}, nil).Once()
+				m.EXPECT().GetReport(ctx, "/0", "").Return(&evaluation.Report{
+					Breakdown: map[string]evaluation.Result{
+						"m1": {Result: types.Result{Track: true, Review: types.Review{Type: types.Approve}}},
+						"m2": {Result: types.Result{Track: true, Review: types.Review{Type: types.RequestChanges}}},
+					},
+				}, nil).Once()
+				api.EXPECT().UI(id).Return(ui).Once()
+				api.EXPECT().IssueComment(ctx, id, "@user2 `/pr-bot explain` completed without reviewing the PR.\n\n"+
+					"**Outcome:** REQUEST_CHANGES\n\ntests are missing\n\n"+
+					"| Module | Review | Notes |\n| --- | --- | --- |\n"+
+					"| m1 | APPROVE |  |\n| m2 | REQUEST_CHANGES |  |\n"+
+					"\nEvaluation report: "+ui).Return(nil).Once()
+			},
+			wantErr: nil,
+		},
+		{
+			name: "should return error from evaluation of explain without replying",
+			cmd:  pullrequest.Command{Name: "explain", Args: []string{}},
+			user: "user1",
+			setExpectations: func(id id.PR, api *gh.MockAPI, _ *pullrequest.MockEventHandler, e *opa.MockEvaluator,
+				_ *evaluation.MockManager) {
+				api.EXPECT().GetPermissionLevel(ctx, id, "user1").Return("read", nil).Once()
+				e.EXPECT().Evaluate(ctx, ghe).Return(types.Result{}, errRandom).Once()
+			},
+			wantErr: errRandom,
+		},
+		{
+			name: "should skip module for collaborator",
+			cmd:  pullrequest.Command{Name: "skip", Args: []string{"m1"}},
+			user: "user2",
+			setExpectations: func(id id.PR, api *gh.MockAPI, h *pullrequest.MockEventHandler, _ *opa.MockEvaluator,
+				_ *evaluation.MockManager) {
+				api.EXPECT().GetPermissionLevel(ctx, id, "user2").Return("admin", nil).Once()
+				h.EXPECT().EvalAndReview(mock.MatchedBy(func(ctx context.Context) bool {
+					return reflect.DeepEqual(evaluation.GetSkippedModules(ctx), []string{"m1"})
+				}), id, ghe).Return(nil).Once()
+				api.EXPECT().UI(id).Return(ui).Once()
+				api.EXPECT().IssueComment(ctx, id,
+					"@user2 `/pr-bot skip m1` completed. Evaluation report: "+ui).Return(nil).Once()
+			},
+			wantErr: nil,
+		},
+		{
+			name: "should reply with modules when skipped module is not in the bundle",
+			cmd:  pullrequest.Command{Name: "skip", Args: []string{"m3"}},
+			user: "user2",
+			setExpectations: func(id id.PR, api *gh.MockAPI, _ *pullrequest.MockEventHandler, _ *opa.MockEvaluator,
+				_ *evaluation.MockManager) {
+				api.EXPECT().GetPermissionLevel(ctx, id, "user2").Return("write", nil).Once()
+				api.EXPECT().IssueComment(ctx, id,
+					"@user2 `m3` is not a module of the policy bundle. Modules: `m1`, `m2`").Return(nil).Once()
+			},
+			wantErr: nil,
+		},
+		{
+			name: "should not let author without write access skip modules",
+			cmd:  pullrequest.Command{Name: "skip", Args: []string{"m1"}},
+			user: "user1",
+			setExpectations: func(id id.PR, api *gh.MockAPI, _ *pullrequest.MockEventHandler, _ *opa.MockEvaluator,
+				_ *evaluation.MockManager) {
+				api.EXPECT().GetPermissionLevel(ctx, id, "user1").Return("read", nil).Once()
+				api.EXPECT().IssueComment(ctx, id,
+					"@user1 you do not have permission to run `/pr-bot skip m1`").Return(nil).Once()
+			},
+			wantErr: nil,
+		},
+		{
+			name: "should not let other users reevaluate",
+			cmd:  pullrequest.Command{Name: "reevaluate", Args: []string{}},
+			user: "user3",
+			setExpectations: func(id id.PR, api *gh.MockAPI, _ *pullrequest.MockEventHandler, _ *opa.MockEvaluator,
+				_ *evaluation.MockManager) {
+				api.EXPECT().GetPermissionLevel(ctx, id, "user3").Return("none", nil).Once()
+				api.EXPECT().IssueComment(ctx, id,
+					"@user3 you do not have permission to run `/pr-bot reevaluate`").Return(nil).Once()
+			},
+			wantErr: nil,
+		},
+		{
+			name: "should reply with usage for unknown commands",
+			cmd:  pullrequest.Command{Name: "merge", Args: []string{}},
+			user: "user1",
+			setExpectations: func(id id.PR, api *gh.MockAPI, _ *pullrequest.MockEventHandler, _ *opa.MockEvaluator,
+				_ *evaluation.MockManager) {
+				api.EXPECT().IssueComment(ctx, id,
+					"@user1 `/pr-bot merge` is not a valid command. "+pullrequest.CommandUsage).Return(nil).Once()
+			},
+			wantErr: nil,
+		},
+		{
+			name: "should reply with usage when skip has no module",
+			cmd:  pullrequest.Command{Name: "skip", Args: []string{}},
+			user: "user1",
+			setExpectations: func(id id.PR, api *gh.MockAPI, _ *pullrequest.MockEventHandler, _ *opa.MockEvaluator,
+				_ *evaluation.MockManager) {
+				api.EXPECT().IssueComment(ctx, id,
+					"@user1 `/pr-bot skip` is not a valid command. "+pullrequest.CommandUsage).Return(nil).Once()
+			},
+			wantErr: nil,
+		},
+		{
+			name: "should return error from permission check",
+			cmd:  pullrequest.Command{Name: "reevaluate", Args: []string{}},
+			user: "user1",
+			setExpectations: func(id id.PR, api *gh.MockAPI, _ *pullrequest.MockEventHandler, _ *opa.MockEvaluator,
+				_ *evaluation.MockManager) {
+				api.EXPECT().GetPermissionLevel(ctx, id, "user1").Return("", errRandom).Once()
+			},
+			wantErr: errRandom,
+		},
+		{
+			name: "should return error from evaluation without replying",
+			cmd:  pullrequest.Command{Name: "reevaluate", Args: []string{}},
+			user: "user1",
+			setExpectations: func(id id.PR, api *gh.MockAPI, h *pullrequest.MockEventHandler, _ *opa.MockEvaluator,
+				_ *evaluation.MockManager) {
+				api.EXPECT().GetPermissionLevel(ctx, id, "user1").Return("read", nil).Once()
+				h.EXPECT().EvalAndReview(ctx, id, ghe).Return(errRandom).Once()
+			},
+			wantErr: errRandom,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := gh.NewMockAPI(t)
+			handler := pullrequest.NewMockEventHandler(t)
+			evaluator := opa.NewMockEvaluator(t)
+			manager := evaluation.NewMockManager(t)
+			tt.setExpectations(sampleID(), api, handler, evaluator, manager)
+
+			bundles := opa.NewBundleHolder(&opa.Bundle{Modules: []string{"m1", "m2"}})
+			ch := pullrequest.NewCommandHandler(api, handler, evaluator, manager, bundles, metrics.NewNoopEmitter())
+			err := ch.Handle(ctx, sampleID(), tt.cmd, tt.user, ghe)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("commandHandler.Handle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package pullrequest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/httplog"
+	"github.com/google/go-github/v50/github"
+	pe "github.com/marqeta/pr-bot/errors"
+	gh "github.com/marqeta/pr-bot/github"
+	"github.com/marqeta/pr-bot/id"
+	"github.com/marqeta/pr-bot/metrics"
+	"github.com/marqeta/pr-bot/opa/evaluation"
+	"github.com/marqeta/pr-bot/opa/input"
+	"github.com/marqeta/pr-bot/opa/types"
+)
+
+const (
+	// SummaryMarker is hidden in the summary comment, so that it can be found and edited on every evaluation.
+	SummaryMarker = "<!-- pr-bot:summary -->"
+
+	SummaryCommentTemplate = `%v
+### pr-bot summary
+%v
+[History of evaluations](%v)
+`
+)
+
+type summaryCommentPublisher struct {
+	api            gh.API
+	manager        evaluation.Manager
+	metrics        metrics.Emitter
+	serviceAccount string
+}
+
+// NewSummaryCommentPublisher returns a Publisher keeping a single summary comment on the PR,
+// the comment is posted on the first evaluation tracking the PR and edited in place on every later evaluation.
+// only comments posted by serviceAccount are edited.
+func NewSummaryCommentPublisher(api gh.API, manager evaluation.Manager, m metrics.Emitter,
+	serviceAccount string) Publisher {
+	return &summaryCommentPublisher{
+		api:            api,
+		manager:        manager,
+		metrics:        m,
+		serviceAccount: serviceAccount,
+	}
+}
+
+// Publish implements Publisher.
+func (p *summaryCommentPublisher) Publish(ctx context.Context, id id.PR, ghe input.GHE,
+	result types.Result, evalErr error) error {
+	oplog := httplog.LogEntry(ctx)
+	comments, err := p.api.ListIssueComments(ctx, id)
+	switch {
+	case errors.Is(err, gh.ErrTruncated):
+		// a summary comment left out of the list is posted again
+		oplog.Warn().Err(err).Msgf("looking for the summary comment in the first %d comments of PR %v", len(comments), id.URL)
+	case err != nil:
+		oplog.Err(err).Msgf("error listing comments of PR %v", id.URL)
+		return err
+	}
+	i := slices.IndexFunc(comments, func(c *github.IssueComment) bool {
+		return strings.Contains(c.GetBody(), SummaryMarker) &&
+			(p.serviceAccount == "" || c.GetUser().GetLogin() == p.serviceAccount)
+	})
+	if i < 0 && evalErr == nil && !result.Track {
+		// PRs ignored by the policies are not commented on,
+		// the summary comment is still updated once posted
+		oplog.Info().Msgf("track=false, skipping summary comment on PR %v", id.URL)
+		return nil
+	}
+
+	body := fmt.Sprintf(SummaryCommentTemplate, SummaryMarker,
+		truncate(summarize(ctx, p.manager, ghe, result, evalErr)), p.api.UI(id))
+
+	op := "create"
+	switch {
+	case i < 0:
+		err = p.api.IssueComment(ctx, id, body)
+	case comments[i].GetBody() == body:
+		oplog.Info().Msgf("summary comment of PR %v is up to date", id.URL)
+		return nil
+	default:
+		op = "update"
+		err = p.api.EditIssueComment(ctx, id, comments[i].GetID(), body)
+	}
+	if err != nil {
+		oplog.Err(err).Msgf("error publishing summary comment on PR %v", id.URL)
+		return err
+	}
+	oplog.Info().Msgf("published summary comment on PR %v", id.URL)
+	p.metrics.EmitDist(ctx, "summaryComments", 1.0, append(id.ToTags(), fmt.Sprintf("op:%s", op)))
+	return nil
+}
+
+type errorCommentPublisher struct {
+	api gh.API
+}
+
+// NewErrorCommentPublisher returns a Publisher posting a new comment for every failed evaluation.
+func NewErrorCommentPublisher(api gh.API) Publisher {
+	return &errorCommentPublisher{
+		api: api,
+	}
+}
+
+// Publish implements Publisher.
+func (p *errorCommentPublisher) Publish(ctx context.Context, id id.PR, _ input.GHE, _ types.Result, evalErr error) error {
+	if evalErr == nil {
+		return nil
+	}
+	var ae pe.APIError
+	if !errors.As(evalErr, &ae) {
+		ae = pe.ServiceFault(ctx, "Error evaluating policies", evalErr)
+	}
+	return p.api.IssueCommentForError(ctx, id, ae)
+}
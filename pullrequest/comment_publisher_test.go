@@ -0,0 +1,224 @@
+package pullrequest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-github/v50/github"
+	pe "github.com/marqeta/pr-bot/errors"
+	gh "github.com/marqeta/pr-bot/github"
+	"github.com/marqeta/pr-bot/metrics"
+	"github.com/marqeta/pr-bot/opa/evaluation"
+	"github.com/marqeta/pr-bot/opa/types"
+	"github.com/marqeta/pr-bot/pullrequest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_summaryCommentPublisher_Publish(t *testing.T) {
+	ctx := evaluation.SetDeliveryID(context.TODO(), "delivery1")
+	report := &evaluation.Report{
+		Breakdown: map[string]evaluation.Result{
+			"m1": {Result: types.Result{Track: true, Review: types.Review{Type: types.Approve}}},
+		},
+	}
+	result := types.Result{Track: true, Review: types.Review{Type: types.Approve, Body: "LGTM"}}
+	ui := "https://pr-bot/ui/eval/owner1/repo1/pull/1"
+	body := "<!-- pr-bot:summary -->\n### pr-bot summary\n" +
+		"**Outcome:** APPROVE\n\nLGTM\n\n" +
+		"| Module | Review | Notes |\n| --- | --- | --- |\n" +
+		"| m1 | APPROVE |  |\n\n" +
+		"[History of evaluations](" + ui + ")\n"
+	untrackedBody := "<!-- pr-bot:summary -->\n### pr-bot summary\n" +
+		"**Outcome:** SKIP\n\n\n" +
+		"| Module | Review | Notes |\n| --- | --- | --- |\n" +
+		"| m1 | APPROVE |  |\n\n" +
+		"[History of evaluations](" + ui + ")\n"
+	summary := func(login, body string) *github.IssueComment {
+		return &github.IssueComment{
+			ID:   github.Int64(2),
+			User: &github.User{Login: github.String(login)},
+			Body: github.String(body),
+		}
+	}
+
+	untracked := types.Result{Track: false, Review: types.Review{Type: types.Skip}}
+
+	tests := []struct {
+		name            string
+		result          *types.Result
+		noSummary       bool
+		setExpectations func(api *gh.MockAPI)
+		wantErr         bool
+	}{
+		{
+			name: "Should post summary comment on the first evaluation",
+			setExpectations: func(api *gh.MockAPI) {
+				api.EXPECT().ListIssueComments(ctx, sampleID()).
+					Return([]*github.IssueComment{summary("user1", "LGTM")}, nil)
+				api.EXPECT().IssueComment(ctx, sampleID(), body).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should edit summary comment in place",
+			setExpectations: func(api *gh.MockAPI) {
+				api.EXPECT().ListIssueComments(ctx, sampleID()).
+					Return([]*github.IssueComment{
+						summary("user1", "LGTM"),
+						summary("pr-bot", pullrequest.SummaryMarker+"\nold summary"),
+					}, nil)
+				api.EXPECT().EditIssueComment(ctx, sampleID(), int64(2), body).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should not edit summary comment which is up to date",
+			setExpectations: func(api *gh.MockAPI) {
+				api.EXPECT().ListIssueComments(ctx, sampleID()).
+					Return([]*github.IssueComment{summary("pr-bot", body)}, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should not edit summary comments copied by users",
+			setExpectations: func(api *gh.MockAPI) {
+				api.EXPECT().ListIssueComments(ctx, sampleID()).
+					Return([]*github.IssueComment{summary("user1", pullrequest.SummaryMarker)}, nil)
+				api.EXPECT().IssueComment(ctx, sampleID(), body).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:      "Should not post summary comment when PR is not tracked",
+			result:    &untracked,
+			noSummary: true,
+			setExpectations: func(api *gh.MockAPI) {
+				api.EXPECT().ListIssueComments(ctx, sampleID()).
+					Return([]*github.IssueComment{summary("user1", pullrequest.SummaryMarker)}, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:   "Should edit summary comment when PR is no longer tracked",
+			result: &untracked,
+			setExpectations: func(api *gh.MockAPI) {
+				api.EXPECT().ListIssueComments(ctx, sampleID()).
+					Return([]*github.IssueComment{summary("pr-bot", pullrequest.SummaryMarker)}, nil)
+				api.EXPECT().EditIssueComment(ctx, sampleID(), int64(2), untrackedBody).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:      "Should return error when comments cannot be listed",
+			noSummary: true,
+			setExpectations: func(api *gh.MockAPI) {
+				api.EXPECT().ListIssueComments(ctx, sampleID()).Return(nil, errRandom)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should return error when summary comment cannot be edited",
+			setExpectations: func(api *gh.MockAPI) {
+				api.EXPECT().ListIssueComments(ctx, sampleID()).
+					Return([]*github.IssueComment{summary("pr-bot", pullrequest.SummaryMarker)}, nil)
+				api.EXPECT().EditIssueComment(ctx, sampleID(), int64(2), body).Return(errRandom)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := gh.NewMockAPI(t)
+			m := evaluation.NewMockManager(t)
+			if !tt.noSummary {
+				m.EXPECT().GetReport(ctx, "owner1/repo1/1", "delivery1").Return(report, nil)
+				api.EXPECT().UI(sampleID()).Return(ui)
+			}
+			tt.setExpectations(api)
+			p := pullrequest.NewSummaryCommentPublisher(api, m, metrics.NewNoopEmitter(), "pr-bot")
+			event := prEvent(github.String("opened"), sampleID())
+			r := result
+			if tt.result != nil {
+				r = *tt.result
+			}
+			if err := p.Publish(ctx, sampleID(), ToGHE(event), r, nil); (err != nil) != tt.wantErr {
+				t.Errorf("summaryCommentPublisher.Publish() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_errorCommentPublisher_Publish(t *testing.T) {
+	ctx := context.TODO()
+	apiErr := pe.UserError(ctx, "invalid decision", errRandom)
+	tests := []struct {
+		name            string
+		evalErr         error
+		setExpectations func(api *gh.MockAPI)
+		wantErr         bool
+	}{
+		{
+			name:    "Should post comment for API errors",
+			evalErr: fmt.Errorf("wrapped: %w", apiErr),
+			setExpectations: func(api *gh.MockAPI) {
+				api.EXPECT().IssueCommentForError(ctx, sampleID(), apiErr).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:    "Should post comment for other errors",
+			evalErr: errRandom,
+			setExpectations: func(api *gh.MockAPI) {
+				api.EXPECT().IssueCommentForError(ctx, sampleID(), pe.ServiceFault(ctx, "Error evaluating policies", errRandom)).
+					Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:            "Should not post comment when evaluation succeeds",
+			setExpectations: func(_ *gh.MockAPI) {},
+			wantErr:         false,
+		},
+		{
+			name:    "Should return error when comment cannot be posted",
+			evalErr: errRandom,
+			setExpectations: func(api *gh.MockAPI) {
+				api.EXPECT().IssueCommentForError(ctx, sampleID(), mock.Anything).Return(errRandom)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := gh.NewMockAPI(t)
+			tt.setExpectations(api)
+			p := pullrequest.NewErrorCommentPublisher(api)
+			event := prEvent(github.String("opened"), sampleID())
+			if err := p.Publish(ctx, sampleID(), ToGHE(event), types.Result{}, tt.evalErr); (err != nil) != tt.wantErr {
+				t.Errorf("errorCommentPublisher.Publish() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPublishers_Publish(t *testing.T) {
+	ctx := context.TODO()
+	ghe := ToGHE(prEvent(github.String("opened"), sampleID()))
+	result := types.Result{Track: true}
+	p1 := pullrequest.NewMockPublisher(t)
+	p2 := pullrequest.NewMockPublisher(t)
+	p3 := pullrequest.NewMockPublisher(t)
+	//nolint:goerr113
+	errOther := errors.New("other error")
+	p1.EXPECT().Publish(ctx, sampleID(), ghe, result, nil).Return(errRandom)
+	p2.EXPECT().Publish(ctx, sampleID(), ghe, result, nil).Return(nil)
+	p3.EXPECT().Publish(ctx, sampleID(), ghe, result, nil).Return(errOther)
+
+	err := pullrequest.NewPublishers(p1, p2, p3).Publish(ctx, sampleID(), ghe, result, nil)
+	assert.ErrorIs(t, err, errRandom)
+	assert.ErrorIs(t, err, errOther)
+	assert.Nil(t, pullrequest.NewPublishers().Publish(ctx, sampleID(), ghe, result, nil))
+}
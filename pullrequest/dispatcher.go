@@ -6,12 +6,13 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/go-chi/httplog"
 	"github.com/google/go-github/v50/github"
 	pe "github.com/marqeta/pr-bot/errors"
+	gh "github.com/marqeta/pr-bot/github"
 	"github.com/marqeta/pr-bot/id"
 	"github.com/marqeta/pr-bot/metrics"
+	"github.com/marqeta/pr-bot/opa/input"
 )
 
 var ErrEventActionNotFound = errors.New("event action was empty or nil")
@@ -19,6 +20,15 @@ var ErrMismatchedEvent = errors.New("expected pull_request event")
 var ErrMismatchedReviewEvent = errors.New("expected pull_request_review event")
 var ErrLabelNotFound = errors.New("pull_request.Label was nil")
 var ErrPRNotFound = errors.New("event.pull_request was nil")
+var ErrMismatchedCheckSuiteEvent = errors.New("expected check_suite event")
+var ErrMismatchedCheckRunEvent = errors.New("expected check_run event")
+var ErrMismatchedStatusEvent = errors.New("expected status event")
+var ErrCheckSuiteNotFound = errors.New("event.check_suite was nil")
+var ErrCheckRunNotFound = errors.New("event.check_run was nil")
+var ErrStatusNotFound = errors.New("event.sha or event.state was nil")
+var ErrRepoNotFound = errors.New("event.repository was nil")
+var ErrMismatchedCommentEvent = errors.New("expected issue_comment event")
+var ErrCommentNotFound = errors.New("event.issue or event.comment was nil")
 
 // Actions are used to identify registered callbacks.
 const (
@@ -28,6 +38,15 @@ const (
 	// EventNameReview is the event name of github.EventName's
 	EventNameReview = "pull_request_review"
 
+	// EventNameCheckSuite, EventNameCheckRun and EventNameStatus are
+	// commit events, they are mapped to the open PRs of the commit.
+	EventNameCheckSuite = "check_suite"
+	EventNameCheckRun   = "check_run"
+	EventNameStatus     = "status"
+
+	// EventNameComment is the event name of comments on issues and PRs.
+	EventNameComment = "issue_comment"
+
 	OpenedAction         = "opened"
 	ReopenedAction       = "reopened"
 	EditedAction         = "edited"
@@ -39,25 +58,49 @@ const (
 	UnassignedAction     = "unassigned"
 	SynchronizeAction    = "synchronize"
 	SubmittedAction      = "submitted"
+	CompletedAction      = "completed"
+	CreatedAction        = "created"
+
+	// StatusPending is the state of a commit status which is not yet complete.
+	StatusPending = "pending"
 )
 
 //go:generate mockery --name Dispatcher
 type Dispatcher interface {
 	Dispatch(ctx context.Context, deliveryID string, eventName string, event *github.PullRequestEvent) error
 	DispatchReview(ctx context.Context, deliveryID string, eventName string, event *github.PullRequestReviewEvent) error
+	DispatchCheckSuite(ctx context.Context, deliveryID string, eventName string, event *github.CheckSuiteEvent) error
+	DispatchCheckRun(ctx context.Context, deliveryID string, eventName string, event *github.CheckRunEvent) error
+	DispatchStatus(ctx context.Context, deliveryID string, eventName string, event *github.StatusEvent) error
+	DispatchComment(ctx context.Context, deliveryID string, eventName string, event *github.IssueCommentEvent) error
 }
 
 type dispatcher struct {
-	handler EventHandler
-	filter  EventFilter
-	metrics metrics.Emitter
+	handler        EventHandler
+	filter         EventFilter
+	commands       CommandHandler
+	api            gh.API
+	metrics        metrics.Emitter
+	serviceAccount string
+	appID          int64
+	checkName      string
 }
 
-func NewDispatcher(eh EventHandler, ef EventFilter, m metrics.Emitter) Dispatcher {
+// NewDispatcher returns a Dispatcher,
+// label and review request changes made by serviceAccount are ignored, since they are the result of an evaluation.
+// check suites of the app appID and check runs named checkName are ignored for the same reason,
+// they are the check runs published by pr-bot.
+func NewDispatcher(eh EventHandler, ef EventFilter, ch CommandHandler, api gh.API, m metrics.Emitter,
+	serviceAccount string, appID int64, checkName string) Dispatcher {
 	return &dispatcher{
-		handler: eh,
-		filter:  ef,
-		metrics: m,
+		handler:        eh,
+		filter:         ef,
+		commands:       ch,
+		api:            api,
+		metrics:        m,
+		serviceAccount: serviceAccount,
+		appID:          appID,
+		checkName:      checkName,
 	}
 }
 
@@ -80,9 +123,9 @@ func (d *dispatcher) Dispatch(ctx context.Context, _ string, eventName string, e
 		return parseError(ctx, ErrPRNotFound)
 	}
 
-	visibility := aws.ToString(event.Repo.Visibility)
-	if visibility != "public" {
-		return nil
+	allowed, err := d.allowsVisibility(ctx, event.Repo)
+	if err != nil || !allowed {
+		return err
 	}
 
 	action := *event.Action
@@ -99,6 +142,7 @@ func (d *dispatcher) Dispatch(ctx context.Context, _ string, eventName string, e
 		RepoFullName: *event.Repo.FullName,
 		Author:       *event.PullRequest.User.Login,
 		URL:          *event.PullRequest.HTMLURL,
+		HeadSHA:      event.PullRequest.GetHead().GetSHA(),
 	}
 
 	shouldHandle, err := d.filter.ShouldHandle(ctx, id)
@@ -117,10 +161,18 @@ func (d *dispatcher) Dispatch(ctx context.Context, _ string, eventName string, e
 		if strings.HasPrefix(id.Author, "svc-") {
 			d.metrics.EmitDist(ctx, "openedPRs", 1.0, id.ToTags())
 		}
-		fallthrough
+		return d.handler.EvalAndReviewPREvent(ctx, id, event)
 
-	case ReopenedAction, EditedAction, LabeledAction,
-		UnlabeledAction, ReviewRequested, ReviewRequestRemoved,
+	case LabeledAction, UnlabeledAction, ReviewRequested, ReviewRequestRemoved:
+		if d.serviceAccount != "" && event.GetSender().GetLogin() == d.serviceAccount {
+			// labels and reviewers applied by pr-bot would otherwise trigger another evaluation
+			oplog.Info().Msgf("ignoring %s by %s", action, d.serviceAccount)
+			d.metrics.EmitDist(ctx, "ignoredOwnChanges", 1, append(id.ToTags(), fmt.Sprintf("action:%s", action)))
+			return nil
+		}
+		return d.handler.EvalAndReviewPREvent(ctx, id, event)
+
+	case ReopenedAction, EditedAction,
 		AssignedAction, UnassignedAction, SynchronizeAction:
 		return d.handler.EvalAndReviewPREvent(ctx, id, event)
 
@@ -150,9 +202,9 @@ func (d *dispatcher) DispatchReview(ctx context.Context, _ string, eventName str
 		return parseError(ctx, ErrPRNotFound)
 	}
 
-	visibility := aws.ToString(event.Repo.Visibility)
-	if visibility != "public" {
-		return nil
+	allowed, err := d.allowsVisibility(ctx, event.Repo)
+	if err != nil || !allowed {
+		return err
 	}
 
 	action := *event.Action
@@ -169,6 +221,7 @@ func (d *dispatcher) DispatchReview(ctx context.Context, _ string, eventName str
 		RepoFullName: *event.Repo.FullName,
 		Author:       *event.PullRequest.User.Login,
 		URL:          *event.PullRequest.HTMLURL,
+		HeadSHA:      event.PullRequest.GetHead().GetSHA(),
 	}
 
 	shouldHandle, err := d.filter.ShouldHandle(ctx, id)
@@ -193,6 +246,286 @@ func (d *dispatcher) DispatchReview(ctx context.Context, _ string, eventName str
 	return nil
 }
 
+func (d *dispatcher) DispatchCheckSuite(ctx context.Context, _ string, eventName string, event *github.CheckSuiteEvent) error {
+	oplog := httplog.LogEntry(ctx)
+
+	if eventName != EventNameCheckSuite {
+		oplog.Err(ErrMismatchedCheckSuiteEvent).Send()
+		return parseError(ctx, ErrMismatchedCheckSuiteEvent)
+	}
+
+	if event == nil || event.Action == nil || len(*event.Action) == 0 {
+		oplog.Err(ErrEventActionNotFound).Send()
+		return parseError(ctx, ErrEventActionNotFound)
+	}
+
+	if event.CheckSuite == nil || event.CheckSuite.HeadSHA == nil {
+		oplog.Err(ErrCheckSuiteNotFound).Send()
+		return parseError(ctx, ErrCheckSuiteNotFound)
+	}
+
+	action := *event.Action
+	if action != CompletedAction {
+		oplog.Info().Msgf("No Handlers registered for Event: %s and Action: %s", eventName, action)
+		return nil
+	}
+	if d.isOwnApp(event.CheckSuite.GetApp()) {
+		oplog.Info().Msgf("ignoring %s of pr-bot", eventName)
+		return nil
+	}
+	return d.dispatchCommit(ctx, eventName, action, event.CheckSuite.GetHeadSHA(), event.Repo, event.Org)
+}
+
+func (d *dispatcher) DispatchCheckRun(ctx context.Context, _ string, eventName string, event *github.CheckRunEvent) error {
+	oplog := httplog.LogEntry(ctx)
+
+	if eventName != EventNameCheckRun {
+		oplog.Err(ErrMismatchedCheckRunEvent).Send()
+		return parseError(ctx, ErrMismatchedCheckRunEvent)
+	}
+
+	if event == nil || event.Action == nil || len(*event.Action) == 0 {
+		oplog.Err(ErrEventActionNotFound).Send()
+		return parseError(ctx, ErrEventActionNotFound)
+	}
+
+	if event.CheckRun == nil || event.CheckRun.HeadSHA == nil {
+		oplog.Err(ErrCheckRunNotFound).Send()
+		return parseError(ctx, ErrCheckRunNotFound)
+	}
+
+	action := *event.Action
+	if action != CompletedAction {
+		oplog.Info().Msgf("No Handlers registered for Event: %s and Action: %s", eventName, action)
+		return nil
+	}
+	if d.isOwnApp(event.CheckRun.GetApp()) || (d.checkName != "" && event.CheckRun.GetName() == d.checkName) {
+		oplog.Info().Msgf("ignoring %s %s of pr-bot", eventName, event.CheckRun.GetName())
+		return nil
+	}
+	return d.dispatchCommit(ctx, eventName, action, event.CheckRun.GetHeadSHA(), event.Repo, event.Org)
+}
+
+// isOwnApp returns true if app is the GitHub App pr-bot authenticates as.
+func (d *dispatcher) isOwnApp(app *github.App) bool {
+	return d.appID != 0 && app.GetID() == d.appID
+}
+
+// DispatchStatus implements Dispatcher.
+// status events do not have an action, state of the status is used as the action.
+func (d *dispatcher) DispatchStatus(ctx context.Context, _ string, eventName string, event *github.StatusEvent) error {
+	oplog := httplog.LogEntry(ctx)
+
+	if eventName != EventNameStatus {
+		oplog.Err(ErrMismatchedStatusEvent).Send()
+		return parseError(ctx, ErrMismatchedStatusEvent)
+	}
+
+	if event == nil || event.SHA == nil || event.State == nil {
+		oplog.Err(ErrStatusNotFound).Send()
+		return parseError(ctx, ErrStatusNotFound)
+	}
+
+	state := *event.State
+	if state == StatusPending {
+		oplog.Info().Msgf("No Handlers registered for Event: %s and State: %s", eventName, state)
+		return nil
+	}
+	return d.dispatchCommit(ctx, eventName, state, event.GetSHA(), event.Repo, nil)
+}
+
+// dispatchCommit evaluates every open PR whose head is the commit.
+func (d *dispatcher) dispatchCommit(ctx context.Context, eventName, action, sha string,
+	repo *github.Repository, org *github.Organization) error {
+
+	oplog := httplog.LogEntry(ctx)
+	if repo == nil || repo.Owner == nil {
+		oplog.Err(ErrRepoNotFound).Send()
+		return parseError(ctx, ErrRepoNotFound)
+	}
+
+	allowed, err := d.allowsVisibility(ctx, repo)
+	if err != nil || !allowed {
+		return err
+	}
+
+	httplog.LogEntrySetField(ctx, "action", action)
+	httplog.LogEntrySetField(ctx, "repo", repo.GetFullName())
+	httplog.LogEntrySetField(ctx, "sha", sha)
+	oplog = httplog.LogEntry(ctx)
+
+	repoID := id.PR{
+		Owner:        repo.GetOwner().GetLogin(),
+		Repo:         repo.GetName(),
+		RepoFullName: repo.GetFullName(),
+	}
+	prs, err := d.api.ListPullRequestsWithCommit(ctx, repoID, sha)
+	switch {
+	case errors.Is(err, gh.ErrTruncated):
+		// PRs beyond the cap are evaluated on their next event
+		oplog.Warn().Err(err).Msgf("dispatching the first %d PRs with commit %v", len(prs), sha)
+	case err != nil:
+		oplog.Err(err).Msgf("error listing PRs with commit %v", sha)
+		return err
+	}
+
+	errs := make([]error, 0)
+	for _, pr := range prs {
+		// commit can be part of a PR without being its head
+		if pr.GetState() != "open" || pr.GetHead().GetSHA() != sha {
+			continue
+		}
+		err = d.dispatchCommitPR(ctx, eventName, action, repoID, pr.GetNumber(), repo, org)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (d *dispatcher) dispatchCommitPR(ctx context.Context, eventName, action string, repoID id.PR, number int,
+	repo *github.Repository, org *github.Organization) error {
+
+	pr, id, err := d.getPR(ctx, repoID, number)
+	if err != nil {
+		return err
+	}
+
+	shouldHandle, err := d.filter.ShouldHandle(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !shouldHandle {
+		d.metrics.EmitDist(ctx, "ignoredRepos", 1, id.ToTags())
+		return nil
+	}
+
+	return d.handler.EvalAndReview(ctx, id, input.GHE{
+		Event:        eventName,
+		Action:       action,
+		PullRequest:  pr,
+		Repository:   repo,
+		Organization: org,
+	})
+}
+
+// DispatchComment implements Dispatcher.
+// only new comments on PRs which contain a command are handled.
+func (d *dispatcher) DispatchComment(ctx context.Context, _ string, eventName string, event *github.IssueCommentEvent) error {
+	oplog := httplog.LogEntry(ctx)
+
+	if eventName != EventNameComment {
+		oplog.Err(ErrMismatchedCommentEvent).Send()
+		return parseError(ctx, ErrMismatchedCommentEvent)
+	}
+
+	if event == nil || event.Action == nil || len(*event.Action) == 0 {
+		oplog.Err(ErrEventActionNotFound).Send()
+		return parseError(ctx, ErrEventActionNotFound)
+	}
+
+	if event.Issue == nil || event.Comment == nil {
+		oplog.Err(ErrCommentNotFound).Send()
+		return parseError(ctx, ErrCommentNotFound)
+	}
+
+	if event.Repo == nil || event.Repo.Owner == nil {
+		oplog.Err(ErrRepoNotFound).Send()
+		return parseError(ctx, ErrRepoNotFound)
+	}
+
+	action := *event.Action
+	if action != CreatedAction || !event.Issue.IsPullRequest() {
+		oplog.Info().Msgf("No Handlers registered for Event: %s and Action: %s", eventName, action)
+		return nil
+	}
+
+	cmd, ok := ParseCommand(event.Comment.GetBody())
+	// ignore comments from bots, including replies from pr-bot
+	if !ok || event.Comment.GetUser().GetType() == "Bot" {
+		return nil
+	}
+
+	allowed, err := d.allowsVisibility(ctx, event.Repo)
+	if err != nil || !allowed {
+		return err
+	}
+
+	httplog.LogEntrySetField(ctx, "action", action)
+	httplog.LogEntrySetField(ctx, "repo", event.Repo.GetFullName())
+	httplog.LogEntrySetField(ctx, "pr", fmt.Sprint(event.Issue.GetNumber()))
+	httplog.LogEntrySetField(ctx, "command", cmd.Name)
+
+	repoID := id.PR{
+		Owner:        event.Repo.GetOwner().GetLogin(),
+		Repo:         event.Repo.GetName(),
+		RepoFullName: event.Repo.GetFullName(),
+	}
+	pr, id, err := d.getPR(ctx, repoID, event.Issue.GetNumber())
+	if err != nil {
+		return err
+	}
+
+	shouldHandle, err := d.filter.ShouldHandle(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !shouldHandle {
+		d.metrics.EmitDist(ctx, "ignoredRepos", 1, id.ToTags())
+		return nil
+	}
+
+	return d.commands.Handle(ctx, id, cmd, event.Comment.GetUser().GetLogin(), input.GHE{
+		Event:        eventName,
+		Action:       action,
+		PullRequest:  pr,
+		Repository:   event.Repo,
+		Organization: event.Organization,
+	})
+}
+
+// getPR fetches the PR, events which are not PR events do not have all the fields of a PR.
+func (d *dispatcher) getPR(ctx context.Context, repoID id.PR, number int) (*github.PullRequest, id.PR, error) {
+	oplog := httplog.LogEntry(ctx)
+	repoID.Number = number
+	pr, err := d.api.GetPullRequest(ctx, repoID)
+	if err != nil {
+		oplog.Err(err).Msgf("error getting PR %d", number)
+		return nil, id.PR{}, err
+	}
+
+	return pr, id.PR{
+		Owner:        repoID.Owner,
+		Repo:         repoID.Repo,
+		Number:       pr.GetNumber(),
+		NodeID:       pr.GetNodeID(),
+		RepoFullName: repoID.RepoFullName,
+		Author:       pr.GetUser().GetLogin(),
+		URL:          pr.GetHTMLURL(),
+		HeadSHA:      pr.GetHead().GetSHA(),
+	}, nil
+}
+
+// allowsVisibility checks if PRs in repos with the visibility of repo are handled.
+func (d *dispatcher) allowsVisibility(ctx context.Context, repo *github.Repository) (bool, error) {
+	oplog := httplog.LogEntry(ctx)
+	visibility := repo.GetVisibility()
+	allowed, err := d.filter.AllowsVisibility(ctx, visibility)
+	if err != nil {
+		return false, err
+	}
+	if !allowed {
+		oplog.Info().Msgf("ignoring event from %v repo %v", visibility, repo.GetFullName())
+		d.metrics.EmitDist(ctx, "ignoredVisibility", 1, []string{
+			fmt.Sprintf("repoFullName:%s", repo.GetFullName()),
+			fmt.Sprintf("visibility:%s", visibility),
+		})
+	}
+	return allowed, nil
+}
+
 func parseError(ctx context.Context, err error) error {
 	return pe.InValidRequestError(ctx, "error parsing webhook event", err)
 }
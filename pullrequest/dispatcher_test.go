@@ -9,9 +9,13 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/google/go-github/v50/github"
 	pe "github.com/marqeta/pr-bot/errors"
+	gh "github.com/marqeta/pr-bot/github"
 	"github.com/marqeta/pr-bot/id"
 	"github.com/marqeta/pr-bot/metrics"
+	"github.com/marqeta/pr-bot/opa/input"
 	"github.com/marqeta/pr-bot/pullrequest"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
 )
 
 var errRandom = errors.New("random error")
@@ -58,6 +62,87 @@ func Test_dispatcher_Dispatch(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		{
+			name: "Should skip labels added by pr-bot",
+			args: args{
+				deliveryID: "123",
+				eventName:  pullrequest.EventName,
+				event:      sentBy(prEvent(github.String("labeled"), sampleID()), "pr-bot"),
+			},
+			setExpectations: func(id id.PR, _ *github.PullRequestEvent,
+				f *pullrequest.MockEventFilter, _ *pullrequest.MockEventHandler) {
+				f.EXPECT().ShouldHandle(ctx, id).Return(true, nil).Once()
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Should skip labels removed by pr-bot",
+			args: args{
+				deliveryID: "123",
+				eventName:  pullrequest.EventName,
+				event:      sentBy(prEvent(github.String("unlabeled"), sampleID()), "pr-bot"),
+			},
+			setExpectations: func(id id.PR, _ *github.PullRequestEvent,
+				f *pullrequest.MockEventFilter, _ *pullrequest.MockEventHandler) {
+				f.EXPECT().ShouldHandle(ctx, id).Return(true, nil).Once()
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Should dispatch PRs opened by pr-bot",
+			args: args{
+				deliveryID: "123",
+				eventName:  pullrequest.EventName,
+				event:      sentBy(prEvent(github.String("opened"), sampleID()), "pr-bot"),
+			},
+			setExpectations: func(id id.PR, event *github.PullRequestEvent,
+				f *pullrequest.MockEventFilter, h *pullrequest.MockEventHandler) {
+				f.EXPECT().ShouldHandle(ctx, id).Return(true, nil).Once()
+				h.EXPECT().EvalAndReviewPREvent(ctx, id, event).Return(nil).Once()
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Should dispatch labels added by users",
+			args: args{
+				deliveryID: "123",
+				eventName:  pullrequest.EventName,
+				event:      sentBy(prEvent(github.String("labeled"), sampleID()), "user1"),
+			},
+			setExpectations: func(id id.PR, event *github.PullRequestEvent,
+				f *pullrequest.MockEventFilter, h *pullrequest.MockEventHandler) {
+				f.EXPECT().ShouldHandle(ctx, id).Return(true, nil).Once()
+				h.EXPECT().EvalAndReviewPREvent(ctx, id, event).Return(nil).Once()
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Should skip reviewers requested by pr-bot",
+			args: args{
+				deliveryID: "123",
+				eventName:  pullrequest.EventName,
+				event:      sentBy(prEvent(github.String("review_requested"), sampleID()), "pr-bot"),
+			},
+			setExpectations: func(id id.PR, _ *github.PullRequestEvent,
+				f *pullrequest.MockEventFilter, _ *pullrequest.MockEventHandler) {
+				f.EXPECT().ShouldHandle(ctx, id).Return(true, nil).Once()
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Should dispatch review requests removed by users",
+			args: args{
+				deliveryID: "123",
+				eventName:  pullrequest.EventName,
+				event:      sentBy(prEvent(github.String("review_request_removed"), sampleID()), "user1"),
+			},
+			setExpectations: func(id id.PR, event *github.PullRequestEvent,
+				f *pullrequest.MockEventFilter, h *pullrequest.MockEventHandler) {
+				f.EXPECT().ShouldHandle(ctx, id).Return(true, nil).Once()
+				h.EXPECT().EvalAndReviewPREvent(ctx, id, event).Return(nil).Once()
+			},
+			wantErr: nil,
+		},
 		{
 			name: "Should dispatch PR edited Event",
 			args: args{
@@ -312,10 +397,39 @@ func Test_dispatcher_Dispatch(t *testing.T) {
 				event:      private(prEvent(github.String("labeled"), sampleID())),
 			},
 			setExpectations: func(_ id.PR, _ *github.PullRequestEvent,
-				_ *pullrequest.MockEventFilter, _ *pullrequest.MockEventHandler) {
+				f *pullrequest.MockEventFilter, _ *pullrequest.MockEventHandler) {
+				f.EXPECT().AllowsVisibility(ctx, "private").Return(false, nil).Once()
 			},
 			wantErr: nil,
 		},
+		{
+			name: "Should dispatch internal repo PR event when visibility is allowed",
+			args: args{
+				deliveryID: "123",
+				eventName:  pullrequest.EventName,
+				event:      internal(prEvent(github.String("labeled"), sampleID())),
+			},
+			setExpectations: func(id id.PR, event *github.PullRequestEvent,
+				f *pullrequest.MockEventFilter, h *pullrequest.MockEventHandler) {
+				f.EXPECT().AllowsVisibility(ctx, "internal").Return(true, nil).Once()
+				f.EXPECT().ShouldHandle(ctx, id).Return(true, nil).Once()
+				h.EXPECT().EvalAndReviewPREvent(ctx, id, event).Return(nil).Once()
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Error when visibility filter returns error",
+			args: args{
+				deliveryID: "123",
+				eventName:  pullrequest.EventName,
+				event:      internal(prEvent(github.String("labeled"), sampleID())),
+			},
+			setExpectations: func(_ id.PR, _ *github.PullRequestEvent,
+				f *pullrequest.MockEventFilter, _ *pullrequest.MockEventHandler) {
+				f.EXPECT().AllowsVisibility(ctx, "internal").Return(false, errRandom).Once()
+			},
+			wantErr: errRandom,
+		},
 		{
 			name: "Skip dispatch when event.Repo.Visibility is nil",
 			args: args{
@@ -324,8 +438,9 @@ func Test_dispatcher_Dispatch(t *testing.T) {
 				event:      prEvent(github.String("labeled"), sampleID()),
 			},
 			setExpectations: func(_ id.PR, event *github.PullRequestEvent,
-				_ *pullrequest.MockEventFilter, _ *pullrequest.MockEventHandler) {
+				f *pullrequest.MockEventFilter, _ *pullrequest.MockEventHandler) {
 				event.Repo.Visibility = nil
+				f.EXPECT().AllowsVisibility(ctx, "").Return(false, nil).Once()
 			},
 			wantErr: nil,
 		},
@@ -337,8 +452,9 @@ func Test_dispatcher_Dispatch(t *testing.T) {
 				event:      prEvent(github.String("labeled"), sampleID()),
 			},
 			setExpectations: func(_ id.PR, event *github.PullRequestEvent,
-				_ *pullrequest.MockEventFilter, _ *pullrequest.MockEventHandler) {
+				f *pullrequest.MockEventFilter, _ *pullrequest.MockEventHandler) {
 				event.Repo.Visibility = github.String("")
+				f.EXPECT().AllowsVisibility(ctx, "").Return(false, nil).Once()
 			},
 			wantErr: nil,
 		},
@@ -347,7 +463,8 @@ func Test_dispatcher_Dispatch(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			handler := pullrequest.NewMockEventHandler(t)
 			filter := pullrequest.NewMockEventFilter(t)
-			d := pullrequest.NewDispatcher(handler, filter, metrics.NewNoopEmitter())
+			filter.EXPECT().AllowsVisibility(ctx, "public").Return(true, nil).Maybe()
+			d := pullrequest.NewDispatcher(handler, filter, pullrequest.NewMockCommandHandler(t), gh.NewMockAPI(t), metrics.NewNoopEmitter(), "pr-bot", 42, "pr-bot")
 
 			tt.setExpectations(sampleID(), tt.args.event, filter, handler)
 			err := d.Dispatch(ctx, tt.args.deliveryID, tt.args.eventName, tt.args.event)
@@ -446,7 +563,8 @@ func Test_dispatcher_DispatchReview(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			handler := pullrequest.NewMockEventHandler(t)
 			filter := pullrequest.NewMockEventFilter(t)
-			d := pullrequest.NewDispatcher(handler, filter, metrics.NewNoopEmitter())
+			filter.EXPECT().AllowsVisibility(ctx, "public").Return(true, nil).Maybe()
+			d := pullrequest.NewDispatcher(handler, filter, pullrequest.NewMockCommandHandler(t), gh.NewMockAPI(t), metrics.NewNoopEmitter(), "pr-bot", 42, "pr-bot")
 
 			tt.setExpectations(sampleID(), tt.args.event, filter, handler)
 			err := d.DispatchReview(ctx, tt.args.deliveryID, tt.args.eventName, tt.args.event)
@@ -459,6 +577,435 @@ func Test_dispatcher_DispatchReview(t *testing.T) {
 	}
 }
 
+func Test_dispatcher_DispatchCheckSuite(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name            string
+		event           *github.CheckSuiteEvent
+		setExpectations func(id id.PR, event *github.CheckSuiteEvent, api *gh.MockAPI,
+			f *pullrequest.MockEventFilter, h *pullrequest.MockEventHandler)
+		wantErr error
+	}{
+		{
+			name:  "Should evaluate open PRs with commit as head",
+			event: checkSuiteEvent(github.String("completed"), sampleID()),
+			setExpectations: func(id id.PR, event *github.CheckSuiteEvent, api *gh.MockAPI,
+				f *pullrequest.MockEventFilter, h *pullrequest.MockEventHandler) {
+				pr := prEvent(github.String("opened"), id).PullRequest
+				api.EXPECT().ListPullRequestsWithCommit(ctx, repoID(id), "sha1").Return([]*github.PullRequest{
+					listedPR(1, "open", "sha1"),
+					listedPR(2, "open", "sha0"),
+					listedPR(3, "closed", "sha1"),
+				}, nil).Once()
+				api.EXPECT().GetPullRequest(ctx, prID(id, 1)).Return(pr, nil).Once()
+				f.EXPECT().ShouldHandle(ctx, id).Return(true, nil).Once()
+				h.EXPECT().EvalAndReview(ctx, id, input.GHE{
+					Event:        pullrequest.EventNameCheckSuite,
+					Action:       "completed",
+					PullRequest:  pr,
+					Repository:   event.Repo,
+					Organization: event.Org,
+				}).Return(nil).Once()
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "Should skip PRs from ignored repos",
+			event: checkSuiteEvent(github.String("completed"), sampleID()),
+			setExpectations: func(id id.PR, _ *github.CheckSuiteEvent, api *gh.MockAPI,
+				f *pullrequest.MockEventFilter, _ *pullrequest.MockEventHandler) {
+				api.EXPECT().ListPullRequestsWithCommit(ctx, repoID(id), "sha1").Return([]*github.PullRequest{
+					listedPR(1, "open", "sha1"),
+				}, nil).Once()
+				api.EXPECT().GetPullRequest(ctx, prID(id, 1)).
+					Return(prEvent(github.String("opened"), id).PullRequest, nil).Once()
+				f.EXPECT().ShouldHandle(ctx, id).Return(false, nil).Once()
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "Should evaluate PRs listed before the cap",
+			event: checkSuiteEvent(github.String("completed"), sampleID()),
+			setExpectations: func(id id.PR, _ *github.CheckSuiteEvent, api *gh.MockAPI,
+				f *pullrequest.MockEventFilter, h *pullrequest.MockEventHandler) {
+				api.EXPECT().ListPullRequestsWithCommit(ctx, repoID(id), "sha1").Return([]*github.PullRequest{
+					listedPR(1, "open", "sha1"),
+				}, fmt.Errorf("random: %w", gh.ErrTruncated)).Once()
+				api.EXPECT().GetPullRequest(ctx, prID(id, 1)).
+					Return(prEvent(github.String("opened"), id).PullRequest, nil).Once()
+				f.EXPECT().ShouldHandle(ctx, id).Return(true, nil).Once()
+				h.EXPECT().EvalAndReview(ctx, id, mock.Anything).Return(nil).Once()
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "Should return error from listing PRs",
+			event: checkSuiteEvent(github.String("completed"), sampleID()),
+			setExpectations: func(id id.PR, _ *github.CheckSuiteEvent, api *gh.MockAPI,
+				_ *pullrequest.MockEventFilter, _ *pullrequest.MockEventHandler) {
+				api.EXPECT().ListPullRequestsWithCommit(ctx, repoID(id), "sha1").Return(nil, errRandom).Once()
+			},
+			wantErr: errRandom,
+		},
+		{
+			name:  "Should return error from evaluating PR",
+			event: checkSuiteEvent(github.String("completed"), sampleID()),
+			setExpectations: func(id id.PR, _ *github.CheckSuiteEvent, api *gh.MockAPI,
+				f *pullrequest.MockEventFilter, h *pullrequest.MockEventHandler) {
+				api.EXPECT().ListPullRequestsWithCommit(ctx, repoID(id), "sha1").Return([]*github.PullRequest{
+					listedPR(1, "open", "sha1"),
+				}, nil).Once()
+				api.EXPECT().GetPullRequest(ctx, prID(id, 1)).
+					Return(prEvent(github.String("opened"), id).PullRequest, nil).Once()
+				f.EXPECT().ShouldHandle(ctx, id).Return(true, nil).Once()
+				h.EXPECT().EvalAndReview(ctx, id, mock.Anything).Return(errRandom).Once()
+			},
+			wantErr: errRandom,
+		},
+		{
+			name: "Should ignore check suites of pr-bot",
+			event: func() *github.CheckSuiteEvent {
+				e := checkSuiteEvent(github.String("completed"), sampleID())
+				e.CheckSuite.App = &github.App{ID: github.Int64(42)}
+				return e
+			}(),
+			setExpectations: func(_ id.PR, _ *github.CheckSuiteEvent, _ *gh.MockAPI,
+				_ *pullrequest.MockEventFilter, _ *pullrequest.MockEventHandler) {
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "Should return silently when check suite is not completed",
+			event: checkSuiteEvent(github.String("requested"), sampleID()),
+			setExpectations: func(_ id.PR, _ *github.CheckSuiteEvent, _ *gh.MockAPI,
+				_ *pullrequest.MockEventFilter, _ *pullrequest.MockEventHandler) {
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Skip dispatch when event.Repo.Visibility is private",
+			event: func() *github.CheckSuiteEvent {
+				e := checkSuiteEvent(github.String("completed"), sampleID())
+				e.Repo.Visibility = github.String("private")
+				return e
+			}(),
+			setExpectations: func(_ id.PR, _ *github.CheckSuiteEvent, _ *gh.MockAPI,
+				f *pullrequest.MockEventFilter, _ *pullrequest.MockEventHandler) {
+				f.EXPECT().AllowsVisibility(ctx, "private").Return(false, nil).Once()
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "Error when Event action is nil",
+			event: checkSuiteEvent(nil, sampleID()),
+			setExpectations: func(_ id.PR, _ *github.CheckSuiteEvent, _ *gh.MockAPI,
+				_ *pullrequest.MockEventFilter, _ *pullrequest.MockEventHandler) {
+			},
+			wantErr: pe.InValidRequestError(ctx, "error parsing webhook event", pullrequest.ErrEventActionNotFound),
+		},
+		{
+			name: "Error when Event check suite is nil",
+			event: &github.CheckSuiteEvent{
+				Action: github.String("completed"),
+			},
+			setExpectations: func(_ id.PR, _ *github.CheckSuiteEvent, _ *gh.MockAPI,
+				_ *pullrequest.MockEventFilter, _ *pullrequest.MockEventHandler) {
+			},
+			wantErr: pe.InValidRequestError(ctx, "error parsing webhook event", pullrequest.ErrCheckSuiteNotFound),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := pullrequest.NewMockEventHandler(t)
+			filter := pullrequest.NewMockEventFilter(t)
+			filter.EXPECT().AllowsVisibility(ctx, "public").Return(true, nil).Maybe()
+			api := gh.NewMockAPI(t)
+			d := pullrequest.NewDispatcher(handler, filter, pullrequest.NewMockCommandHandler(t), api, metrics.NewNoopEmitter(), "pr-bot", 42, "pr-bot")
+
+			tt.setExpectations(sampleID(), tt.event, api, filter, handler)
+			err := d.DispatchCheckSuite(ctx, "123", pullrequest.EventNameCheckSuite, tt.event)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("dispatcher.DispatchCheckSuite() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_dispatcher_DispatchCheckRun(t *testing.T) {
+	ctx := context.Background()
+	id := sampleID()
+	handler := pullrequest.NewMockEventHandler(t)
+	filter := pullrequest.NewMockEventFilter(t)
+	filter.EXPECT().AllowsVisibility(ctx, "public").Return(true, nil).Maybe()
+	api := gh.NewMockAPI(t)
+	d := pullrequest.NewDispatcher(handler, filter, pullrequest.NewMockCommandHandler(t), api, metrics.NewNoopEmitter(), "pr-bot", 42, "pr-bot")
+
+	suite := checkSuiteEvent(github.String("completed"), id)
+	event := &github.CheckRunEvent{
+		Action:   github.String("completed"),
+		CheckRun: &github.CheckRun{HeadSHA: github.String("sha1")},
+		Repo:     suite.Repo,
+	}
+	pr := prEvent(github.String("opened"), id).PullRequest
+	api.EXPECT().ListPullRequestsWithCommit(ctx, repoID(id), "sha1").
+		Return([]*github.PullRequest{listedPR(1, "open", "sha1")}, nil).Once()
+	api.EXPECT().GetPullRequest(ctx, prID(id, 1)).Return(pr, nil).Once()
+	filter.EXPECT().ShouldHandle(ctx, id).Return(true, nil).Once()
+	handler.EXPECT().EvalAndReview(ctx, id, input.GHE{
+		Event:       pullrequest.EventNameCheckRun,
+		Action:      "completed",
+		PullRequest: pr,
+		Repository:  event.Repo,
+	}).Return(nil).Once()
+
+	assert.Nil(t, d.DispatchCheckRun(ctx, "123", pullrequest.EventNameCheckRun, event))
+
+	event.Action = github.String("created")
+	assert.Nil(t, d.DispatchCheckRun(ctx, "123", pullrequest.EventNameCheckRun, event))
+
+	// check runs of pr-bot are not dispatched, publishing them would trigger another evaluation
+	event.Action = github.String("completed")
+	event.CheckRun.Name = github.String("pr-bot")
+	assert.Nil(t, d.DispatchCheckRun(ctx, "123", pullrequest.EventNameCheckRun, event))
+	event.CheckRun.Name = github.String("ci")
+	event.CheckRun.App = &github.App{ID: github.Int64(42)}
+	assert.Nil(t, d.DispatchCheckRun(ctx, "123", pullrequest.EventNameCheckRun, event))
+
+	err := d.DispatchCheckRun(ctx, "123", pullrequest.EventNameCheckRun, &github.CheckRunEvent{Action: github.String("completed")})
+	assert.ErrorIs(t, err, pe.InValidRequestError(ctx, "error parsing webhook event", pullrequest.ErrCheckRunNotFound))
+}
+
+func Test_dispatcher_DispatchStatus(t *testing.T) {
+	ctx := context.Background()
+	id := sampleID()
+	handler := pullrequest.NewMockEventHandler(t)
+	filter := pullrequest.NewMockEventFilter(t)
+	filter.EXPECT().AllowsVisibility(ctx, "public").Return(true, nil).Maybe()
+	api := gh.NewMockAPI(t)
+	d := pullrequest.NewDispatcher(handler, filter, pullrequest.NewMockCommandHandler(t), api, metrics.NewNoopEmitter(), "pr-bot", 42, "pr-bot")
+
+	event := &github.StatusEvent{
+		SHA:   github.String("sha1"),
+		State: github.String("success"),
+		Repo:  checkSuiteEvent(github.String("completed"), id).Repo,
+	}
+	pr := prEvent(github.String("opened"), id).PullRequest
+	api.EXPECT().ListPullRequestsWithCommit(ctx, repoID(id), "sha1").
+		Return([]*github.PullRequest{listedPR(1, "open", "sha1")}, nil).Once()
+	api.EXPECT().GetPullRequest(ctx, prID(id, 1)).Return(pr, nil).Once()
+	filter.EXPECT().ShouldHandle(ctx, id).Return(true, nil).Once()
+	handler.EXPECT().EvalAndReview(ctx, id, input.GHE{
+		Event:       pullrequest.EventNameStatus,
+		Action:      "success",
+		PullRequest: pr,
+		Repository:  event.Repo,
+	}).Return(nil).Once()
+
+	assert.Nil(t, d.DispatchStatus(ctx, "123", pullrequest.EventNameStatus, event))
+
+	event.State = github.String(pullrequest.StatusPending)
+	assert.Nil(t, d.DispatchStatus(ctx, "123", pullrequest.EventNameStatus, event))
+
+	err := d.DispatchStatus(ctx, "123", pullrequest.EventNameStatus, &github.StatusEvent{})
+	assert.ErrorIs(t, err, pe.InValidRequestError(ctx, "error parsing webhook event", pullrequest.ErrStatusNotFound))
+}
+
+func Test_dispatcher_DispatchComment(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name            string
+		event           *github.IssueCommentEvent
+		setExpectations func(id id.PR, event *github.IssueCommentEvent, api *gh.MockAPI,
+			f *pullrequest.MockEventFilter, c *pullrequest.MockCommandHandler)
+		wantErr error
+	}{
+		{
+			name:  "Should handle command in PR comment",
+			event: commentEvent(github.String("created"), "/pr-bot skip m1", sampleID()),
+			setExpectations: func(id id.PR, event *github.IssueCommentEvent, api *gh.MockAPI,
+				f *pullrequest.MockEventFilter, c *pullrequest.MockCommandHandler) {
+				pr := prEvent(github.String("opened"), id).PullRequest
+				api.EXPECT().GetPullRequest(ctx, prID(id, 1)).Return(pr, nil).Once()
+				f.EXPECT().ShouldHandle(ctx, id).Return(true, nil).Once()
+				c.EXPECT().Handle(ctx, id, pullrequest.Command{Name: "skip", Args: []string{"m1"}}, "user2", input.GHE{
+					Event:        pullrequest.EventNameComment,
+					Action:       "created",
+					PullRequest:  pr,
+					Repository:   event.Repo,
+					Organization: event.Organization,
+				}).Return(nil).Once()
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "Should return error from command handler",
+			event: commentEvent(github.String("created"), "/pr-bot reevaluate", sampleID()),
+			setExpectations: func(id id.PR, _ *github.IssueCommentEvent, api *gh.MockAPI,
+				f *pullrequest.MockEventFilter, c *pullrequest.MockCommandHandler) {
+				api.EXPECT().GetPullRequest(ctx, prID(id, 1)).
+					Return(prEvent(github.String("opened"), id).PullRequest, nil).Once()
+				f.EXPECT().ShouldHandle(ctx, id).Return(true, nil).Once()
+				c.EXPECT().Handle(ctx, id, mock.Anything, "user2", mock.Anything).Return(errRandom).Once()
+			},
+			wantErr: errRandom,
+		},
+		{
+			name:  "Should skip commands from ignored repos",
+			event: commentEvent(github.String("created"), "/pr-bot reevaluate", sampleID()),
+			setExpectations: func(id id.PR, _ *github.IssueCommentEvent, api *gh.MockAPI,
+				f *pullrequest.MockEventFilter, _ *pullrequest.MockCommandHandler) {
+				api.EXPECT().GetPullRequest(ctx, prID(id, 1)).
+					Return(prEvent(github.String("opened"), id).PullRequest, nil).Once()
+				f.EXPECT().ShouldHandle(ctx, id).Return(false, nil).Once()
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "Should ignore comments without a command",
+			event: commentEvent(github.String("created"), "looks good", sampleID()),
+			setExpectations: func(_ id.PR, _ *github.IssueCommentEvent, _ *gh.MockAPI,
+				_ *pullrequest.MockEventFilter, _ *pullrequest.MockCommandHandler) {
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "Should ignore edited comments",
+			event: commentEvent(github.String("edited"), "/pr-bot reevaluate", sampleID()),
+			setExpectations: func(_ id.PR, _ *github.IssueCommentEvent, _ *gh.MockAPI,
+				_ *pullrequest.MockEventFilter, _ *pullrequest.MockCommandHandler) {
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Should ignore comments on issues",
+			event: func() *github.IssueCommentEvent {
+				e := commentEvent(github.String("created"), "/pr-bot reevaluate", sampleID())
+				e.Issue.PullRequestLinks = nil
+				return e
+			}(),
+			setExpectations: func(_ id.PR, _ *github.IssueCommentEvent, _ *gh.MockAPI,
+				_ *pullrequest.MockEventFilter, _ *pullrequest.MockCommandHandler) {
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Should ignore comments from bots",
+			event: func() *github.IssueCommentEvent {
+				e := commentEvent(github.String("created"), "/pr-bot reevaluate", sampleID())
+				e.Comment.User.Type = github.String("Bot")
+				return e
+			}(),
+			setExpectations: func(_ id.PR, _ *github.IssueCommentEvent, _ *gh.MockAPI,
+				_ *pullrequest.MockEventFilter, _ *pullrequest.MockCommandHandler) {
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "Error when Event action is nil",
+			event: commentEvent(nil, "/pr-bot reevaluate", sampleID()),
+			setExpectations: func(_ id.PR, _ *github.IssueCommentEvent, _ *gh.MockAPI,
+				_ *pullrequest.MockEventFilter, _ *pullrequest.MockCommandHandler) {
+			},
+			wantErr: pe.InValidRequestError(ctx, "error parsing webhook event", pullrequest.ErrEventActionNotFound),
+		},
+		{
+			name: "Error when Event comment is nil",
+			event: func() *github.IssueCommentEvent {
+				e := commentEvent(github.String("created"), "", sampleID())
+				e.Comment = nil
+				return e
+			}(),
+			setExpectations: func(_ id.PR, _ *github.IssueCommentEvent, _ *gh.MockAPI,
+				_ *pullrequest.MockEventFilter, _ *pullrequest.MockCommandHandler) {
+			},
+			wantErr: pe.InValidRequestError(ctx, "error parsing webhook event", pullrequest.ErrCommentNotFound),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := pullrequest.NewMockEventHandler(t)
+			filter := pullrequest.NewMockEventFilter(t)
+			filter.EXPECT().AllowsVisibility(ctx, "public").Return(true, nil).Maybe()
+			commands := pullrequest.NewMockCommandHandler(t)
+			api := gh.NewMockAPI(t)
+			d := pullrequest.NewDispatcher(handler, filter, commands, api, metrics.NewNoopEmitter(), "pr-bot", 42, "pr-bot")
+
+			tt.setExpectations(sampleID(), tt.event, api, filter, commands)
+			err := d.DispatchComment(ctx, "123", pullrequest.EventNameComment, tt.event)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("dispatcher.DispatchComment() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func commentEvent(action *string, body string, id id.PR) *github.IssueCommentEvent {
+	return &github.IssueCommentEvent{
+		Action: action,
+		Issue: &github.Issue{
+			Number:           &id.Number,
+			PullRequestLinks: &github.PullRequestLinks{},
+		},
+		Comment: &github.IssueComment{
+			Body: github.String(body),
+			User: &github.User{
+				Login: github.String("user2"),
+				Type:  github.String("User"),
+			},
+		},
+		Repo: checkSuiteEvent(github.String("completed"), id).Repo,
+		Organization: &github.Organization{
+			Login: &id.Owner,
+		},
+	}
+}
+
+func checkSuiteEvent(action *string, id id.PR) *github.CheckSuiteEvent {
+	return &github.CheckSuiteEvent{
+		Action: action,
+		CheckSuite: &github.CheckSuite{
+			HeadSHA: github.String("sha1"),
+		},
+		Repo: &github.Repository{
+			Owner: &github.User{
+				Login: &id.Owner,
+			},
+			DefaultBranch: github.String("main"),
+			Name:          &id.Repo,
+			FullName:      &id.RepoFullName,
+			Visibility:    github.String("public"),
+		},
+		Org: &github.Organization{
+			Login: &id.Owner,
+		},
+	}
+}
+
+func listedPR(number int, state string, sha string) *github.PullRequest {
+	return &github.PullRequest{
+		Number: github.Int(number),
+		State:  github.String(state),
+		Head: &github.PullRequestBranch{
+			SHA: github.String(sha),
+		},
+	}
+}
+
+func repoID(id id.PR) id.PR {
+	return prID(id, 0)
+}
+
+func prID(pr id.PR, number int) id.PR {
+	return id.PR{
+		Owner:        pr.Owner,
+		Repo:         pr.Repo,
+		Number:       number,
+		RepoFullName: pr.RepoFullName,
+	}
+}
+
 func empty(e *github.PullRequestEvent) *github.PullRequestEvent {
 	e.PullRequest.ChangedFiles = github.Int(0)
 	return e
@@ -502,6 +1049,11 @@ func prReviewEvent(action *string, id id.PR) *github.PullRequestReviewEvent {
 	}
 }
 
+func sentBy(event *github.PullRequestEvent, login string) *github.PullRequestEvent {
+	event.Sender = &github.User{Login: github.String(login)}
+	return event
+}
+
 func prEvent(action *string, id id.PR) *github.PullRequestEvent {
 	url := fmt.Sprintf("%s/%s/%d", id.Owner, id.Repo, id.Number)
 	return &github.PullRequestEvent{
@@ -573,6 +1125,11 @@ func private(event *github.PullRequestEvent) *github.PullRequestEvent {
 	return event
 }
 
+func internal(event *github.PullRequestEvent) *github.PullRequestEvent {
+	event.Repo.Visibility = github.String("internal")
+	return event
+}
+
 func randomBranchAsDefault(event *github.PullRequestEvent) *github.PullRequestEvent {
 	event.Repo.DefaultBranch = github.String("random")
 	return event
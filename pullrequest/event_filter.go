@@ -3,6 +3,7 @@ package pullrequest
 import (
 	"context"
 	"regexp"
+	"strings"
 
 	"github.com/go-chi/httplog"
 	"github.com/marqeta/pr-bot/configstore"
@@ -14,15 +15,21 @@ import (
 //go:generate mockery --name EventFilter --testonly
 type EventFilter interface {
 	ShouldHandle(ctx context.Context, id id.PR) (bool, error)
+	AllowsVisibility(ctx context.Context, visibility string) (bool, error)
 }
 
+// DefaultVisibilities are allowed when the config does not list any visibilities.
+var DefaultVisibilities = []string{"public"}
+
 type RepoFilterCfg struct {
-	Allowlist       []string         `dynamodbav:"allowlist"`
-	Denylist        []string         `dynamodbav:"denylist"`
-	IgnoreTopics    []string         `dynamodbav:"ignore_topics"`
-	AllowlistRegex  []*regexp.Regexp `dynamodbav:"-"`
-	DenylistRegex   []*regexp.Regexp `dynamodbav:"-"`
-	IgnoreTopicsMap map[string]bool  `dynamodbav:"-"`
+	Allowlist           []string         `dynamodbav:"allowlist"`
+	Denylist            []string         `dynamodbav:"denylist"`
+	IgnoreTopics        []string         `dynamodbav:"ignore_topics"`
+	Visibilities        []string         `dynamodbav:"visibilities"`
+	AllowlistRegex      []*regexp.Regexp `dynamodbav:"-"`
+	DenylistRegex       []*regexp.Regexp `dynamodbav:"-"`
+	IgnoreTopicsMap     map[string]bool  `dynamodbav:"-"`
+	AllowedVisibilities map[string]bool  `dynamodbav:"-"`
 }
 
 // Update hook will be called everytime configstore reads an updated value.
@@ -54,6 +61,15 @@ func (cfg *RepoFilterCfg) Update() error {
 		cfg.IgnoreTopicsMap[topic] = true
 	}
 
+	visibilities := cfg.Visibilities
+	if len(visibilities) == 0 {
+		visibilities = DefaultVisibilities
+	}
+	cfg.AllowedVisibilities = make(map[string]bool)
+	for _, v := range visibilities {
+		cfg.AllowedVisibilities[strings.ToLower(v)] = true
+	}
+
 	return nil
 }
 
@@ -103,6 +119,18 @@ func (f *repoFilter) ShouldHandle(ctx context.Context, id id.PR) (bool, error) {
 	return false, nil
 }
 
+// AllowsVisibility implements EventFilter
+// visibility is one of public, private or internal.
+func (f *repoFilter) AllowsVisibility(ctx context.Context, visibility string) (bool, error) {
+	oplog := httplog.LogEntry(ctx)
+	cfg, err := f.cfgStore.Get()
+	if err != nil {
+		oplog.Err(err).Msgf("error while retrieveing repo filter cfg")
+		return false, err
+	}
+	return cfg.AllowedVisibilities[strings.ToLower(visibility)], nil
+}
+
 func (f *repoFilter) hasIgnoreTopic(ctx context.Context, id id.PR, cfg *RepoFilterCfg) (bool, error) {
 	topics, err := f.dao.ListAllTopics(ctx, id)
 	if err != nil {
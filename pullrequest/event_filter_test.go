@@ -73,6 +73,59 @@ func TestRepoFilterCfg_Update(t *testing.T) {
 	}
 }
 
+func Test_repoFilter_AllowsVisibility(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name         string
+		visibilities []string
+		visibility   string
+		want         bool
+	}{
+		{
+			name:         "should allow public repos by default",
+			visibilities: nil,
+			visibility:   "public",
+			want:         true,
+		},
+		{
+			name:         "should not allow internal repos by default",
+			visibilities: nil,
+			visibility:   "internal",
+			want:         false,
+		},
+		{
+			name:         "should allow configured visibilities",
+			visibilities: []string{"public", "Internal"},
+			visibility:   "internal",
+			want:         true,
+		},
+		{
+			name:         "should not allow visibilities which are not configured",
+			visibilities: []string{"internal"},
+			visibility:   "public",
+			want:         false,
+		},
+		{
+			name:         "should not allow empty visibility",
+			visibilities: []string{"public", "private", "internal"},
+			visibility:   "",
+			want:         false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := configstore.NewInMemoryStore(&pullrequest.RepoFilterCfg{
+				Visibilities: tt.visibilities,
+			})
+			assert.Nil(t, err)
+			f := pullrequest.NewRepoFilter(store, gh.NewMockAPI(t))
+			got, err := f.AllowsVisibility(ctx, tt.visibility)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func Test_repoNameFilter_ShouldHandle(t *testing.T) {
 	ctx := context.Background()
 	type fields struct {
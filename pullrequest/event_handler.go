@@ -2,6 +2,8 @@ package pullrequest
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/go-chi/httplog"
 	"github.com/google/go-github/v50/github"
@@ -9,12 +11,18 @@ import (
 	"github.com/marqeta/pr-bot/id"
 	"github.com/marqeta/pr-bot/metrics"
 	"github.com/marqeta/pr-bot/opa"
+	"github.com/marqeta/pr-bot/opa/evaluation"
 	"github.com/marqeta/pr-bot/opa/input"
 	"github.com/marqeta/pr-bot/opa/types"
 	"github.com/marqeta/pr-bot/pullrequest/review"
 	"github.com/shurcooL/githubv4"
 )
 
+const (
+	// ReconcileBody is the message of approvals dismissed when new commits do not satisfy the policies.
+	ReconcileBody = "New commits were pushed, which are not approved by the policies. Dismissing the approval of pr-bot."
+)
+
 //go:generate mockery --name EventHandler
 type EventHandler interface {
 	EvalAndReview(ctx context.Context, id id.PR, ghe input.GHE) error
@@ -25,14 +33,21 @@ type EventHandler interface {
 
 type eventHandler struct {
 	reviewer  review.Reviewer
+	labeler   Labeler
+	publisher Publisher
+	manager   evaluation.Manager
 	metrics   metrics.Emitter
 	evaluator opa.Evaluator
 	adapter   input.Adapter
 }
 
-func NewEventHandler(evaluator opa.Evaluator, reviewer review.Reviewer, metrics metrics.Emitter, adapter input.Adapter) EventHandler {
+func NewEventHandler(evaluator opa.Evaluator, reviewer review.Reviewer, labeler Labeler,
+	publisher Publisher, manager evaluation.Manager, metrics metrics.Emitter, adapter input.Adapter) EventHandler {
 	return &eventHandler{
 		reviewer:  reviewer,
+		labeler:   labeler,
+		publisher: publisher,
+		manager:   manager,
 		metrics:   metrics,
 		evaluator: evaluator,
 		adapter:   adapter,
@@ -69,16 +84,67 @@ func (eh *eventHandler) EvalAndReview(ctx context.Context, id id.PR, ghe input.G
 	tags := id.ToTags()
 	opaResult, err := eh.evaluator.Evaluate(ctx, ghe)
 	oplog.Err(err).Interface("decision", opaResult).Msg("opa evaluation complete")
+	// every evaluation is published, so that a check run can be required by branch protection
+	publishErr := eh.publisher.Publish(ctx, id, ghe, opaResult, err)
+	if publishErr != nil {
+		eh.metrics.EmitDist(ctx, "publisher.errors", 1.0, tags)
+	}
 	if err != nil {
 		eh.metrics.EmitDist(ctx, "opa.evaluator.errors", 1.0, tags)
-		return err
+		return errors.Join(err, publishErr)
 	}
 
 	if !opaResult.Track {
 		oplog.Info().Msg("track=false, skipping review")
+		return publishErr
+	}
+
+	// labels, reviewers and comments are applied even if the review fails
+	labelErr := eh.labeler.Apply(ctx, id, ghe.PullRequest.Labels, opaResult.Labels)
+	if labelErr != nil {
+		eh.metrics.EmitDist(ctx, "labeler.errors", 1.0, tags)
+	}
+	var reviewersErr error
+	if r := opaResult.Reviewers; r != nil && (len(r.Users) > 0 || len(r.Teams) > 0) {
+		reviewersErr = eh.reviewer.RequestReviewers(ctx, id, r.Users, r.Teams)
+	}
+	reconcileErr := eh.reconcile(ctx, id, ghe, opaResult)
+	reviewErr := eh.review(ctx, id, ghe, opaResult)
+	var annotateErr error
+	if len(opaResult.Comments) > 0 {
+		// comments are posted as a separate review, whatever the review is
+		annotateErr = eh.reviewer.Annotate(ctx, id, opaResult.Comments)
+	}
+	return errors.Join(reconcileErr, reviewErr, labelErr, reviewersErr, annotateErr, publishErr)
+}
+
+// reconcile dismisses approvals of pr-bot and disables auto merge enabled by pr-bot,
+// when new commits pushed to the PR are no longer approved by the policies.
+// the reconciliation is recorded in the report of the evaluation.
+func (eh *eventHandler) reconcile(ctx context.Context, id id.PR, ghe input.GHE, opaResult types.Result) error {
+	oplog := httplog.LogEntry(ctx)
+	if ghe.Action != "synchronize" || opaResult.Review.Type == types.Approve {
 		return nil
 	}
+	reconciliation, err := eh.reviewer.Reconcile(ctx, id, ReconcileBody, review.ReconcileOptions{
+		AutoMergeEnabledBy: ghe.PullRequest.GetAutoMerge().GetEnabledBy().GetLogin(),
+	})
+	if err != nil {
+		eh.metrics.EmitDist(ctx, "reconcile.errors", 1.0, id.ToTags())
+	}
+	if reconciliation.IsEmpty() {
+		return err
+	}
+	pr := fmt.Sprintf("%s/%d", ghe.Repository.GetFullName(), ghe.PullRequest.GetNumber())
+	recordErr := eh.manager.RecordReconciliation(ctx, pr, evaluation.GetDeliveryID(ctx), reconciliation)
+	if recordErr != nil {
+		oplog.Err(recordErr).Msgf("error recording reconciliation of PR %v", id.URL)
+	}
+	return errors.Join(err, recordErr)
+}
 
+func (eh *eventHandler) review(ctx context.Context, id id.PR, ghe input.GHE, opaResult types.Result) error {
+	oplog := httplog.LogEntry(ctx)
 	switch opaResult.Review.Type {
 
 	case types.Approve:
@@ -88,7 +154,8 @@ func (eh *eventHandler) EvalAndReview(ctx context.Context, id id.PR, ghe input.G
 		return eh.reviewer.Approve(ctx, id, opaResult.Review.Body, review.ApproveOptions{
 			AutoMergeEnabled: autoApprove,
 			DefaultBranch:    ghe.Repository.GetDefaultBranch(),
-			MergeMethod:      mergeMethod(ghe),
+			MergeMethod:      mergeMethod(ghe, opaResult.Merge),
+			ApproveOnly:      approveOnly(opaResult.Merge),
 		})
 	case types.RequestChanges:
 		return eh.reviewer.RequestChanges(ctx, id, opaResult.Review.Body)
@@ -102,15 +169,27 @@ func (eh *eventHandler) EvalAndReview(ctx context.Context, id id.PR, ghe input.G
 	return nil
 }
 
-func mergeMethod(ghe input.GHE) githubv4.PullRequestMergeMethod {
+// mergeMethod returns the merge method chosen by the policy when the repo allows it,
+// or guesses it from the repo settings when the policy did not choose one.
+func mergeMethod(ghe input.GHE, merge *types.Merge) githubv4.PullRequestMergeMethod {
 	rebase := ghe.PullRequest.GetBase().GetRepo().GetAllowRebaseMerge() || ghe.Repository.GetAllowRebaseMerge()
 	squash := ghe.PullRequest.GetBase().GetRepo().GetAllowSquashMerge() || ghe.Repository.GetAllowSquashMerge()
+	commit := ghe.PullRequest.GetBase().GetRepo().GetAllowMergeCommit() || ghe.Repository.GetAllowMergeCommit()
 	fc := ghe.PullRequest.GetChangedFiles()
-	// TODO: let policy specify what merge method to use.
 	// when rebasing empty commits on to main,
 	// no new commit is created, therefore no triggers would be fired.
 	// use squash to force a new commit to be created. when merging empty PRs
-	if rebase && fc > 0 {
+	canRebase := rebase && fc > 0
+	if merge != nil {
+		method := githubv4.PullRequestMergeMethod(merge.Method)
+		switch {
+		case method == githubv4.PullRequestMergeMethodRebase && canRebase,
+			method == githubv4.PullRequestMergeMethodSquash && squash,
+			method == githubv4.PullRequestMergeMethodMerge && commit:
+			return method
+		}
+	}
+	if canRebase {
 		return githubv4.PullRequestMergeMethodRebase
 	}
 	if squash {
@@ -119,3 +198,8 @@ func mergeMethod(ghe input.GHE) githubv4.PullRequestMergeMethod {
 	return githubv4.PullRequestMergeMethodMerge
 
 }
+
+// approveOnly returns true when the policy disabled auto merge, auto merge is enabled by default.
+func approveOnly(merge *types.Merge) bool {
+	return merge != nil && merge.AutoMerge != nil && !*merge.AutoMerge
+}
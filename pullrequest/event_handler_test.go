@@ -5,14 +5,17 @@ import (
 	"testing"
 
 	"github.com/google/go-github/v50/github"
+	gh "github.com/marqeta/pr-bot/github"
 	"github.com/marqeta/pr-bot/id"
 	"github.com/marqeta/pr-bot/metrics"
 	"github.com/marqeta/pr-bot/opa"
+	"github.com/marqeta/pr-bot/opa/evaluation"
 	"github.com/marqeta/pr-bot/opa/input"
 	"github.com/marqeta/pr-bot/opa/types"
 	"github.com/marqeta/pr-bot/pullrequest"
 	"github.com/marqeta/pr-bot/pullrequest/review"
 	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/mock"
 )
 
 func Test_eventHandlerV2_EvalAndReview(t *testing.T) {
@@ -52,6 +55,101 @@ func Test_eventHandlerV2_EvalAndReview(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Should approve PRs with merge method chosen by the policy",
+			args: args{
+				id:    sampleID(),
+				event: squash(rebase(prEvent(github.String("opened"), sampleID()))),
+				setExpectaions: func(e *opa.MockEvaluator, r *review.MockReviewer, p *github.PullRequestEvent) {
+					p.GetRepo().AllowAutoMerge = github.Bool(true)
+
+					e.EXPECT().Evaluate(ctx, ToGHE(p)).Return(types.Result{
+						Track: true,
+						Review: types.Review{
+							Type: types.Approve,
+							Body: "LGTM",
+						},
+						Merge: &types.Merge{Method: types.MergeMethodSquash},
+					}, nil)
+					r.EXPECT().Approve(ctx, sampleID(), "LGTM", review.ApproveOptions{
+						AutoMergeEnabled: true,
+						DefaultBranch:    "main",
+						MergeMethod:      githubv4.PullRequestMergeMethodSquash,
+					}).Return(nil)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should approve PRs with merge method of the repo when the repo does not allow the policy method",
+			args: args{
+				id:    sampleID(),
+				event: rebase(prEvent(github.String("opened"), sampleID())),
+				setExpectaions: func(e *opa.MockEvaluator, r *review.MockReviewer, p *github.PullRequestEvent) {
+					e.EXPECT().Evaluate(ctx, ToGHE(p)).Return(types.Result{
+						Track: true,
+						Review: types.Review{
+							Type: types.Approve,
+							Body: "LGTM",
+						},
+						Merge: &types.Merge{Method: types.MergeMethodSquash},
+					}, nil)
+					r.EXPECT().Approve(ctx, sampleID(), "LGTM", review.ApproveOptions{
+						AutoMergeEnabled: true,
+						DefaultBranch:    "main",
+						MergeMethod:      githubv4.PullRequestMergeMethodRebase,
+					}).Return(nil)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should not rebase empty PRs when the policy chooses rebase",
+			args: args{
+				id:    sampleID(),
+				event: empty(allMergeMethods(prEvent(github.String("opened"), sampleID()))),
+				setExpectaions: func(e *opa.MockEvaluator, r *review.MockReviewer, p *github.PullRequestEvent) {
+					e.EXPECT().Evaluate(ctx, ToGHE(p)).Return(types.Result{
+						Track: true,
+						Review: types.Review{
+							Type: types.Approve,
+							Body: "LGTM",
+						},
+						Merge: &types.Merge{Method: types.MergeMethodRebase},
+					}, nil)
+					r.EXPECT().Approve(ctx, sampleID(), "LGTM", review.ApproveOptions{
+						AutoMergeEnabled: true,
+						DefaultBranch:    "main",
+						MergeMethod:      githubv4.PullRequestMergeMethodSquash,
+					}).Return(nil)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should only approve PRs when policy disables auto merge",
+			args: args{
+				id:    sampleID(),
+				event: prEvent(github.String("opened"), sampleID()),
+				setExpectaions: func(e *opa.MockEvaluator, r *review.MockReviewer, p *github.PullRequestEvent) {
+					e.EXPECT().Evaluate(ctx, ToGHE(p)).Return(types.Result{
+						Track: true,
+						Review: types.Review{
+							Type: types.Approve,
+							Body: "LGTM",
+						},
+						Merge: &types.Merge{AutoMerge: github.Bool(false)},
+					}, nil)
+					r.EXPECT().Approve(ctx, sampleID(), "LGTM", review.ApproveOptions{
+						AutoMergeEnabled: false,
+						DefaultBranch:    "main",
+						MergeMethod:      githubv4.PullRequestMergeMethodMerge,
+						ApproveOnly:      true,
+					}).Return(nil)
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "Should approve PRs with auto merge disabled",
 			args: args{
@@ -341,7 +439,9 @@ func Test_eventHandlerV2_EvalAndReview(t *testing.T) {
 			r := review.NewMockReviewer(t)
 			m := metrics.NewNoopEmitter()
 			a := input.NewMockAdapter(t)
-			eh := pullrequest.NewEventHandler(e, r, m, a)
+			// none of the results have labels
+			l := pullrequest.NewLabeler(gh.NewMockAPI(t), m)
+			eh := pullrequest.NewEventHandler(e, r, l, pullrequest.NewNoopPublisher(), evaluation.NewMockManager(t), m, a)
 			tt.args.setExpectaions(e, r, tt.args.event)
 			ghe := ToGHE(tt.args.event)
 			if err := eh.EvalAndReview(ctx, tt.args.id, ghe); (err != nil) != tt.wantErr {
@@ -351,6 +451,150 @@ func Test_eventHandlerV2_EvalAndReview(t *testing.T) {
 	}
 }
 
+func Test_eventHandler_EvalAndReview_LabelsReviewersAndComments(t *testing.T) {
+	ctx := context.TODO()
+	labels := &types.Labels{Add: []string{"auto-approved"}}
+	tests := []struct {
+		name            string
+		result          types.Result
+		setExpectations func(r *review.MockReviewer, l *pullrequest.MockLabeler)
+		wantErr         bool
+	}{
+		{
+			name: "Should apply labels and review",
+			result: types.Result{
+				Track:  true,
+				Review: types.Review{Type: types.Comment, Body: "nit"},
+				Labels: labels,
+			},
+			setExpectations: func(r *review.MockReviewer, l *pullrequest.MockLabeler) {
+				l.EXPECT().Apply(ctx, sampleID(), []*github.Label{{Name: github.String("size/XL")}}, labels).Return(nil)
+				r.EXPECT().Comment(ctx, sampleID(), "nit").Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should apply labels when review is skipped",
+			result: types.Result{
+				Track:  true,
+				Review: types.Review{Type: types.Skip},
+				Labels: labels,
+			},
+			setExpectations: func(_ *review.MockReviewer, l *pullrequest.MockLabeler) {
+				l.EXPECT().Apply(ctx, sampleID(), []*github.Label{{Name: github.String("size/XL")}}, labels).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should review and return error when labels cannot be applied",
+			result: types.Result{
+				Track:  true,
+				Review: types.Review{Type: types.Comment, Body: "nit"},
+				Labels: labels,
+			},
+			setExpectations: func(r *review.MockReviewer, l *pullrequest.MockLabeler) {
+				l.EXPECT().Apply(ctx, sampleID(), []*github.Label{{Name: github.String("size/XL")}}, labels).Return(errRandom)
+				r.EXPECT().Comment(ctx, sampleID(), "nit").Return(nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should request reviewers and review",
+			result: types.Result{
+				Track:     true,
+				Review:    types.Review{Type: types.Comment, Body: "nit"},
+				Reviewers: &types.Reviewers{Users: []string{"alice"}, Teams: []string{"infra"}},
+			},
+			setExpectations: func(r *review.MockReviewer, l *pullrequest.MockLabeler) {
+				l.EXPECT().Apply(ctx, sampleID(), []*github.Label{{Name: github.String("size/XL")}}, (*types.Labels)(nil)).Return(nil)
+				r.EXPECT().RequestReviewers(ctx, sampleID(), []string{"alice"}, []string{"infra"}).Return(nil)
+				r.EXPECT().Comment(ctx, sampleID(), "nit").Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should not request reviewers when decision has none",
+			result: types.Result{
+				Track:     true,
+				Review:    types.Review{Type: types.Skip},
+				Reviewers: &types.Reviewers{},
+			},
+			setExpectations: func(_ *review.MockReviewer, l *pullrequest.MockLabeler) {
+				l.EXPECT().Apply(ctx, sampleID(), []*github.Label{{Name: github.String("size/XL")}}, (*types.Labels)(nil)).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should review and return error when reviewers cannot be requested",
+			result: types.Result{
+				Track:     true,
+				Review:    types.Review{Type: types.Comment, Body: "nit"},
+				Reviewers: &types.Reviewers{Teams: []string{"infra"}},
+			},
+			setExpectations: func(r *review.MockReviewer, l *pullrequest.MockLabeler) {
+				l.EXPECT().Apply(ctx, sampleID(), []*github.Label{{Name: github.String("size/XL")}}, (*types.Labels)(nil)).Return(nil)
+				r.EXPECT().RequestReviewers(ctx, sampleID(), []string(nil), []string{"infra"}).Return(errRandom)
+				r.EXPECT().Comment(ctx, sampleID(), "nit").Return(nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should post comments and review",
+			result: types.Result{
+				Track:    true,
+				Review:   types.Review{Type: types.Skip},
+				Comments: []types.InlineComment{{Path: "main.tf", Line: 1, Side: types.Right, Severity: types.Notice, Message: "m"}},
+			},
+			setExpectations: func(r *review.MockReviewer, l *pullrequest.MockLabeler) {
+				l.EXPECT().Apply(ctx, sampleID(), []*github.Label{{Name: github.String("size/XL")}}, (*types.Labels)(nil)).Return(nil)
+				r.EXPECT().Annotate(ctx, sampleID(),
+					[]types.InlineComment{{Path: "main.tf", Line: 1, Side: types.Right, Severity: types.Notice, Message: "m"}}).
+					Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should review and return error when comments cannot be posted",
+			result: types.Result{
+				Track:    true,
+				Review:   types.Review{Type: types.Comment, Body: "nit"},
+				Comments: []types.InlineComment{{Path: "main.tf", Line: 1, Side: types.Right, Severity: types.Notice, Message: "m"}},
+			},
+			setExpectations: func(r *review.MockReviewer, l *pullrequest.MockLabeler) {
+				l.EXPECT().Apply(ctx, sampleID(), []*github.Label{{Name: github.String("size/XL")}}, (*types.Labels)(nil)).Return(nil)
+				r.EXPECT().Comment(ctx, sampleID(), "nit").Return(nil)
+				r.EXPECT().Annotate(ctx, sampleID(),
+					[]types.InlineComment{{Path: "main.tf", Line: 1, Side: types.Right, Severity: types.Notice, Message: "m"}}).
+					Return(errRandom)
+			},
+			wantErr: true,
+		},
+		{
+			name:            "Should not apply labels when track is false",
+			result:          types.Result{Labels: labels},
+			setExpectations: func(_ *review.MockReviewer, _ *pullrequest.MockLabeler) {},
+			wantErr:         false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := opa.NewMockEvaluator(t)
+			r := review.NewMockReviewer(t)
+			l := pullrequest.NewMockLabeler(t)
+			eh := pullrequest.NewEventHandler(e, r, l, pullrequest.NewNoopPublisher(), evaluation.NewMockManager(t),
+				metrics.NewNoopEmitter(), input.NewMockAdapter(t))
+			event := prEvent(github.String("labeled"), sampleID())
+			event.PullRequest.Labels = []*github.Label{{Name: github.String("size/XL")}}
+			ghe := ToGHE(event)
+			e.EXPECT().Evaluate(ctx, ghe).Return(tt.result, nil)
+			tt.setExpectations(r, l)
+			if err := eh.EvalAndReview(ctx, sampleID(), ghe); (err != nil) != tt.wantErr {
+				t.Errorf("eventHandler.EvalAndReview() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func ToGHE(event *github.PullRequestEvent) input.GHE {
 	return input.GHE{
 		Event:        "pull_request",
@@ -360,3 +604,179 @@ func ToGHE(event *github.PullRequestEvent) input.GHE {
 		Organization: event.GetOrganization(),
 	}
 }
+
+func Test_eventHandler_EvalAndReview_Publish(t *testing.T) {
+	ctx := context.TODO()
+	event := prEvent(github.String("synchronize"), sampleID())
+	ghe := ToGHE(event)
+	tests := []struct {
+		name            string
+		result          types.Result
+		evalErr         error
+		setExpectations func(r *review.MockReviewer, l *pullrequest.MockLabeler, c *pullrequest.MockPublisher)
+		wantErr         bool
+	}{
+		{
+			name:   "Should publish result and review",
+			result: types.Result{Track: true, Review: types.Review{Type: types.Comment, Body: "nit"}},
+			setExpectations: func(r *review.MockReviewer, l *pullrequest.MockLabeler, c *pullrequest.MockPublisher) {
+				c.EXPECT().Publish(ctx, sampleID(), ghe,
+					types.Result{Track: true, Review: types.Review{Type: types.Comment, Body: "nit"}}, nil).Return(nil)
+				l.EXPECT().Apply(ctx, sampleID(), []*github.Label(nil), (*types.Labels)(nil)).Return(nil)
+				r.EXPECT().Reconcile(ctx, sampleID(), pullrequest.ReconcileBody, review.ReconcileOptions{}).
+					Return(evaluation.Reconciliation{}, nil)
+				r.EXPECT().Comment(ctx, sampleID(), "nit").Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:   "Should publish result when track is false",
+			result: types.Result{},
+			setExpectations: func(_ *review.MockReviewer, _ *pullrequest.MockLabeler, c *pullrequest.MockPublisher) {
+				c.EXPECT().Publish(ctx, sampleID(), ghe, types.Result{}, nil).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:    "Should publish error when evaluation fails",
+			evalErr: errRandom,
+			setExpectations: func(_ *review.MockReviewer, _ *pullrequest.MockLabeler, c *pullrequest.MockPublisher) {
+				c.EXPECT().Publish(ctx, sampleID(), ghe, types.Result{}, errRandom).Return(nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:   "Should review and return error when result cannot be published",
+			result: types.Result{Track: true, Review: types.Review{Type: types.Comment, Body: "nit"}},
+			setExpectations: func(r *review.MockReviewer, l *pullrequest.MockLabeler, c *pullrequest.MockPublisher) {
+				c.EXPECT().Publish(ctx, sampleID(), ghe,
+					types.Result{Track: true, Review: types.Review{Type: types.Comment, Body: "nit"}}, nil).Return(errRandom)
+				l.EXPECT().Apply(ctx, sampleID(), []*github.Label(nil), (*types.Labels)(nil)).Return(nil)
+				r.EXPECT().Reconcile(ctx, sampleID(), pullrequest.ReconcileBody, review.ReconcileOptions{}).
+					Return(evaluation.Reconciliation{}, nil)
+				r.EXPECT().Comment(ctx, sampleID(), "nit").Return(nil)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := opa.NewMockEvaluator(t)
+			r := review.NewMockReviewer(t)
+			l := pullrequest.NewMockLabeler(t)
+			c := pullrequest.NewMockPublisher(t)
+			eh := pullrequest.NewEventHandler(e, r, l, c, evaluation.NewMockManager(t),
+				metrics.NewNoopEmitter(), input.NewMockAdapter(t))
+			e.EXPECT().Evaluate(ctx, ghe).Return(tt.result, tt.evalErr)
+			tt.setExpectations(r, l, c)
+			if err := eh.EvalAndReview(ctx, sampleID(), ghe); (err != nil) != tt.wantErr {
+				t.Errorf("eventHandler.EvalAndReview() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_eventHandler_EvalAndReview_Reconcile(t *testing.T) {
+	ctx := evaluation.SetDeliveryID(context.TODO(), "delivery1")
+	changes := types.Result{Track: true, Review: types.Review{Type: types.RequestChanges, Body: "no"}}
+	reconciliation := evaluation.Reconciliation{DismissedReviews: []int64{1}, AutoMergeDisabled: true}
+	autoMerge := &github.PullRequestAutoMerge{EnabledBy: &github.User{Login: github.String("pr-bot")}}
+	tests := []struct {
+		name            string
+		action          string
+		result          types.Result
+		setExpectations func(r *review.MockReviewer, m *evaluation.MockManager)
+		wantErr         bool
+	}{
+		{
+			name:   "Should reconcile and record reconciliation when new commits are not approved",
+			action: "synchronize",
+			result: changes,
+			setExpectations: func(r *review.MockReviewer, m *evaluation.MockManager) {
+				r.EXPECT().Reconcile(ctx, sampleID(), pullrequest.ReconcileBody,
+					review.ReconcileOptions{AutoMergeEnabledBy: "pr-bot"}).Return(reconciliation, nil)
+				m.EXPECT().RecordReconciliation(ctx, "owner1/repo1/1", "delivery1", reconciliation).Return(nil)
+				r.EXPECT().RequestChanges(ctx, sampleID(), "no").Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:   "Should not record reconciliation when nothing was reconciled",
+			action: "synchronize",
+			result: changes,
+			setExpectations: func(r *review.MockReviewer, _ *evaluation.MockManager) {
+				r.EXPECT().Reconcile(ctx, sampleID(), pullrequest.ReconcileBody,
+					review.ReconcileOptions{AutoMergeEnabledBy: "pr-bot"}).Return(evaluation.Reconciliation{}, nil)
+				r.EXPECT().RequestChanges(ctx, sampleID(), "no").Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:   "Should record partial reconciliation and return error when reconcile fails",
+			action: "synchronize",
+			result: changes,
+			setExpectations: func(r *review.MockReviewer, m *evaluation.MockManager) {
+				partial := evaluation.Reconciliation{DismissedReviews: []int64{1}}
+				r.EXPECT().Reconcile(ctx, sampleID(), pullrequest.ReconcileBody,
+					review.ReconcileOptions{AutoMergeEnabledBy: "pr-bot"}).Return(partial, errRandom)
+				m.EXPECT().RecordReconciliation(ctx, "owner1/repo1/1", "delivery1", partial).Return(nil)
+				r.EXPECT().RequestChanges(ctx, sampleID(), "no").Return(nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:   "Should return error when reconciliation cannot be recorded",
+			action: "synchronize",
+			result: changes,
+			setExpectations: func(r *review.MockReviewer, m *evaluation.MockManager) {
+				r.EXPECT().Reconcile(ctx, sampleID(), pullrequest.ReconcileBody,
+					review.ReconcileOptions{AutoMergeEnabledBy: "pr-bot"}).Return(reconciliation, nil)
+				m.EXPECT().RecordReconciliation(ctx, "owner1/repo1/1", "delivery1", reconciliation).
+					Return(evaluation.ErrReportNotFound)
+				r.EXPECT().RequestChanges(ctx, sampleID(), "no").Return(nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:   "Should not reconcile when new commits are approved",
+			action: "synchronize",
+			result: types.Result{
+				Track:  true,
+				Review: types.Review{Type: types.Approve, Body: "LGTM"},
+				Merge:  &types.Merge{AutoMerge: github.Bool(false)},
+			},
+			setExpectations: func(r *review.MockReviewer, _ *evaluation.MockManager) {
+				r.EXPECT().Approve(ctx, sampleID(), "LGTM", mock.Anything).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:   "Should not reconcile on other actions",
+			action: "edited",
+			result: changes,
+			setExpectations: func(r *review.MockReviewer, _ *evaluation.MockManager) {
+				r.EXPECT().RequestChanges(ctx, sampleID(), "no").Return(nil)
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := opa.NewMockEvaluator(t)
+			r := review.NewMockReviewer(t)
+			l := pullrequest.NewMockLabeler(t)
+			m := evaluation.NewMockManager(t)
+			eh := pullrequest.NewEventHandler(e, r, l, pullrequest.NewNoopPublisher(), m,
+				metrics.NewNoopEmitter(), input.NewMockAdapter(t))
+			event := prEvent(github.String(tt.action), sampleID())
+			event.PullRequest.AutoMerge = autoMerge
+			ghe := ToGHE(event)
+			e.EXPECT().Evaluate(ctx, ghe).Return(tt.result, nil)
+			l.EXPECT().Apply(ctx, sampleID(), []*github.Label(nil), (*types.Labels)(nil)).Return(nil)
+			tt.setExpectations(r, m)
+			if err := eh.EvalAndReview(ctx, sampleID(), ghe); (err != nil) != tt.wantErr {
+				t.Errorf("eventHandler.EvalAndReview() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package pullrequest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/httplog"
+	"github.com/google/go-github/v50/github"
+	gh "github.com/marqeta/pr-bot/github"
+	"github.com/marqeta/pr-bot/id"
+	"github.com/marqeta/pr-bot/metrics"
+	"github.com/marqeta/pr-bot/opa/types"
+)
+
+// Labeler applies the labels of a policy decision to a PR.
+//
+//go:generate mockery --name Labeler
+type Labeler interface {
+	// Apply adds and removes labels, labels already on the PR are not added again
+	// and labels which are not on the PR are not removed.
+	Apply(ctx context.Context, id id.PR, current []*github.Label, labels *types.Labels) error
+}
+
+type labeler struct {
+	api     gh.API
+	metrics metrics.Emitter
+}
+
+func NewLabeler(api gh.API, m metrics.Emitter) Labeler {
+	return &labeler{
+		api:     api,
+		metrics: m,
+	}
+}
+
+// Apply implements Labeler.
+func (l *labeler) Apply(ctx context.Context, id id.PR, current []*github.Label, labels *types.Labels) error {
+	oplog := httplog.LogEntry(ctx)
+	if labels == nil {
+		return nil
+	}
+	add := make([]string, 0, len(labels.Add))
+	for _, label := range labels.Add {
+		if !hasLabel(current, label) && !containsFold(add, label) {
+			add = append(add, label)
+		}
+	}
+	remove := make([]string, 0, len(labels.Remove))
+	for _, label := range labels.Remove {
+		if hasLabel(current, label) && !containsFold(remove, label) {
+			remove = append(remove, label)
+		}
+	}
+
+	errs := make([]error, 0)
+	if len(add) > 0 {
+		if err := l.api.AddLabels(ctx, id, add); err != nil {
+			oplog.Err(err).Msgf("error adding labels %v to PR", add)
+			errs = append(errs, err)
+		} else {
+			oplog.Info().Msgf("added labels %v to PR", add)
+			l.emit(ctx, id, "add", len(add))
+		}
+	}
+	for _, label := range remove {
+		if err := l.api.RemoveLabel(ctx, id, label); err != nil {
+			oplog.Err(err).Msgf("error removing label %v from PR", label)
+			errs = append(errs, err)
+			continue
+		}
+		oplog.Info().Msgf("removed label %v from PR", label)
+		l.emit(ctx, id, "remove", 1)
+	}
+	return errors.Join(errs...)
+}
+
+func (l *labeler) emit(ctx context.Context, id id.PR, op string, count int) {
+	tags := append(id.ToTags(), fmt.Sprintf("op:%s", op))
+	l.metrics.EmitDist(ctx, "labeledPRs", float64(count), tags)
+}
+
+// GitHub label names are case insensitive.
+func hasLabel(labels []*github.Label, name string) bool {
+	return slices.ContainsFunc(labels, func(l *github.Label) bool {
+		return strings.EqualFold(l.GetName(), name)
+	})
+}
+
+func containsFold(s []string, v string) bool {
+	return slices.ContainsFunc(s, func(e string) bool {
+		return strings.EqualFold(e, v)
+	})
+}
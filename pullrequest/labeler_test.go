@@ -0,0 +1,95 @@
+package pullrequest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-github/v50/github"
+	gh "github.com/marqeta/pr-bot/github"
+	"github.com/marqeta/pr-bot/metrics"
+	"github.com/marqeta/pr-bot/opa/types"
+	"github.com/marqeta/pr-bot/pullrequest"
+)
+
+func Test_labeler_Apply(t *testing.T) {
+	ctx := context.TODO()
+	current := []*github.Label{
+		{Name: github.String("size/XL")},
+		{Name: github.String("needs-review")},
+	}
+	tests := []struct {
+		name            string
+		labels          *types.Labels
+		setExpectations func(api *gh.MockAPI)
+		wantErr         bool
+	}{
+		{
+			name:            "Should do nothing without labels",
+			labels:          nil,
+			setExpectations: func(_ *gh.MockAPI) {},
+			wantErr:         false,
+		},
+		{
+			name: "Should add labels which are not on the PR",
+			labels: &types.Labels{
+				Add: []string{"SIZE/XL", "auto-approved", "Auto-Approved"},
+			},
+			setExpectations: func(api *gh.MockAPI) {
+				api.EXPECT().AddLabels(ctx, sampleID(), []string{"auto-approved"}).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should remove labels which are on the PR",
+			labels: &types.Labels{
+				Remove: []string{"needs-review", "needs-security-review"},
+			},
+			setExpectations: func(api *gh.MockAPI) {
+				api.EXPECT().RemoveLabel(ctx, sampleID(), "needs-review").Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should not call GitHub when labels are already applied",
+			labels: &types.Labels{
+				Add:    []string{"size/XL"},
+				Remove: []string{"auto-approved"},
+			},
+			setExpectations: func(_ *gh.MockAPI) {},
+			wantErr:         false,
+		},
+		{
+			name: "Should remove labels when adding labels fails",
+			labels: &types.Labels{
+				Add:    []string{"auto-approved"},
+				Remove: []string{"needs-review"},
+			},
+			setExpectations: func(api *gh.MockAPI) {
+				api.EXPECT().AddLabels(ctx, sampleID(), []string{"auto-approved"}).Return(errRandom)
+				api.EXPECT().RemoveLabel(ctx, sampleID(), "needs-review").Return(nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should return error when removing labels fails",
+			labels: &types.Labels{
+				Remove: []string{"needs-review", "size/XL"},
+			},
+			setExpectations: func(api *gh.MockAPI) {
+				api.EXPECT().RemoveLabel(ctx, sampleID(), "needs-review").Return(errRandom)
+				api.EXPECT().RemoveLabel(ctx, sampleID(), "size/XL").Return(nil)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := gh.NewMockAPI(t)
+			tt.setExpectations(api)
+			l := pullrequest.NewLabeler(api, metrics.NewNoopEmitter())
+			if err := l.Apply(ctx, sampleID(), current, tt.labels); (err != nil) != tt.wantErr {
+				t.Errorf("labeler.Apply() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
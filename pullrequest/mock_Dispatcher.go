@@ -40,16 +40,47 @@ func (_m *MockDispatcher) Dispatch(ctx context.Context, deliveryID string, event
 	return r0
 }
 
-// D provides a mock function with given fields: ctx, deliveryID, eventName, event
-func (_m *MockDispatcher) DispatchReview(ctx context.Context, deliveryID string, eventName string, event *github.PullRequestReviewEvent) error {
+// MockDispatcher_Dispatch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Dispatch'
+type MockDispatcher_Dispatch_Call struct {
+	*mock.Call
+}
+
+// Dispatch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - deliveryID string
+//   - eventName string
+//   - event *github.PullRequestEvent
+func (_e *MockDispatcher_Expecter) Dispatch(ctx interface{}, deliveryID interface{}, eventName interface{}, event interface{}) *MockDispatcher_Dispatch_Call {
+	return &MockDispatcher_Dispatch_Call{Call: _e.mock.On("Dispatch", ctx, deliveryID, eventName, event)}
+}
+
+func (_c *MockDispatcher_Dispatch_Call) Run(run func(ctx context.Context, deliveryID string, eventName string, event *github.PullRequestEvent)) *MockDispatcher_Dispatch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(*github.PullRequestEvent))
+	})
+	return _c
+}
+
+func (_c *MockDispatcher_Dispatch_Call) Return(_a0 error) *MockDispatcher_Dispatch_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockDispatcher_Dispatch_Call) RunAndReturn(run func(context.Context, string, string, *github.PullRequestEvent) error) *MockDispatcher_Dispatch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DispatchCheckRun provides a mock function with given fields: ctx, deliveryID, eventName, event
+func (_m *MockDispatcher) DispatchCheckRun(ctx context.Context, deliveryID string, eventName string, event *github.CheckRunEvent) error {
 	ret := _m.Called(ctx, deliveryID, eventName, event)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Dispatch")
+		panic("no return value specified for DispatchCheckRun")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, *github.PullRequestReviewEvent) error); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *github.CheckRunEvent) error); ok {
 		r0 = rf(ctx, deliveryID, eventName, event)
 	} else {
 		r0 = ret.Error(0)
@@ -58,23 +89,156 @@ func (_m *MockDispatcher) DispatchReview(ctx context.Context, deliveryID string,
 	return r0
 }
 
-// MockDispatcher_Dispatch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Dispatch'
-type MockDispatcher_Dispatch_Call struct {
+// MockDispatcher_DispatchCheckRun_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DispatchCheckRun'
+type MockDispatcher_DispatchCheckRun_Call struct {
 	*mock.Call
 }
 
-// MockDispatcher_DispatchReview_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DispatchReview'
-type MockDispatcher_DispatchReview_Call struct {
+// DispatchCheckRun is a helper method to define mock.On call
+//   - ctx context.Context
+//   - deliveryID string
+//   - eventName string
+//   - event *github.CheckRunEvent
+func (_e *MockDispatcher_Expecter) DispatchCheckRun(ctx interface{}, deliveryID interface{}, eventName interface{}, event interface{}) *MockDispatcher_DispatchCheckRun_Call {
+	return &MockDispatcher_DispatchCheckRun_Call{Call: _e.mock.On("DispatchCheckRun", ctx, deliveryID, eventName, event)}
+}
+
+func (_c *MockDispatcher_DispatchCheckRun_Call) Run(run func(ctx context.Context, deliveryID string, eventName string, event *github.CheckRunEvent)) *MockDispatcher_DispatchCheckRun_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(*github.CheckRunEvent))
+	})
+	return _c
+}
+
+func (_c *MockDispatcher_DispatchCheckRun_Call) Return(_a0 error) *MockDispatcher_DispatchCheckRun_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockDispatcher_DispatchCheckRun_Call) RunAndReturn(run func(context.Context, string, string, *github.CheckRunEvent) error) *MockDispatcher_DispatchCheckRun_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DispatchCheckSuite provides a mock function with given fields: ctx, deliveryID, eventName, event
+func (_m *MockDispatcher) DispatchCheckSuite(ctx context.Context, deliveryID string, eventName string, event *github.CheckSuiteEvent) error {
+	ret := _m.Called(ctx, deliveryID, eventName, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DispatchCheckSuite")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *github.CheckSuiteEvent) error); ok {
+		r0 = rf(ctx, deliveryID, eventName, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockDispatcher_DispatchCheckSuite_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DispatchCheckSuite'
+type MockDispatcher_DispatchCheckSuite_Call struct {
 	*mock.Call
 }
 
-// Dispatch is a helper method to define mock.On call
+// DispatchCheckSuite is a helper method to define mock.On call
 //   - ctx context.Context
 //   - deliveryID string
 //   - eventName string
-//   - event *github.PullRequestEvent
-func (_e *MockDispatcher_Expecter) Dispatch(ctx interface{}, deliveryID interface{}, eventName interface{}, event interface{}) *MockDispatcher_Dispatch_Call {
-	return &MockDispatcher_Dispatch_Call{Call: _e.mock.On("Dispatch", ctx, deliveryID, eventName, event)}
+//   - event *github.CheckSuiteEvent
+func (_e *MockDispatcher_Expecter) DispatchCheckSuite(ctx interface{}, deliveryID interface{}, eventName interface{}, event interface{}) *MockDispatcher_DispatchCheckSuite_Call {
+	return &MockDispatcher_DispatchCheckSuite_Call{Call: _e.mock.On("DispatchCheckSuite", ctx, deliveryID, eventName, event)}
+}
+
+func (_c *MockDispatcher_DispatchCheckSuite_Call) Run(run func(ctx context.Context, deliveryID string, eventName string, event *github.CheckSuiteEvent)) *MockDispatcher_DispatchCheckSuite_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(*github.CheckSuiteEvent))
+	})
+	return _c
+}
+
+func (_c *MockDispatcher_DispatchCheckSuite_Call) Return(_a0 error) *MockDispatcher_DispatchCheckSuite_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockDispatcher_DispatchCheckSuite_Call) RunAndReturn(run func(context.Context, string, string, *github.CheckSuiteEvent) error) *MockDispatcher_DispatchCheckSuite_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DispatchComment provides a mock function with given fields: ctx, deliveryID, eventName, event
+func (_m *MockDispatcher) DispatchComment(ctx context.Context, deliveryID string, eventName string, event *github.IssueCommentEvent) error {
+	ret := _m.Called(ctx, deliveryID, eventName, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DispatchComment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *github.IssueCommentEvent) error); ok {
+		r0 = rf(ctx, deliveryID, eventName, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockDispatcher_DispatchComment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DispatchComment'
+type MockDispatcher_DispatchComment_Call struct {
+	*mock.Call
+}
+
+// DispatchComment is a helper method to define mock.On call
+//   - ctx context.Context
+//   - deliveryID string
+//   - eventName string
+//   - event *github.IssueCommentEvent
+func (_e *MockDispatcher_Expecter) DispatchComment(ctx interface{}, deliveryID interface{}, eventName interface{}, event interface{}) *MockDispatcher_DispatchComment_Call {
+	return &MockDispatcher_DispatchComment_Call{Call: _e.mock.On("DispatchComment", ctx, deliveryID, eventName, event)}
+}
+
+func (_c *MockDispatcher_DispatchComment_Call) Run(run func(ctx context.Context, deliveryID string, eventName string, event *github.IssueCommentEvent)) *MockDispatcher_DispatchComment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(*github.IssueCommentEvent))
+	})
+	return _c
+}
+
+func (_c *MockDispatcher_DispatchComment_Call) Return(_a0 error) *MockDispatcher_DispatchComment_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockDispatcher_DispatchComment_Call) RunAndReturn(run func(context.Context, string, string, *github.IssueCommentEvent) error) *MockDispatcher_DispatchComment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DispatchReview provides a mock function with given fields: ctx, deliveryID, eventName, event
+func (_m *MockDispatcher) DispatchReview(ctx context.Context, deliveryID string, eventName string, event *github.PullRequestReviewEvent) error {
+	ret := _m.Called(ctx, deliveryID, eventName, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DispatchReview")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *github.PullRequestReviewEvent) error); ok {
+		r0 = rf(ctx, deliveryID, eventName, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockDispatcher_DispatchReview_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DispatchReview'
+type MockDispatcher_DispatchReview_Call struct {
+	*mock.Call
 }
 
 // DispatchReview is a helper method to define mock.On call
@@ -103,19 +267,51 @@ func (_c *MockDispatcher_DispatchReview_Call) RunAndReturn(run func(context.Cont
 	return _c
 }
 
-func (_c *MockDispatcher_Dispatch_Call) Run(run func(ctx context.Context, deliveryID string, eventName string, event *github.PullRequestEvent)) *MockDispatcher_Dispatch_Call {
+// DispatchStatus provides a mock function with given fields: ctx, deliveryID, eventName, event
+func (_m *MockDispatcher) DispatchStatus(ctx context.Context, deliveryID string, eventName string, event *github.StatusEvent) error {
+	ret := _m.Called(ctx, deliveryID, eventName, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DispatchStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *github.StatusEvent) error); ok {
+		r0 = rf(ctx, deliveryID, eventName, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockDispatcher_DispatchStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DispatchStatus'
+type MockDispatcher_DispatchStatus_Call struct {
+	*mock.Call
+}
+
+// DispatchStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - deliveryID string
+//   - eventName string
+//   - event *github.StatusEvent
+func (_e *MockDispatcher_Expecter) DispatchStatus(ctx interface{}, deliveryID interface{}, eventName interface{}, event interface{}) *MockDispatcher_DispatchStatus_Call {
+	return &MockDispatcher_DispatchStatus_Call{Call: _e.mock.On("DispatchStatus", ctx, deliveryID, eventName, event)}
+}
+
+func (_c *MockDispatcher_DispatchStatus_Call) Run(run func(ctx context.Context, deliveryID string, eventName string, event *github.StatusEvent)) *MockDispatcher_DispatchStatus_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(*github.PullRequestEvent))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(*github.StatusEvent))
 	})
 	return _c
 }
 
-func (_c *MockDispatcher_Dispatch_Call) Return(_a0 error) *MockDispatcher_Dispatch_Call {
+func (_c *MockDispatcher_DispatchStatus_Call) Return(_a0 error) *MockDispatcher_DispatchStatus_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockDispatcher_Dispatch_Call) RunAndReturn(run func(context.Context, string, string, *github.PullRequestEvent) error) *MockDispatcher_Dispatch_Call {
+func (_c *MockDispatcher_DispatchStatus_Call) RunAndReturn(run func(context.Context, string, string, *github.StatusEvent) error) *MockDispatcher_DispatchStatus_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -22,6 +22,63 @@ func (_m *MockEventFilter) EXPECT() *MockEventFilter_Expecter {
 	return &MockEventFilter_Expecter{mock: &_m.Mock}
 }
 
+// AllowsVisibility provides a mock function with given fields: ctx, visibility
+func (_m *MockEventFilter) AllowsVisibility(ctx context.Context, visibility string) (bool, error) {
+	ret := _m.Called(ctx, visibility)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AllowsVisibility")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, visibility)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, visibility)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, visibility)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockEventFilter_AllowsVisibility_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AllowsVisibility'
+type MockEventFilter_AllowsVisibility_Call struct {
+	*mock.Call
+}
+
+// AllowsVisibility is a helper method to define mock.On call
+//   - ctx context.Context
+//   - visibility string
+func (_e *MockEventFilter_Expecter) AllowsVisibility(ctx interface{}, visibility interface{}) *MockEventFilter_AllowsVisibility_Call {
+	return &MockEventFilter_AllowsVisibility_Call{Call: _e.mock.On("AllowsVisibility", ctx, visibility)}
+}
+
+func (_c *MockEventFilter_AllowsVisibility_Call) Run(run func(ctx context.Context, visibility string)) *MockEventFilter_AllowsVisibility_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockEventFilter_AllowsVisibility_Call) Return(_a0 bool, _a1 error) *MockEventFilter_AllowsVisibility_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockEventFilter_AllowsVisibility_Call) RunAndReturn(run func(context.Context, string) (bool, error)) *MockEventFilter_AllowsVisibility_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ShouldHandle provides a mock function with given fields: ctx, _a1
 func (_m *MockEventFilter) ShouldHandle(ctx context.Context, _a1 id.PR) (bool, error) {
 	ret := _m.Called(ctx, _a1)
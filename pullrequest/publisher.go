@@ -0,0 +1,117 @@
+package pullrequest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/httplog"
+	"github.com/marqeta/pr-bot/id"
+	"github.com/marqeta/pr-bot/opa/evaluation"
+	"github.com/marqeta/pr-bot/opa/input"
+	"github.com/marqeta/pr-bot/opa/types"
+)
+
+// Publisher publishes the result of an evaluation on a PR.
+//
+//go:generate mockery --name Publisher
+type Publisher interface {
+	// Publish publishes the result of an evaluation,
+	// evalErr is the error of the evaluation, if it failed.
+	Publish(ctx context.Context, id id.PR, ghe input.GHE, result types.Result, evalErr error) error
+}
+
+type publishers struct {
+	publishers []Publisher
+}
+
+// NewPublishers returns a Publisher publishing with every publisher,
+// a publisher failing does not stop the others.
+func NewPublishers(p ...Publisher) Publisher {
+	return &publishers{
+		publishers: p,
+	}
+}
+
+// Publish implements Publisher.
+func (p *publishers) Publish(ctx context.Context, id id.PR, ghe input.GHE, result types.Result, evalErr error) error {
+	errs := make([]error, 0)
+	for _, publisher := range p.publishers {
+		if err := publisher.Publish(ctx, id, ghe, result, evalErr); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// summarize returns the outcome of the evaluation followed by the result of every module,
+// the results are read from the report of the evaluation stored in manager.
+func summarize(ctx context.Context, manager evaluation.Manager, ghe input.GHE, result types.Result, evalErr error) string {
+	oplog := httplog.LogEntry(ctx)
+	var b strings.Builder
+	if evalErr != nil {
+		fmt.Fprintf(&b, "**Outcome:** error\n\n%s\n", evalErr.Error())
+	} else {
+		fmt.Fprintf(&b, "**Outcome:** %v\n\n", result.Review.Type)
+		if result.Review.Body != "" {
+			fmt.Fprintf(&b, "%s\n", result.Review.Body)
+		}
+	}
+
+	pr := fmt.Sprintf("%s/%d", ghe.Repository.GetFullName(), ghe.PullRequest.GetNumber())
+	report, err := manager.GetReport(ctx, pr, evaluation.GetDeliveryID(ctx))
+	if err != nil {
+		// the summary is still published, only without the breakdown
+		oplog.Err(err).Msg("error getting report of evaluation for summary")
+		return b.String()
+	}
+	modules := make([]string, 0, len(report.Breakdown))
+	for module := range report.Breakdown {
+		modules = append(modules, module)
+	}
+	if len(modules) == 0 {
+		return b.String()
+	}
+	slices.Sort(modules)
+	b.WriteString("\n| Module | Review | Notes |\n| --- | --- | --- |\n")
+	for _, module := range modules {
+		r := report.Breakdown[module]
+		fmt.Fprintf(&b, "| %s | %v | %s |\n", module, r.Result.Review.Type, notes(r))
+	}
+	return b.String()
+}
+
+func notes(r evaluation.Result) string {
+	n := make([]string, 0)
+	if r.Shadow {
+		n = append(n, "shadow")
+	}
+	if !r.Result.Track {
+		n = append(n, "not tracked")
+	}
+	if r.CircuitOpen {
+		n = append(n, "circuit open")
+	}
+	if r.Err != nil {
+		n = append(n, fmt.Sprintf("error: %s", r.Err.Error()))
+	}
+	if r.Failure != "" {
+		n = append(n, fmt.Sprintf("failure mode: %s", r.Failure))
+	}
+	// newlines and pipes would break the table
+	return strings.NewReplacer("\n", " ", "|", `\|`).Replace(strings.Join(n, ", "))
+}
+
+type noopPublisher struct{}
+
+// NewNoopPublisher returns a Publisher which does not publish anything.
+func NewNoopPublisher() Publisher {
+	return &noopPublisher{}
+}
+
+// Publish implements Publisher.
+func (*noopPublisher) Publish(_ context.Context, _ id.PR, _ input.GHE, _ types.Result, _ error) error {
+	return nil
+}
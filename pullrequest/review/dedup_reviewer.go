@@ -2,11 +2,15 @@ package review
 
 import (
 	"context"
+	"errors"
+	"slices"
+	"strings"
 
 	"github.com/go-chi/httplog"
 	"github.com/google/go-github/v50/github"
 	gh "github.com/marqeta/pr-bot/github"
 	"github.com/marqeta/pr-bot/id"
+	"github.com/marqeta/pr-bot/opa/evaluation"
 	"github.com/marqeta/pr-bot/opa/types"
 )
 
@@ -23,13 +27,12 @@ type DedupReviewer struct {
 // Approve implements Reviewer.
 func (d *DedupReviewer) Approve(ctx context.Context, id id.PR, body string, opts ApproveOptions) error {
 	oplog := httplog.LogEntry(ctx)
-	reviews, err := d.api.ListReviews(ctx, id)
+	reviews, err := d.listReviews(ctx, id)
 	if err != nil {
-		oplog.Err(err).Msgf(ListReviewsError, id.URL)
 		return err
 	}
-	if d.checkForReview(reviews, types.Approve) {
-		oplog.Info().Msgf("PR already has a review of type %v or higher %v", types.Approve, id.URL)
+	if d.checkForReview(id, reviews, types.Approve, body) {
+		oplog.Info().Msgf("PR already has a review of type %v on %v with the same body %v", types.Approve, id.HeadSHA, id.URL)
 		return nil
 	}
 	return d.delegate.Approve(ctx, id, body, opts)
@@ -38,13 +41,12 @@ func (d *DedupReviewer) Approve(ctx context.Context, id id.PR, body string, opts
 // Comment implements Reviewer.
 func (d *DedupReviewer) Comment(ctx context.Context, id id.PR, body string) error {
 	oplog := httplog.LogEntry(ctx)
-	reviews, err := d.api.ListReviews(ctx, id)
+	reviews, err := d.listReviews(ctx, id)
 	if err != nil {
-		oplog.Err(err).Msgf(ListReviewsError, id.URL)
 		return err
 	}
-	if d.checkForReview(reviews, types.Comment) {
-		oplog.Info().Msgf("PR already has a review of type %v or higher %v", types.Comment, id.URL)
+	if d.checkForReview(id, reviews, types.Comment, body) {
+		oplog.Info().Msgf("PR already has a review of type %v on %v with the same body %v", types.Comment, id.HeadSHA, id.URL)
 		return nil
 	}
 	return d.delegate.Comment(ctx, id, body)
@@ -53,13 +55,12 @@ func (d *DedupReviewer) Comment(ctx context.Context, id id.PR, body string) erro
 // RequestChanges implements Reviewer.
 func (d *DedupReviewer) RequestChanges(ctx context.Context, id id.PR, body string) error {
 	oplog := httplog.LogEntry(ctx)
-	reviews, err := d.api.ListReviews(ctx, id)
+	reviews, err := d.listReviews(ctx, id)
 	if err != nil {
-		oplog.Err(err).Msgf(ListReviewsError, id.URL)
 		return err
 	}
-	if d.checkForReview(reviews, types.RequestChanges) {
-		oplog.Info().Msgf("PR already has a review of type %v or higher %v", types.RequestChanges, id.URL)
+	if d.checkForReview(id, reviews, types.RequestChanges, body) {
+		oplog.Info().Msgf("PR already has a review of type %v on %v with the same body %v", types.RequestChanges, id.HeadSHA, id.URL)
 		return nil
 	}
 	return d.delegate.RequestChanges(ctx, id, body)
@@ -74,22 +75,140 @@ func NewDedupReviewer(delegate Reviewer, api gh.API, serviceAccount string) Revi
 	}
 }
 
-// checkForReview checks if the service account has already reviewed the PR with a review type of minReviewType or higher
-func (d *DedupReviewer) checkForReview(reviews []*github.PullRequestReview, reviewType types.ReviewType) bool {
+// listReviews lists the reviews on the PR, reviews left out of a truncated list are not checked for duplicates.
+func (d *DedupReviewer) listReviews(ctx context.Context, id id.PR) ([]*github.PullRequestReview, error) {
+	oplog := httplog.LogEntry(ctx)
+	reviews, err := d.api.ListReviews(ctx, id)
+	switch {
+	case errors.Is(err, gh.ErrTruncated):
+		oplog.Warn().Err(err).Msgf("checking the first %d reviews on PR %v for duplicates", len(reviews), id.URL)
+	case err != nil:
+		oplog.Err(err).Msgf(ListReviewsError, id.URL)
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// checkForReview checks if the service account has already reviewed the head commit of the PR
+// with a review of reviewType and the same body.
+// reviews are matched on any commit when the head commit is unknown.
+func (d *DedupReviewer) checkForReview(id id.PR, reviews []*github.PullRequestReview,
+	reviewType types.ReviewType, body string) bool {
 	for _, review := range reviews {
-		if review.User.GetLogin() == d.serviceAccount {
-			t, err := types.ParseReviewState(review.GetState())
-			if err != nil {
-				continue
-			}
-			if t == reviewType {
-				return true
-			}
+		if review.User.GetLogin() != d.serviceAccount || gh.IsAnnotation(review.GetBody()) {
+			// reviews with inline comments are not decisions of the policy
+			continue
+		}
+		t, err := types.ParseReviewState(review.GetState())
+		if err != nil || t != reviewType {
+			continue
+		}
+		if id.HeadSHA != "" && review.GetCommitID() != id.HeadSHA {
+			continue
+		}
+		if sameBody(review, body) {
+			return true
 		}
 	}
 	return false
 }
 
+// sameBody checks if the review was posted with body,
+// approval messages posted before the hash of the body was recorded match any body.
+func sameBody(review *github.PullRequestReview, body string) bool {
+	if strings.Contains(review.GetBody(), `"body_hash"`) {
+		return strings.Contains(review.GetBody(), gh.BodyHash(body))
+	}
+	return strings.Contains(review.GetBody(), `"request_id"`)
+}
+
 func (d *DedupReviewer) Dismiss(ctx context.Context, id id.PR, body string) error {
 	return d.delegate.Dismiss(ctx, id, body)
 }
+
+// RequestReviewers implements Reviewer.
+// users and teams whose review is already requested are not requested again,
+// the author of the PR cannot review their own PR.
+func (d *DedupReviewer) RequestReviewers(ctx context.Context, id id.PR, users, teams []string) error {
+	oplog := httplog.LogEntry(ctx)
+	requested, err := d.api.ListRequestedReviewers(ctx, id)
+	if err != nil {
+		oplog.Err(err).Msgf("error listing requested reviewers on PR %v", id.URL)
+		return err
+	}
+	newUsers := make([]string, 0, len(users))
+	for _, u := range users {
+		if strings.EqualFold(u, id.Author) || containsFold(newUsers, u) ||
+			slices.ContainsFunc(requested.Users, func(r *github.User) bool { return strings.EqualFold(r.GetLogin(), u) }) {
+			continue
+		}
+		newUsers = append(newUsers, u)
+	}
+	newTeams := make([]string, 0, len(teams))
+	for _, t := range teams {
+		if containsFold(newTeams, t) ||
+			slices.ContainsFunc(requested.Teams, func(r *github.Team) bool { return strings.EqualFold(r.GetSlug(), t) }) {
+			continue
+		}
+		newTeams = append(newTeams, t)
+	}
+	if len(newUsers) == 0 && len(newTeams) == 0 {
+		oplog.Info().Msgf("reviewers %v and teams %v are already requested on PR %v", users, teams, id.URL)
+		return nil
+	}
+	return d.delegate.RequestReviewers(ctx, id, newUsers, newTeams)
+}
+
+// Annotate implements Reviewer.
+// comments already posted by the service account, either on the diff or in the body of a review, are not posted again.
+func (d *DedupReviewer) Annotate(ctx context.Context, id id.PR, comments []types.InlineComment) error {
+	oplog := httplog.LogEntry(ctx)
+	reviews, err := d.listReviews(ctx, id)
+	if err != nil {
+		return err
+	}
+	posted, err := d.api.ListReviewComments(ctx, id)
+	switch {
+	case errors.Is(err, gh.ErrTruncated):
+		oplog.Warn().Err(err).Msgf("checking the first %d review comments on PR %v for duplicates", len(posted), id.URL)
+	case err != nil:
+		oplog.Err(err).Msgf("error listing review comments on PR %v", id.URL)
+		return err
+	}
+	newComments := make([]types.InlineComment, 0, len(comments))
+	for _, c := range comments {
+		if slices.Contains(newComments, c) || d.isPosted(c, reviews, posted) {
+			continue
+		}
+		newComments = append(newComments, c)
+	}
+	if len(newComments) == 0 {
+		oplog.Info().Msgf("all %d comments are already posted on PR %v", len(comments), id.URL)
+		return nil
+	}
+	return d.delegate.Annotate(ctx, id, newComments)
+}
+
+func (d *DedupReviewer) isPosted(c types.InlineComment, reviews []*github.PullRequestReview,
+	posted []*github.PullRequestComment) bool {
+	onDiff := slices.ContainsFunc(posted, func(p *github.PullRequestComment) bool {
+		return p.GetUser().GetLogin() == d.serviceAccount && p.GetPath() == c.Path &&
+			p.GetBody() == annotation(c) && (p.GetLine() == c.Line || p.GetOriginalLine() == c.Line)
+	})
+	inBody := slices.ContainsFunc(reviews, func(r *github.PullRequestReview) bool {
+		return r.GetUser().GetLogin() == d.serviceAccount && strings.Contains(r.GetBody(), fallback(c))
+	})
+	return onDiff || inBody
+}
+
+func containsFold(s []string, v string) bool {
+	return slices.ContainsFunc(s, func(e string) bool {
+		return strings.EqualFold(e, v)
+	})
+}
+
+// Reconcile implements Reviewer.
+func (d *DedupReviewer) Reconcile(ctx context.Context, id id.PR, body string,
+	opts ReconcileOptions) (evaluation.Reconciliation, error) {
+	return d.delegate.Reconcile(ctx, id, body, opts)
+}
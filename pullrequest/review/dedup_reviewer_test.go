@@ -3,11 +3,13 @@ package review_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/google/go-github/v50/github"
 	gh "github.com/marqeta/pr-bot/github"
 	"github.com/marqeta/pr-bot/id"
+	"github.com/marqeta/pr-bot/opa/types"
 	"github.com/marqeta/pr-bot/pullrequest/review"
 )
 
@@ -142,6 +144,64 @@ func TestDedupReviewer_Approve(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Should re-approve PR when new commits are pushed",
+			args: args{
+				id:             headID("sha2"),
+				body:           "LGTM",
+				serviceAccount: "svc-ci-prbot",
+				setExpectations: func(api *gh.MockAPI, delegate *review.MockReviewer) {
+					api.EXPECT().ListReviews(ctx, headID("sha2")).
+						Return([]*github.PullRequestReview{reviewOf("approved", "sha1", "LGTM")}, nil)
+					delegate.EXPECT().Approve(ctx, headID("sha2"), "LGTM", review.ApproveOptions{}).
+						Return(nil)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should not re-approve PR when reviews are truncated",
+			args: args{
+				id:             headID("sha2"),
+				body:           "LGTM",
+				serviceAccount: "svc-ci-prbot",
+				setExpectations: func(api *gh.MockAPI, _ *review.MockReviewer) {
+					api.EXPECT().ListReviews(ctx, headID("sha2")).
+						Return([]*github.PullRequestReview{reviewOf("approved", "sha2", "LGTM")},
+							fmt.Errorf("reviews of PR: %w", gh.ErrTruncated))
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should approve PR when reviews are truncated",
+			args: args{
+				id:             headID("sha2"),
+				body:           "LGTM",
+				serviceAccount: "svc-ci-prbot",
+				setExpectations: func(api *gh.MockAPI, delegate *review.MockReviewer) {
+					api.EXPECT().ListReviews(ctx, headID("sha2")).
+						Return([]*github.PullRequestReview{prReview("asd", "approved")},
+							fmt.Errorf("reviews of PR: %w", gh.ErrTruncated))
+					delegate.EXPECT().Approve(ctx, headID("sha2"), "LGTM", review.ApproveOptions{}).
+						Return(nil)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should not re-approve head commit of PR",
+			args: args{
+				id:             headID("sha2"),
+				body:           "LGTM",
+				serviceAccount: "svc-ci-prbot",
+				setExpectations: func(api *gh.MockAPI, _ *review.MockReviewer) {
+					api.EXPECT().ListReviews(ctx, headID("sha2")).
+						Return([]*github.PullRequestReview{reviewOf("approved", "sha2", "LGTM")}, nil)
+				},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -201,6 +261,37 @@ func TestDedupReviewer_Comment(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Should comment on head commit with inline comments of the service account",
+			args: args{
+				id:             headID("sha2"),
+				body:           "comment",
+				serviceAccount: "svc-ci-prbot",
+				setExpectations: func(api *gh.MockAPI, delegate *review.MockReviewer) {
+					api.EXPECT().ListReviews(ctx, headID("sha2")).
+						Return([]*github.PullRequestReview{annotationOf("sha2")}, nil)
+					delegate.EXPECT().Comment(ctx, headID("sha2"), "comment").Return(nil)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should comment when a review without an approval message was posted",
+			args: args{
+				id:             headID("sha2"),
+				body:           "comment",
+				serviceAccount: "svc-ci-prbot",
+				setExpectations: func(api *gh.MockAPI, delegate *review.MockReviewer) {
+					other := prReview("svc-ci-prbot", "commented")
+					other.CommitID = github.String("sha2")
+					other.Body = github.String("manual comment")
+					api.EXPECT().ListReviews(ctx, headID("sha2")).
+						Return([]*github.PullRequestReview{other}, nil)
+					delegate.EXPECT().Comment(ctx, headID("sha2"), "comment").Return(nil)
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "Should comment on PR even with approval from other users",
 			args: args{
@@ -298,6 +389,34 @@ func TestDedupReviewer_Comment(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Should comment on PR when the body changed",
+			args: args{
+				id:             headID("sha1"),
+				body:           "new comment",
+				serviceAccount: "svc-ci-prbot",
+				setExpectations: func(api *gh.MockAPI, delegate *review.MockReviewer) {
+					api.EXPECT().ListReviews(ctx, headID("sha1")).
+						Return([]*github.PullRequestReview{reviewOf("commented", "sha1", "comment")}, nil)
+					delegate.EXPECT().Comment(ctx, headID("sha1"), "new comment").
+						Return(nil)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should not comment on PR with the same body twice",
+			args: args{
+				id:             headID("sha1"),
+				body:           "comment",
+				serviceAccount: "svc-ci-prbot",
+				setExpectations: func(api *gh.MockAPI, _ *review.MockReviewer) {
+					api.EXPECT().ListReviews(ctx, headID("sha1")).
+						Return([]*github.PullRequestReview{reviewOf("commented", "sha1", "comment")}, nil)
+				},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -482,14 +601,234 @@ func TestDedupReviewer_RequestChanges(t *testing.T) {
 	}
 }
 
+func TestDedupReviewer_RequestReviewers(t *testing.T) {
+	ctx := context.Background()
+	//nolint:goerr113
+	errRandom := errors.New("random error")
+	type args struct {
+		id              id.PR
+		users           []string
+		teams           []string
+		setExpectations func(api *gh.MockAPI, delegate *review.MockReviewer)
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Should request reviewers for the first time",
+			args: args{
+				id:    sampleID(),
+				users: []string{"alice", "bob"},
+				teams: []string{"infra"},
+				setExpectations: func(api *gh.MockAPI, delegate *review.MockReviewer) {
+					api.EXPECT().ListRequestedReviewers(ctx, sampleID()).
+						Return(&github.Reviewers{}, nil)
+					delegate.EXPECT().RequestReviewers(ctx, sampleID(), []string{"alice", "bob"}, []string{"infra"}).
+						Return(nil)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should not request reviewers and teams already requested",
+			args: args{
+				id:    sampleID(),
+				users: []string{"Alice", "bob"},
+				teams: []string{"infra", "security"},
+				setExpectations: func(api *gh.MockAPI, delegate *review.MockReviewer) {
+					api.EXPECT().ListRequestedReviewers(ctx, sampleID()).
+						Return(&github.Reviewers{
+							Users: []*github.User{{Login: github.String("alice")}},
+							Teams: []*github.Team{{Slug: github.String("Infra")}},
+						}, nil)
+					delegate.EXPECT().RequestReviewers(ctx, sampleID(), []string{"bob"}, []string{"security"}).
+						Return(nil)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should not request review from the author or the same reviewer twice",
+			args: args{
+				id:    sampleID(),
+				users: []string{"user1", "bob", "BOB"},
+				setExpectations: func(api *gh.MockAPI, delegate *review.MockReviewer) {
+					api.EXPECT().ListRequestedReviewers(ctx, sampleID()).
+						Return(&github.Reviewers{}, nil)
+					delegate.EXPECT().RequestReviewers(ctx, sampleID(), []string{"bob"}, []string{}).
+						Return(nil)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should not call delegate when all reviewers are already requested",
+			args: args{
+				id:    sampleID(),
+				users: []string{"user1", "alice"},
+				teams: []string{"infra"},
+				setExpectations: func(api *gh.MockAPI, _ *review.MockReviewer) {
+					api.EXPECT().ListRequestedReviewers(ctx, sampleID()).
+						Return(&github.Reviewers{
+							Users: []*github.User{{Login: github.String("alice")}},
+							Teams: []*github.Team{{Slug: github.String("infra")}},
+						}, nil)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should not request reviewers when listing requested reviewers returns an error",
+			args: args{
+				id:    sampleID(),
+				users: []string{"alice"},
+				setExpectations: func(api *gh.MockAPI, _ *review.MockReviewer) {
+					api.EXPECT().ListRequestedReviewers(ctx, sampleID()).
+						Return(nil, errRandom)
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := gh.NewMockAPI(t)
+			delegate := review.NewMockReviewer(t)
+			tt.args.setExpectations(api, delegate)
+			r := review.NewDedupReviewer(delegate, api, "svc-ci-prbot")
+			if err := r.RequestReviewers(ctx, tt.args.id, tt.args.users, tt.args.teams); (err != nil) != tt.wantErr {
+				t.Errorf("DedupReviewer.RequestReviewers() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDedupReviewer_Annotate(t *testing.T) {
+	ctx := context.Background()
+	//nolint:goerr113
+	errRandom := errors.New("random error")
+	onDiff := types.InlineComment{Path: "main.tf", Line: 2, Side: types.Right, Severity: types.Failure, Message: "pin the version"}
+	outside := types.InlineComment{Path: "main.tf", Line: 10, Side: types.Right, Severity: types.Notice, Message: "unused"}
+	postedOnDiff := &github.PullRequestComment{
+		User:         &github.User{Login: github.String("svc-ci-prbot")},
+		Path:         github.String("main.tf"),
+		OriginalLine: github.Int(2),
+		Body:         github.String(":x: **failure**: pin the version"),
+	}
+	postedInBody := &github.PullRequestReview{
+		User: &github.User{Login: github.String("svc-ci-prbot")},
+		Body: github.String("Policies commented on lines outside the diff:\n\n" +
+			"- `main.tf:10` :information_source: **notice**: unused\n"),
+		State: github.String("COMMENTED"),
+	}
+	type args struct {
+		comments        []types.InlineComment
+		setExpectations func(api *gh.MockAPI, delegate *review.MockReviewer)
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Should post comments for the first time",
+			args: args{
+				comments: []types.InlineComment{onDiff, outside, onDiff},
+				setExpectations: func(api *gh.MockAPI, delegate *review.MockReviewer) {
+					api.EXPECT().ListReviews(ctx, sampleID()).Return(nil, nil)
+					api.EXPECT().ListReviewComments(ctx, sampleID()).Return(nil, nil)
+					delegate.EXPECT().Annotate(ctx, sampleID(), []types.InlineComment{onDiff, outside}).Return(nil)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should not post comments already on the diff",
+			args: args{
+				comments: []types.InlineComment{onDiff, outside},
+				setExpectations: func(api *gh.MockAPI, delegate *review.MockReviewer) {
+					api.EXPECT().ListReviews(ctx, sampleID()).Return(nil, nil)
+					api.EXPECT().ListReviewComments(ctx, sampleID()).
+						Return([]*github.PullRequestComment{postedOnDiff}, nil)
+					delegate.EXPECT().Annotate(ctx, sampleID(), []types.InlineComment{outside}).Return(nil)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should post comments posted by other users",
+			args: args{
+				comments: []types.InlineComment{onDiff},
+				setExpectations: func(api *gh.MockAPI, delegate *review.MockReviewer) {
+					other := *postedOnDiff
+					other.User = &github.User{Login: github.String("asd")}
+					api.EXPECT().ListReviews(ctx, sampleID()).Return(nil, nil)
+					api.EXPECT().ListReviewComments(ctx, sampleID()).
+						Return([]*github.PullRequestComment{&other}, nil)
+					delegate.EXPECT().Annotate(ctx, sampleID(), []types.InlineComment{onDiff}).Return(nil)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should not post comments when all are already posted",
+			args: args{
+				comments: []types.InlineComment{onDiff, outside},
+				setExpectations: func(api *gh.MockAPI, _ *review.MockReviewer) {
+					api.EXPECT().ListReviews(ctx, sampleID()).
+						Return([]*github.PullRequestReview{postedInBody}, nil)
+					api.EXPECT().ListReviewComments(ctx, sampleID()).
+						Return([]*github.PullRequestComment{postedOnDiff}, nil)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should not post comments when list review comments returns an error",
+			args: args{
+				comments: []types.InlineComment{onDiff},
+				setExpectations: func(api *gh.MockAPI, _ *review.MockReviewer) {
+					api.EXPECT().ListReviews(ctx, sampleID()).Return(nil, nil)
+					api.EXPECT().ListReviewComments(ctx, sampleID()).Return(nil, errRandom)
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := gh.NewMockAPI(t)
+			delegate := review.NewMockReviewer(t)
+			tt.args.setExpectations(api, delegate)
+			r := review.NewDedupReviewer(delegate, api, "svc-ci-prbot")
+			if err := r.Annotate(ctx, sampleID(), tt.args.comments); (err != nil) != tt.wantErr {
+				t.Errorf("DedupReviewer.Annotate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// reviews returns reviews of the service account posted before the hash of their body was recorded.
 func reviews(states ...string) []*github.PullRequestReview {
 	var r []*github.PullRequestReview
 	for _, state := range states {
-		r = append(r, prReview("svc-ci-prbot", state))
+		review := prReview("svc-ci-prbot", state)
+		review.Body = github.String("LGTM\n~~~json\n{\n  \"request_id\": \"req1\"\n}\n~~~")
+		r = append(r, review)
 	}
 	return r
 }
 
+// annotationOf returns a review with inline comments of the service account on commit sha.
+func annotationOf(sha string) *github.PullRequestReview {
+	r := prReview("svc-ci-prbot", "commented")
+	r.CommitID = github.String(sha)
+	r.Body = github.String(fmt.Sprintf(gh.AnnotationTemplate, "1 comment", "http://ui", "req1"))
+	return r
+}
+
 func prReview(user, state string) *github.PullRequestReview {
 	return &github.PullRequestReview{
 		User: &github.User{
@@ -498,3 +837,18 @@ func prReview(user, state string) *github.PullRequestReview {
 		State: github.String(state),
 	}
 }
+
+// reviewOf returns a review of the service account on commit sha, posted with body.
+func reviewOf(state, sha, body string) *github.PullRequestReview {
+	r := prReview("svc-ci-prbot", state)
+	r.CommitID = github.String(sha)
+	r.Body = github.String(fmt.Sprintf("%s\n~~~json\n{\n  \"request_id\": \"req1\",\n  \"body_hash\": %q\n}\n~~~",
+		body, gh.BodyHash(body)))
+	return r
+}
+
+func headID(sha string) id.PR {
+	pr := sampleID()
+	pr.HeadSHA = sha
+	return pr
+}
@@ -0,0 +1,102 @@
+package review
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/marqeta/pr-bot/opa/types"
+)
+
+var ErrInvalidHunkHeader = errors.New("invalid hunk header")
+
+var severityIcons = map[types.Severity]string{
+	types.Notice:  ":information_source:",
+	types.Warning: ":warning:",
+	types.Failure: ":x:",
+}
+
+// DiffPosition returns the position of a line of a file in the patch of the file,
+// positions count the lines below the first hunk header, including the headers of later hunks.
+// line is a line of the new file on the RIGHT side and a line of the old file on the LEFT side,
+// side is case-insensitive and any side other than LEFT is the RIGHT side.
+// returns false when the line is not part of the patch.
+func DiffPosition(patch string, line int, side types.Side) (int, bool) {
+	if patch == "" || line < 1 {
+		return 0, false
+	}
+	left := strings.EqualFold(strings.TrimSpace(string(side)), string(types.Left))
+	position := -1
+	oldLine, newLine := 0, 0
+	for _, l := range strings.Split(strings.TrimSuffix(patch, "\n"), "\n") {
+		position++
+		if strings.HasPrefix(l, "@@") {
+			var err error
+			oldLine, newLine, err = hunkStart(l)
+			if err != nil {
+				return 0, false
+			}
+			continue
+		}
+		if position == 0 {
+			// patch does not start with a hunk header
+			return 0, false
+		}
+		switch {
+		case strings.HasPrefix(l, "+"):
+			if !left && newLine == line {
+				return position, true
+			}
+			newLine++
+		case strings.HasPrefix(l, "-"):
+			if left && oldLine == line {
+				return position, true
+			}
+			oldLine++
+		case strings.HasPrefix(l, `\`):
+			// \ No newline at end of file
+		default:
+			if (left && oldLine == line) || (!left && newLine == line) {
+				return position, true
+			}
+			oldLine++
+			newLine++
+		}
+	}
+	return 0, false
+}
+
+// hunkStart returns the first line of the old and the new file of a hunk header,
+// e.g. @@ -10,7 +10,8 @@ func main() {
+func hunkStart(header string) (int, int, error) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 {
+		return 0, 0, fmt.Errorf("%w %q", ErrInvalidHunkHeader, header)
+	}
+	oldLine, err := rangeStart(fields[1], "-")
+	if err != nil {
+		return 0, 0, err
+	}
+	newLine, err := rangeStart(fields[2], "+")
+	if err != nil {
+		return 0, 0, err
+	}
+	return oldLine, newLine, nil
+}
+
+func rangeStart(r, prefix string) (int, error) {
+	start, _, _ := strings.Cut(strings.TrimPrefix(r, prefix), ",")
+	return strconv.Atoi(start)
+}
+
+// annotation returns the body of an inline comment.
+func annotation(c types.InlineComment) string {
+	return fmt.Sprintf("%s **%s**: %s", severityIcons[c.Severity], c.Severity, c.Message)
+}
+
+// fallback returns an inline comment as an item of the list in the review body,
+// for comments on lines which are not part of the diff.
+func fallback(c types.InlineComment) string {
+	return fmt.Sprintf("- `%s:%d` %s", c.Path, c.Line, annotation(c))
+}
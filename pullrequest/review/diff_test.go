@@ -0,0 +1,53 @@
+package review_test
+
+import (
+	"testing"
+
+	"github.com/marqeta/pr-bot/opa/types"
+	"github.com/marqeta/pr-bot/pullrequest/review"
+)
+
+func TestDiffPosition(t *testing.T) {
+	patch := "@@ -1,4 +1,5 @@\n" +
+		" package main\n" +
+		"-import \"fmt\"\n" +
+		"+import (\n" +
+		"+\t\"fmt\"\n" +
+		"+)\n" +
+		" \n" +
+		"@@ -20,3 +21,3 @@ func main() {\n" +
+		" \tfmt.Println(a)\n" +
+		"-\tfmt.Println(b)\n" +
+		"+\tfmt.Println(c)\n" +
+		"\\ No newline at end of file"
+	tests := []struct {
+		name   string
+		patch  string
+		line   int
+		side   types.Side
+		want   int
+		wantOk bool
+	}{
+		{name: "Should map context line", patch: patch, line: 1, side: types.Right, want: 1, wantOk: true},
+		{name: "Should map added line", patch: patch, line: 3, side: types.Right, want: 4, wantOk: true},
+		{name: "Should map removed line on the left side", patch: patch, line: 2, side: types.Left, want: 2, wantOk: true},
+		{name: "Should map context line on the left side", patch: patch, line: 3, side: types.Left, want: 6, wantOk: true},
+		{name: "Should count headers of later hunks", patch: patch, line: 22, side: types.Right, want: 10, wantOk: true},
+		{name: "Should map line of later hunk on the left side", patch: patch, line: 21, side: types.Left, want: 9, wantOk: true},
+		{name: "Should default to the right side", patch: patch, line: 21, want: 8, wantOk: true},
+		{name: "Should match side case-insensitively", patch: patch, line: 21, side: "left", want: 9, wantOk: true},
+		{name: "Should not map line between hunks", patch: patch, line: 10, side: types.Right},
+		{name: "Should not map removed line on the right side", patch: "@@ -1 +0,0 @@\n-a", line: 1, side: types.Right},
+		{name: "Should not map line of file without patch", patch: "", line: 1, side: types.Right},
+		{name: "Should not map patch without hunk header", patch: "+a", line: 1, side: types.Right},
+		{name: "Should not map patch with invalid hunk header", patch: "@@ -a +b @@\n+a", line: 1, side: types.Right},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := review.DiffPosition(tt.patch, tt.line, tt.side)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("DiffPosition() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
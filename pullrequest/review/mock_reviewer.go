@@ -6,7 +6,11 @@ import (
 	context "context"
 
 	id "github.com/marqeta/pr-bot/id"
+	evaluation "github.com/marqeta/pr-bot/opa/evaluation"
+
 	mock "github.com/stretchr/testify/mock"
+
+	types "github.com/marqeta/pr-bot/opa/types"
 )
 
 // MockReviewer is an autogenerated mock type for the Reviewer type
@@ -22,6 +26,54 @@ func (_m *MockReviewer) EXPECT() *MockReviewer_Expecter {
 	return &MockReviewer_Expecter{mock: &_m.Mock}
 }
 
+// Annotate provides a mock function with given fields: ctx, _a1, comments
+func (_m *MockReviewer) Annotate(ctx context.Context, _a1 id.PR, comments []types.InlineComment) error {
+	ret := _m.Called(ctx, _a1, comments)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Annotate")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, []types.InlineComment) error); ok {
+		r0 = rf(ctx, _a1, comments)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockReviewer_Annotate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Annotate'
+type MockReviewer_Annotate_Call struct {
+	*mock.Call
+}
+
+// Annotate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - _a1 id.PR
+//   - comments []types.InlineComment
+func (_e *MockReviewer_Expecter) Annotate(ctx interface{}, _a1 interface{}, comments interface{}) *MockReviewer_Annotate_Call {
+	return &MockReviewer_Annotate_Call{Call: _e.mock.On("Annotate", ctx, _a1, comments)}
+}
+
+func (_c *MockReviewer_Annotate_Call) Run(run func(ctx context.Context, _a1 id.PR, comments []types.InlineComment)) *MockReviewer_Annotate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(id.PR), args[2].([]types.InlineComment))
+	})
+	return _c
+}
+
+func (_c *MockReviewer_Annotate_Call) Return(_a0 error) *MockReviewer_Annotate_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockReviewer_Annotate_Call) RunAndReturn(run func(context.Context, id.PR, []types.InlineComment) error) *MockReviewer_Annotate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Approve provides a mock function with given fields: ctx, _a1, body, opts
 func (_m *MockReviewer) Approve(ctx context.Context, _a1 id.PR, body string, opts ApproveOptions) error {
 	ret := _m.Called(ctx, _a1, body, opts)
@@ -119,12 +171,12 @@ func (_c *MockReviewer_Comment_Call) RunAndReturn(run func(context.Context, id.P
 	return _c
 }
 
-// RequestChanges provides a mock function with given fields: ctx, _a1, body
-func (_m *MockReviewer) RequestChanges(ctx context.Context, _a1 id.PR, body string) error {
+// Dismiss provides a mock function with given fields: ctx, _a1, body
+func (_m *MockReviewer) Dismiss(ctx context.Context, _a1 id.PR, body string) error {
 	ret := _m.Called(ctx, _a1, body)
 
 	if len(ret) == 0 {
-		panic("no return value specified for RequestChanges")
+		panic("no return value specified for Dismiss")
 	}
 
 	var r0 error
@@ -137,12 +189,101 @@ func (_m *MockReviewer) RequestChanges(ctx context.Context, _a1 id.PR, body stri
 	return r0
 }
 
-// Dismiss provides a mock function with given fields: ctx, _a1, body
-func (_m *MockReviewer) Dismiss(ctx context.Context, _a1 id.PR, body string) error {
+// MockReviewer_Dismiss_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Dismiss'
+type MockReviewer_Dismiss_Call struct {
+	*mock.Call
+}
+
+// Dismiss is a helper method to define mock.On call
+//   - ctx context.Context
+//   - _a1 id.PR
+//   - body string
+func (_e *MockReviewer_Expecter) Dismiss(ctx interface{}, _a1 interface{}, body interface{}) *MockReviewer_Dismiss_Call {
+	return &MockReviewer_Dismiss_Call{Call: _e.mock.On("Dismiss", ctx, _a1, body)}
+}
+
+func (_c *MockReviewer_Dismiss_Call) Run(run func(ctx context.Context, _a1 id.PR, body string)) *MockReviewer_Dismiss_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(id.PR), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockReviewer_Dismiss_Call) Return(_a0 error) *MockReviewer_Dismiss_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockReviewer_Dismiss_Call) RunAndReturn(run func(context.Context, id.PR, string) error) *MockReviewer_Dismiss_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Reconcile provides a mock function with given fields: ctx, _a1, body, opts
+func (_m *MockReviewer) Reconcile(ctx context.Context, _a1 id.PR, body string, opts ReconcileOptions) (evaluation.Reconciliation, error) {
+	ret := _m.Called(ctx, _a1, body, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reconcile")
+	}
+
+	var r0 evaluation.Reconciliation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, string, ReconcileOptions) (evaluation.Reconciliation, error)); ok {
+		return rf(ctx, _a1, body, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, string, ReconcileOptions) evaluation.Reconciliation); ok {
+		r0 = rf(ctx, _a1, body, opts)
+	} else {
+		r0 = ret.Get(0).(evaluation.Reconciliation)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, id.PR, string, ReconcileOptions) error); ok {
+		r1 = rf(ctx, _a1, body, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockReviewer_Reconcile_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Reconcile'
+type MockReviewer_Reconcile_Call struct {
+	*mock.Call
+}
+
+// Reconcile is a helper method to define mock.On call
+//   - ctx context.Context
+//   - _a1 id.PR
+//   - body string
+//   - opts ReconcileOptions
+func (_e *MockReviewer_Expecter) Reconcile(ctx interface{}, _a1 interface{}, body interface{}, opts interface{}) *MockReviewer_Reconcile_Call {
+	return &MockReviewer_Reconcile_Call{Call: _e.mock.On("Reconcile", ctx, _a1, body, opts)}
+}
+
+func (_c *MockReviewer_Reconcile_Call) Run(run func(ctx context.Context, _a1 id.PR, body string, opts ReconcileOptions)) *MockReviewer_Reconcile_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(id.PR), args[2].(string), args[3].(ReconcileOptions))
+	})
+	return _c
+}
+
+func (_c *MockReviewer_Reconcile_Call) Return(_a0 evaluation.Reconciliation, _a1 error) *MockReviewer_Reconcile_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockReviewer_Reconcile_Call) RunAndReturn(run func(context.Context, id.PR, string, ReconcileOptions) (evaluation.Reconciliation, error)) *MockReviewer_Reconcile_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RequestChanges provides a mock function with given fields: ctx, _a1, body
+func (_m *MockReviewer) RequestChanges(ctx context.Context, _a1 id.PR, body string) error {
 	ret := _m.Called(ctx, _a1, body)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Dismiss")
+		panic("no return value specified for RequestChanges")
 	}
 
 	var r0 error
@@ -185,6 +326,55 @@ func (_c *MockReviewer_RequestChanges_Call) RunAndReturn(run func(context.Contex
 	return _c
 }
 
+// RequestReviewers provides a mock function with given fields: ctx, _a1, users, teams
+func (_m *MockReviewer) RequestReviewers(ctx context.Context, _a1 id.PR, users []string, teams []string) error {
+	ret := _m.Called(ctx, _a1, users, teams)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequestReviewers")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, id.PR, []string, []string) error); ok {
+		r0 = rf(ctx, _a1, users, teams)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockReviewer_RequestReviewers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RequestReviewers'
+type MockReviewer_RequestReviewers_Call struct {
+	*mock.Call
+}
+
+// RequestReviewers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - _a1 id.PR
+//   - users []string
+//   - teams []string
+func (_e *MockReviewer_Expecter) RequestReviewers(ctx interface{}, _a1 interface{}, users interface{}, teams interface{}) *MockReviewer_RequestReviewers_Call {
+	return &MockReviewer_RequestReviewers_Call{Call: _e.mock.On("RequestReviewers", ctx, _a1, users, teams)}
+}
+
+func (_c *MockReviewer_RequestReviewers_Call) Run(run func(ctx context.Context, _a1 id.PR, users []string, teams []string)) *MockReviewer_RequestReviewers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(id.PR), args[2].([]string), args[3].([]string))
+	})
+	return _c
+}
+
+func (_c *MockReviewer_RequestReviewers_Call) Return(_a0 error) *MockReviewer_RequestReviewers_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockReviewer_RequestReviewers_Call) RunAndReturn(run func(context.Context, id.PR, []string, []string) error) *MockReviewer_RequestReviewers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockReviewer creates a new instance of MockReviewer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockReviewer(t interface {
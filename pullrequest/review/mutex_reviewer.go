@@ -11,6 +11,8 @@ import (
 
 	pe "github.com/marqeta/pr-bot/errors"
 	"github.com/marqeta/pr-bot/id"
+	"github.com/marqeta/pr-bot/opa/evaluation"
+	"github.com/marqeta/pr-bot/opa/types"
 )
 
 //go:generate mockery --name Locker --testonly
@@ -103,3 +105,31 @@ func (r *mutexReviewer) Dismiss(ctx context.Context, id id.PR, body string) erro
 	defer r.releaseLock(ctx, lock, id)
 	return r.delegate.Dismiss(ctx, id, body)
 }
+
+func (r *mutexReviewer) RequestReviewers(ctx context.Context, id id.PR, users, teams []string) error {
+	lock, err := r.acquireLock(ctx, id)
+	if err != nil {
+		return err
+	}
+	defer r.releaseLock(ctx, lock, id)
+	return r.delegate.RequestReviewers(ctx, id, users, teams)
+}
+
+func (r *mutexReviewer) Annotate(ctx context.Context, id id.PR, comments []types.InlineComment) error {
+	lock, err := r.acquireLock(ctx, id)
+	if err != nil {
+		return err
+	}
+	defer r.releaseLock(ctx, lock, id)
+	return r.delegate.Annotate(ctx, id, comments)
+}
+
+func (r *mutexReviewer) Reconcile(ctx context.Context, id id.PR, body string,
+	opts ReconcileOptions) (evaluation.Reconciliation, error) {
+	lock, err := r.acquireLock(ctx, id)
+	if err != nil {
+		return evaluation.Reconciliation{}, err
+	}
+	defer r.releaseLock(ctx, lock, id)
+	return r.delegate.Reconcile(ctx, id, body, opts)
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/go-chi/httplog"
 	pe "github.com/marqeta/pr-bot/errors"
 	"github.com/marqeta/pr-bot/id"
+	"github.com/marqeta/pr-bot/opa/evaluation"
+	"github.com/marqeta/pr-bot/opa/types"
 )
 
 var ErrAutoMergeDisabled = errors.New("auto merge is disabled in repo")
@@ -19,7 +21,8 @@ type preCondValidationReviewer struct {
 func (p *preCondValidationReviewer) Approve(ctx context.Context, id id.PR, body string, opts ApproveOptions) error {
 	oplog := httplog.LogEntry(ctx)
 
-	if !opts.AutoMergeEnabled {
+	// auto merge is not needed when a human merges the PR
+	if !opts.AutoMergeEnabled && !opts.ApproveOnly {
 		ae := pe.UserError(ctx, AutoMergeError, ErrAutoMergeDisabled)
 		oplog.Error().Msgf("Auto merge is disabled in repo for pr %v", id.URL)
 		return ae
@@ -44,6 +47,23 @@ func NewPreCondValidationReviewer(delegate Reviewer) Reviewer {
 	}
 }
 
+// Dismiss implements Reviewer.
 func (p *preCondValidationReviewer) Dismiss(ctx context.Context, id id.PR, body string) error {
 	return p.delegate.Dismiss(ctx, id, body)
 }
+
+// RequestReviewers implements Reviewer.
+func (p *preCondValidationReviewer) RequestReviewers(ctx context.Context, id id.PR, users, teams []string) error {
+	return p.delegate.RequestReviewers(ctx, id, users, teams)
+}
+
+// Annotate implements Reviewer.
+func (p *preCondValidationReviewer) Annotate(ctx context.Context, id id.PR, comments []types.InlineComment) error {
+	return p.delegate.Annotate(ctx, id, comments)
+}
+
+// Reconcile implements Reviewer.
+func (p *preCondValidationReviewer) Reconcile(ctx context.Context, id id.PR, body string,
+	opts ReconcileOptions) (evaluation.Reconciliation, error) {
+	return p.delegate.Reconcile(ctx, id, body, opts)
+}
@@ -41,6 +41,24 @@ func Test_preCondValidationReviewer_Approve(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Should call delegate if automerge is disabled and PR is only approved",
+			args: args{
+				id:   sampleID(),
+				body: "LGTM",
+				opts: review.ApproveOptions{
+					DefaultBranch: "main",
+					ApproveOnly:   true,
+				},
+				setExpectations: func(delegate *review.MockReviewer) {
+					delegate.EXPECT().Approve(ctx, sampleID(), "LGTM", review.ApproveOptions{
+						DefaultBranch: "main",
+						ApproveOnly:   true,
+					}).Return(nil)
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "Should not call delegate if automerge is disabled",
 			args: args{
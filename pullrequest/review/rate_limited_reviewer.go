@@ -9,6 +9,8 @@ import (
 	pe "github.com/marqeta/pr-bot/errors"
 	gh "github.com/marqeta/pr-bot/github"
 	"github.com/marqeta/pr-bot/id"
+	"github.com/marqeta/pr-bot/opa/evaluation"
+	"github.com/marqeta/pr-bot/opa/types"
 	"github.com/marqeta/pr-bot/rate"
 )
 
@@ -53,6 +55,23 @@ func NewRateLimitedReviewer(delegate Reviewer, api gh.API, throttler rate.Thrott
 	}
 }
 
+// Dismiss implements Reviewer.
 func (r *rateLimitedReviewer) Dismiss(ctx context.Context, id id.PR, body string) error {
 	return r.delegate.Dismiss(ctx, id, body)
 }
+
+// RequestReviewers implements Reviewer.
+func (r *rateLimitedReviewer) RequestReviewers(ctx context.Context, id id.PR, users, teams []string) error {
+	return r.delegate.RequestReviewers(ctx, id, users, teams)
+}
+
+// Annotate implements Reviewer.
+func (r *rateLimitedReviewer) Annotate(ctx context.Context, id id.PR, comments []types.InlineComment) error {
+	return r.delegate.Annotate(ctx, id, comments)
+}
+
+// Reconcile implements Reviewer.
+func (r *rateLimitedReviewer) Reconcile(ctx context.Context, id id.PR, body string,
+	opts ReconcileOptions) (evaluation.Reconciliation, error) {
+	return r.delegate.Reconcile(ctx, id, body, opts)
+}
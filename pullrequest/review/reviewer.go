@@ -2,14 +2,18 @@ package review
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/go-chi/httplog"
+	"github.com/google/go-github/v50/github"
 	pe "github.com/marqeta/pr-bot/errors"
 	gh "github.com/marqeta/pr-bot/github"
 	"github.com/marqeta/pr-bot/id"
 	"github.com/marqeta/pr-bot/metrics"
+	"github.com/marqeta/pr-bot/opa/evaluation"
+	"github.com/marqeta/pr-bot/opa/types"
 	"github.com/shurcooL/githubv4"
 )
 
@@ -21,6 +25,13 @@ type ApproveOptions struct {
 	AutoMergeEnabled bool
 	DefaultBranch    string
 	MergeMethod      githubv4.PullRequestMergeMethod
+	// ApproveOnly approves the PR without enabling auto merge, the PR is merged by a human.
+	ApproveOnly bool
+}
+
+type ReconcileOptions struct {
+	// AutoMergeEnabledBy is the login of the user who enabled auto merge on the PR, empty if auto merge is disabled.
+	AutoMergeEnabledBy string
 }
 
 //go:generate mockery --name Reviewer
@@ -29,6 +40,14 @@ type Reviewer interface {
 	RequestChanges(ctx context.Context, id id.PR, body string) error
 	Comment(ctx context.Context, id id.PR, body string) error
 	Dismiss(ctx context.Context, id id.PR, body string) error
+	// RequestReviewers requests a review from users and team slugs.
+	RequestReviewers(ctx context.Context, id id.PR, users, teams []string) error
+	// Annotate posts a single review with the comments on lines of the diff,
+	// comments on lines outside the diff are listed in the body of the review.
+	Annotate(ctx context.Context, id id.PR, comments []types.InlineComment) error
+	// Reconcile undoes an earlier approval of pr-bot, which is stale after new commits were pushed.
+	// approvals of pr-bot are dismissed with body and auto merge is disabled if pr-bot enabled it.
+	Reconcile(ctx context.Context, id id.PR, body string, opts ReconcileOptions) (evaluation.Reconciliation, error)
 }
 
 type reviewer struct {
@@ -40,14 +59,18 @@ type reviewer struct {
 // Approve implements Reviewer.
 func (r *reviewer) Approve(ctx context.Context, id id.PR, body string, opts ApproveOptions) error {
 	oplog := httplog.LogEntry(ctx)
-	err := r.api.EnableAutoMerge(ctx, id, opts.MergeMethod)
-	if err != nil {
-		oplog.Err(err).Msgf("error enabling auto merge on PR %v", id.URL)
-		return r.handleAutoMergeError(ctx, id, err)
+	if opts.ApproveOnly {
+		oplog.Info().Msgf("approving PR without enabling auto merge")
+	} else {
+		err := r.api.EnableAutoMerge(ctx, id, opts.MergeMethod)
+		if err != nil {
+			oplog.Err(err).Msgf("error enabling auto merge on PR %v", id.URL)
+			return r.handleAutoMergeError(ctx, id, err)
+		}
+		oplog.Info().Msgf("enabled auto merge on PR")
 	}
-	oplog.Info().Msgf("enabled auto merge on PR")
 
-	err = r.api.AddReview(ctx, id, body, gh.Approve)
+	err := r.api.AddReview(ctx, id, body, gh.Approve)
 	if err != nil {
 		oplog.Err(err).Msgf("error approving PR")
 		ae := pe.ServiceFault(ctx, "Error approving PR", err)
@@ -55,6 +78,7 @@ func (r *reviewer) Approve(ctx context.Context, id id.PR, body string, opts Appr
 		return ae
 	}
 	tags := append(id.ToTags(), fmt.Sprintf("mergeMethod:%s", opts.MergeMethod))
+	tags = append(tags, fmt.Sprintf("autoMerge:%t", !opts.ApproveOnly))
 	tags = append(tags, fmt.Sprintf("reviewType:%s", "approve"))
 	r.metrics.EmitDist(ctx, "reviewedPRs", 1.0, tags)
 	r.metrics.EmitDist(ctx, "approvedPRs", 1.0, tags)
@@ -102,7 +126,10 @@ func (r *reviewer) Dismiss(ctx context.Context, id id.PR, body string) error {
 	
 	// Get existing reviews to find CHANGES_REQUESTED reviews to dismiss
 	reviews, err := r.api.ListReviews(ctx, id)
-	if err != nil {
+	switch {
+	case errors.Is(err, gh.ErrTruncated):
+		oplog.Warn().Err(err).Msgf("dismissing reviews among the first %d reviews for PR %v", len(reviews), id.URL)
+	case err != nil:
 		oplog.Err(err).Msgf("error listing reviews for PR %v", id.URL)
 		ae := pe.ServiceFault(ctx, "Error listing reviews for PR", err)
 		return ae
@@ -134,6 +161,112 @@ func (r *reviewer) Dismiss(ctx context.Context, id id.PR, body string) error {
 	return nil
 }
 
+// RequestReviewers implements Reviewer.
+func (r *reviewer) RequestReviewers(ctx context.Context, id id.PR, users, teams []string) error {
+	oplog := httplog.LogEntry(ctx)
+	err := r.api.RequestReviewers(ctx, id, users, teams)
+	if err != nil {
+		oplog.Err(err).Msgf("error requesting reviewers %v and teams %v on PR %v", users, teams, id.URL)
+		return pe.UserError(ctx, "Error requesting reviewers", err)
+	}
+	tags := append(id.ToTags(), "reviewType:request_reviewers")
+	r.metrics.EmitDist(ctx, "reviewersRequested", float64(len(users)+len(teams)), tags)
+	oplog.Info().Msgf("requested reviewers %v and teams %v", users, teams)
+	return nil
+}
+
+// Annotate implements Reviewer.
+func (r *reviewer) Annotate(ctx context.Context, id id.PR, comments []types.InlineComment) error {
+	oplog := httplog.LogEntry(ctx)
+	files, err := r.api.ListFilesChangedInPR(ctx, id)
+	switch {
+	case errors.Is(err, gh.ErrTruncated):
+		// comments on files left out of the list are listed in the body of the review
+		oplog.Warn().Err(err).Msgf("annotating the first %d files changed in PR %v", len(files), id.URL)
+	case err != nil:
+		oplog.Err(err).Msgf("error listing files changed in PR %v", id.URL)
+		return pe.ServiceFault(ctx, "Error listing files changed in PR", err)
+	}
+	patches := make(map[string]string, len(files))
+	for _, f := range files {
+		patches[f.GetFilename()] = f.GetPatch()
+	}
+
+	drafts := make([]*github.DraftReviewComment, 0, len(comments))
+	outside := make([]string, 0)
+	for _, c := range comments {
+		position, ok := DiffPosition(patches[c.Path], c.Line, c.Side)
+		if !ok {
+			outside = append(outside, fallback(c))
+			continue
+		}
+		drafts = append(drafts, &github.DraftReviewComment{
+			Path:     github.String(c.Path),
+			Position: github.Int(position),
+			Body:     github.String(annotation(c)),
+		})
+	}
+	summary := "Policies commented on the diff"
+	if len(outside) > 0 {
+		summary = fmt.Sprintf("Policies commented on lines outside the diff:\n\n%s", strings.Join(outside, "\n"))
+	}
+
+	err = r.api.AddReviewComments(ctx, id, summary, drafts)
+	if err != nil {
+		oplog.Err(err).Msgf("error adding review comments to PR %v", id.URL)
+		return pe.ServiceFault(ctx, "Error adding review comments to PR", err)
+	}
+	r.metrics.EmitDist(ctx, "reviewComments", float64(len(drafts)), append(id.ToTags(), "placement:diff"))
+	r.metrics.EmitDist(ctx, "reviewComments", float64(len(outside)), append(id.ToTags(), "placement:body"))
+	oplog.Info().Msgf("added %d review comments, %d outside the diff", len(drafts), len(outside))
+	return nil
+}
+
+// Reconcile implements Reviewer.
+func (r *reviewer) Reconcile(ctx context.Context, id id.PR, body string,
+	opts ReconcileOptions) (evaluation.Reconciliation, error) {
+	oplog := httplog.LogEntry(ctx)
+	var reconciliation evaluation.Reconciliation
+	reviews, err := r.api.ListReviews(ctx, id)
+	switch {
+	case errors.Is(err, gh.ErrTruncated):
+		oplog.Warn().Err(err).Msgf("dismissing approvals among the first %d reviews for PR %v", len(reviews), id.URL)
+	case err != nil:
+		oplog.Err(err).Msgf("error listing reviews for PR %v", id.URL)
+		return reconciliation, pe.ServiceFault(ctx, "Error listing reviews for PR", err)
+	}
+
+	for _, review := range reviews {
+		if review.GetState() != "APPROVED" || review.GetUser().GetLogin() != r.serviceAccount {
+			continue
+		}
+		err = r.api.DismissReview(ctx, id, review.GetID(), body)
+		if err != nil {
+			oplog.Err(err).Msgf("error dismissing approval %d for PR %v", review.GetID(), id.URL)
+			return reconciliation, pe.ServiceFault(ctx, "Error dismissing approval", err)
+		}
+		reconciliation.DismissedReviews = append(reconciliation.DismissedReviews, review.GetID())
+		oplog.Info().Msgf("dismissed stale approval %d for PR %v", review.GetID(), id.URL)
+	}
+
+	if opts.AutoMergeEnabledBy != "" && opts.AutoMergeEnabledBy == r.serviceAccount {
+		err = r.api.DisableAutoMerge(ctx, id)
+		if err != nil {
+			oplog.Err(err).Msgf("error disabling auto merge on PR %v", id.URL)
+			return reconciliation, pe.ServiceFault(ctx, "Error disabling auto merge on PR", err)
+		}
+		reconciliation.AutoMergeDisabled = true
+		oplog.Info().Msgf("disabled auto merge on PR %v", id.URL)
+	}
+
+	if !reconciliation.IsEmpty() {
+		tags := append(id.ToTags(), fmt.Sprintf("autoMergeDisabled:%t", reconciliation.AutoMergeDisabled))
+		r.metrics.EmitDist(ctx, "reconciledPRs", 1.0, tags)
+		r.metrics.EmitDist(ctx, "dismissedApprovals", float64(len(reconciliation.DismissedReviews)), tags)
+	}
+	return reconciliation, nil
+}
+
 func NewReviewer(dao gh.API, metrics metrics.Emitter, serviceAccount string) Reviewer {
 	return &reviewer{api: dao, metrics: metrics, serviceAccount: serviceAccount}
 }
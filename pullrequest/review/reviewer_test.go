@@ -3,13 +3,19 @@ package review_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
+	"github.com/google/go-github/v50/github"
 	gh "github.com/marqeta/pr-bot/github"
 	"github.com/marqeta/pr-bot/id"
 	"github.com/marqeta/pr-bot/metrics"
+	"github.com/marqeta/pr-bot/opa/evaluation"
+	"github.com/marqeta/pr-bot/opa/types"
 	"github.com/marqeta/pr-bot/pullrequest/review"
 	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func Test_reviewer_Approve(t *testing.T) {
@@ -29,6 +35,7 @@ func Test_reviewer_Approve(t *testing.T) {
 		body            string
 		setExpectations func(d *gh.MockAPI)
 		mergeMethod     githubv4.PullRequestMergeMethod
+		approveOnly     bool
 	}
 	tests := []struct {
 		name    string
@@ -50,6 +57,20 @@ func Test_reviewer_Approve(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Should approve PR without enabling auto merge",
+			args: args{
+				id:   sampleID(),
+				body: "LGTM",
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().AddReview(ctx, sampleID(), "LGTM", gh.Approve).
+						Return(nil)
+				},
+				mergeMethod: githubv4.PullRequestMergeMethodMerge,
+				approveOnly: true,
+			},
+			wantErr: false,
+		},
 		{
 			name: "Should auto merge PR with rebase",
 			args: args{
@@ -169,6 +190,7 @@ func Test_reviewer_Approve(t *testing.T) {
 			tt.args.setExpectations(mockAPI)
 			if err := r.Approve(ctx, tt.args.id, tt.args.body, review.ApproveOptions{
 				MergeMethod: tt.args.mergeMethod,
+				ApproveOnly: tt.args.approveOnly,
 			}); (err != nil) != tt.wantErr {
 				t.Errorf("reviewer.Approve() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -282,6 +304,275 @@ func Test_reviewer_RequestChanges(t *testing.T) {
 	}
 }
 
+func Test_reviewer_RequestReviewers(t *testing.T) {
+	ctx := context.Background()
+
+	//nolint:goerr113
+	errRandom := errors.New("random error")
+	type args struct {
+		id              id.PR
+		users           []string
+		teams           []string
+		setExpectations func(d *gh.MockAPI)
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Should request reviewers on PR",
+			args: args{
+				id:    sampleID(),
+				users: []string{"alice"},
+				teams: []string{"infra"},
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().RequestReviewers(ctx, sampleID(), []string{"alice"}, []string{"infra"}).
+						Return(nil)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Throw error when RequestReviewers fails",
+			args: args{
+				id:    sampleID(),
+				users: []string{"alice"},
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().RequestReviewers(ctx, sampleID(), []string{"alice"}, []string(nil)).
+						Return(errRandom).Once()
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI := gh.NewMockAPI(t)
+			metrics := metrics.NewNoopEmitter()
+			r := review.NewReviewer(mockAPI, metrics, "test-service-account")
+			tt.args.setExpectations(mockAPI)
+			if err := r.RequestReviewers(ctx, tt.args.id, tt.args.users, tt.args.teams); (err != nil) != tt.wantErr {
+				t.Errorf("reviewer.RequestReviewers() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_reviewer_Annotate(t *testing.T) {
+	ctx := context.Background()
+
+	//nolint:goerr113
+	errRandom := errors.New("random error")
+	files := []*github.CommitFile{
+		{Filename: github.String("main.tf"), Patch: github.String("@@ -1,2 +1,3 @@\n a\n+b\n c")},
+		{Filename: github.String("image.png")},
+	}
+	onDiff := types.InlineComment{Path: "main.tf", Line: 2, Side: types.Right, Severity: types.Failure, Message: "pin the version"}
+	outside := types.InlineComment{Path: "main.tf", Line: 10, Side: types.Right, Severity: types.Notice, Message: "unused"}
+	binary := types.InlineComment{Path: "image.png", Line: 1, Side: types.Right, Severity: types.Warning, Message: "too large"}
+	type args struct {
+		id              id.PR
+		comments        []types.InlineComment
+		setExpectations func(d *gh.MockAPI)
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Should post comments on the diff",
+			args: args{
+				id:       sampleID(),
+				comments: []types.InlineComment{onDiff},
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().ListFilesChangedInPR(ctx, sampleID()).Return(files, nil)
+					d.EXPECT().AddReviewComments(ctx, sampleID(), "Policies commented on the diff", []*github.DraftReviewComment{
+						{
+							Path:     github.String("main.tf"),
+							Position: github.Int(2),
+							Body:     github.String(":x: **failure**: pin the version"),
+						},
+					}).Return(nil)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should list comments outside the diff in the body",
+			args: args{
+				id:       sampleID(),
+				comments: []types.InlineComment{onDiff, outside, binary},
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().ListFilesChangedInPR(ctx, sampleID()).Return(files, nil)
+					d.EXPECT().AddReviewComments(ctx, sampleID(),
+						"Policies commented on lines outside the diff:\n\n"+
+							"- `main.tf:10` :information_source: **notice**: unused\n"+
+							"- `image.png:1` :warning: **warning**: too large",
+						[]*github.DraftReviewComment{
+							{
+								Path:     github.String("main.tf"),
+								Position: github.Int(2),
+								Body:     github.String(":x: **failure**: pin the version"),
+							},
+						}).Return(nil)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should list comments on files left out of a truncated list in the body",
+			args: args{
+				id:       sampleID(),
+				comments: []types.InlineComment{onDiff, binary},
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().ListFilesChangedInPR(ctx, sampleID()).
+						Return(files[:1], fmt.Errorf("files of PR: %w", gh.ErrTruncated))
+					d.EXPECT().AddReviewComments(ctx, sampleID(),
+						"Policies commented on lines outside the diff:\n\n"+
+							"- `image.png:1` :warning: **warning**: too large",
+						[]*github.DraftReviewComment{
+							{
+								Path:     github.String("main.tf"),
+								Position: github.Int(2),
+								Body:     github.String(":x: **failure**: pin the version"),
+							},
+						}).Return(nil)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Throw error when ListFilesChangedInPR fails",
+			args: args{
+				id:       sampleID(),
+				comments: []types.InlineComment{onDiff},
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().ListFilesChangedInPR(ctx, sampleID()).Return(nil, errRandom)
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Throw error when AddReviewComments fails",
+			args: args{
+				id:       sampleID(),
+				comments: []types.InlineComment{onDiff},
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().ListFilesChangedInPR(ctx, sampleID()).Return(files, nil)
+					d.EXPECT().AddReviewComments(ctx, sampleID(), "Policies commented on the diff", mock.Anything).
+						Return(errRandom).Once()
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI := gh.NewMockAPI(t)
+			metrics := metrics.NewNoopEmitter()
+			r := review.NewReviewer(mockAPI, metrics, "test-service-account")
+			tt.args.setExpectations(mockAPI)
+			if err := r.Annotate(ctx, tt.args.id, tt.args.comments); (err != nil) != tt.wantErr {
+				t.Errorf("reviewer.Annotate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_reviewer_Reconcile(t *testing.T) {
+	ctx := context.Background()
+
+	//nolint:goerr113
+	errRandom := errors.New("random error")
+	review1 := func(id int64, user, state string) *github.PullRequestReview {
+		return &github.PullRequestReview{
+			ID:    github.Int64(id),
+			User:  &github.User{Login: github.String(user)},
+			State: github.String(state),
+		}
+	}
+	reviews := []*github.PullRequestReview{
+		review1(1, "test-service-account", "APPROVED"),
+		review1(2, "user1", "APPROVED"),
+		review1(3, "test-service-account", "COMMENTED"),
+		review1(4, "test-service-account", "APPROVED"),
+	}
+	type args struct {
+		opts            review.ReconcileOptions
+		setExpectations func(d *gh.MockAPI)
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    evaluation.Reconciliation
+		wantErr bool
+	}{
+		{
+			name: "Should dismiss approvals of service account and disable auto merge enabled by service account",
+			args: args{
+				opts: review.ReconcileOptions{AutoMergeEnabledBy: "test-service-account"},
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().ListReviews(ctx, sampleID()).Return(reviews, nil)
+					d.EXPECT().DismissReview(ctx, sampleID(), int64(1), "stale").Return(nil)
+					d.EXPECT().DismissReview(ctx, sampleID(), int64(4), "stale").Return(nil)
+					d.EXPECT().DisableAutoMerge(ctx, sampleID()).Return(nil)
+				},
+			},
+			want:    evaluation.Reconciliation{DismissedReviews: []int64{1, 4}, AutoMergeDisabled: true},
+			wantErr: false,
+		},
+		{
+			name: "Should not disable auto merge enabled by other users",
+			args: args{
+				opts: review.ReconcileOptions{AutoMergeEnabledBy: "user1"},
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().ListReviews(ctx, sampleID()).Return(reviews[1:3], nil)
+				},
+			},
+			want:    evaluation.Reconciliation{},
+			wantErr: false,
+		},
+		{
+			name: "Throw error when ListReviews fails",
+			args: args{
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().ListReviews(ctx, sampleID()).Return(nil, errRandom)
+				},
+			},
+			want:    evaluation.Reconciliation{},
+			wantErr: true,
+		},
+		{
+			name: "Throw error and return dismissed reviews when DisableAutoMerge fails",
+			args: args{
+				opts: review.ReconcileOptions{AutoMergeEnabledBy: "test-service-account"},
+				setExpectations: func(d *gh.MockAPI) {
+					d.EXPECT().ListReviews(ctx, sampleID()).Return(reviews[:1], nil)
+					d.EXPECT().DismissReview(ctx, sampleID(), int64(1), "stale").Return(nil)
+					d.EXPECT().DisableAutoMerge(ctx, sampleID()).Return(errRandom)
+				},
+			},
+			want:    evaluation.Reconciliation{DismissedReviews: []int64{1}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI := gh.NewMockAPI(t)
+			metrics := metrics.NewNoopEmitter()
+			r := review.NewReviewer(mockAPI, metrics, "test-service-account")
+			tt.args.setExpectations(mockAPI)
+			got, err := r.Reconcile(ctx, sampleID(), "stale", tt.args.opts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("reviewer.Reconcile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func sampleID() id.PR {
 
 	return id.PR{
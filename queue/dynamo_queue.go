@@ -0,0 +1,294 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/jonboulle/clockwork"
+	"github.com/marqeta/pr-bot/metrics"
+)
+
+const (
+	// IndexName of the GSI used to find visible deliveries.
+	// partition key: queue, sort key: visible_at
+	IndexName = "queue-visible_at-index"
+	// number of visible deliveries fetched in a single query,
+	// more than one so that workers racing for the same delivery can claim the next one
+	dequeueBatchSize = 10
+)
+
+//go:generate mockery --name Dao  --testonly
+type Dao interface {
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput,
+		optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput,
+		optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+	DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput,
+		optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+	Query(ctx context.Context, params *dynamodb.QueryInput,
+		optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+}
+
+type record struct {
+	Queue string `json:"queue"`
+	Delivery
+	ExpireAt int64 `json:"expire_at"`
+}
+
+type dynamoQueue struct {
+	dao               Dao
+	clock             clockwork.Clock
+	metrics           metrics.Emitter
+	table             string
+	name              string
+	ttl               time.Duration
+	visibilityTimeout time.Duration
+}
+
+// Enqueue implements Queue.
+func (q *dynamoQueue) Enqueue(ctx context.Context, d *Delivery) error {
+	now := q.clock.Now()
+	r := record{
+		Queue:    q.name,
+		Delivery: *d,
+		ExpireAt: now.Add(q.ttl).Unix(),
+	}
+	r.EnqueuedAt = now.Unix()
+	r.VisibleAt = now.Unix()
+	item, err := attributevalue.MarshalMapWithOptions(r, useJSONTagEncoding)
+	if err != nil {
+		emitError(ctx, q.metrics, "Enqueue", "MarshalError")
+		return err
+	}
+	// github redelivers with the same delivery id, do not enqueue it twice.
+	cond, err := expression.NewBuilder().
+		WithCondition(expression.AttributeNotExists(expression.Name("delivery_id"))).Build()
+	if err != nil {
+		emitError(ctx, q.metrics, "Enqueue", "ExpressionError")
+		return err
+	}
+	_, err = q.dao.PutItem(ctx, &dynamodb.PutItemInput{
+		Item:                      item,
+		TableName:                 &q.table,
+		ConditionExpression:       cond.Condition(),
+		ExpressionAttributeNames:  cond.Names(),
+		ExpressionAttributeValues: cond.Values(),
+	})
+	if isConditionalCheckFailed(err) {
+		return nil
+	}
+	if err != nil {
+		emitError(ctx, q.metrics, "Enqueue", "DDBPutItemError")
+		return err
+	}
+	return nil
+}
+
+// Dequeue implements Queue.
+func (q *dynamoQueue) Dequeue(ctx context.Context) (*Delivery, error) {
+	now := q.clock.Now()
+	keyEx := expression.Key("queue").Equal(expression.Value(q.name)).
+		And(expression.Key("visible_at").LessThanEqual(expression.Value(now.Unix())))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyEx).Build()
+	if err != nil {
+		emitError(ctx, q.metrics, "Dequeue", "ExpressionError")
+		return nil, err
+	}
+	o, err := q.dao.Query(ctx, &dynamodb.QueryInput{
+		TableName:                 &q.table,
+		IndexName:                 aws.String(IndexName),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		Limit:                     aws.Int32(dequeueBatchSize),
+	})
+	if err != nil {
+		emitError(ctx, q.metrics, "Dequeue", "DDBQueryError")
+		return nil, err
+	}
+	var records []record
+	err = attributevalue.UnmarshalListOfMapsWithOptions(o.Items, &records, useJSONTagForDecoding)
+	if err != nil {
+		emitError(ctx, q.metrics, "Dequeue", "UnMarshalError")
+		return nil, err
+	}
+	for _, r := range records {
+		claimed, err := q.claim(ctx, &r.Delivery, now.Add(q.visibilityTimeout).Unix())
+		if err != nil {
+			return nil, err
+		}
+		if claimed {
+			return &r.Delivery, nil
+		}
+	}
+	return nil, nil
+}
+
+// claim hides the delivery from other consumers and counts the attempt,
+// returns false if another consumer has already claimed it.
+func (q *dynamoQueue) claim(ctx context.Context, d *Delivery, visibleAt int64) (bool, error) {
+	update := expression.Set(expression.Name("visible_at"), expression.Value(visibleAt)).
+		Set(expression.Name("attempts"), expression.Value(d.Attempts+1))
+	cond := expression.Name("visible_at").Equal(expression.Value(d.VisibleAt))
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(cond).Build()
+	if err != nil {
+		emitError(ctx, q.metrics, "Dequeue", "ExpressionError")
+		return false, err
+	}
+	key, err := key(d.DeliveryID)
+	if err != nil {
+		emitError(ctx, q.metrics, "Dequeue", "MarshalError")
+		return false, err
+	}
+	_, err = q.dao.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 &q.table,
+		Key:                       key,
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if isConditionalCheckFailed(err) {
+		return false, nil
+	}
+	if err != nil {
+		emitError(ctx, q.metrics, "Dequeue", "DDBUpdateItemError")
+		return false, err
+	}
+	d.VisibleAt = visibleAt
+	d.Attempts++
+	return true, nil
+}
+
+// Ack implements Queue.
+func (q *dynamoQueue) Ack(ctx context.Context, d *Delivery) error {
+	key, err := key(d.DeliveryID)
+	if err != nil {
+		emitError(ctx, q.metrics, "Ack", "MarshalError")
+		return err
+	}
+	_, err = q.dao.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: &q.table,
+		Key:       key,
+	})
+	if err != nil {
+		emitError(ctx, q.metrics, "Ack", "DDBDeleteItemError")
+		return err
+	}
+	return nil
+}
+
+// Nack implements Queue.
+func (q *dynamoQueue) Nack(ctx context.Context, d *Delivery, delay time.Duration) error {
+	update := expression.Set(expression.Name("visible_at"), expression.Value(q.clock.Now().Add(delay).Unix())).
+		Set(expression.Name("attempts"), expression.Value(d.Attempts)).
+		Set(expression.Name("last_error"), expression.Value(d.LastError))
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		emitError(ctx, q.metrics, "Nack", "ExpressionError")
+		return err
+	}
+	key, err := key(d.DeliveryID)
+	if err != nil {
+		emitError(ctx, q.metrics, "Nack", "MarshalError")
+		return err
+	}
+	_, err = q.dao.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 &q.table,
+		Key:                       key,
+		UpdateExpression:          expr.Update(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		emitError(ctx, q.metrics, "Nack", "DDBUpdateItemError")
+		return err
+	}
+	return nil
+}
+
+// NewDynamoQueue returns a durable queue backed by a DynamoDB table,
+// table must have a GSI named IndexName.
+func NewDynamoQueue(dao Dao, clock clockwork.Clock, m metrics.Emitter, table, name string,
+	ttl, visibilityTimeout time.Duration) Queue {
+	return &dynamoQueue{
+		dao:               dao,
+		clock:             clock,
+		metrics:           m,
+		table:             table,
+		name:              name,
+		ttl:               ttl,
+		visibilityTimeout: visibilityTimeout,
+	}
+}
+
+type dynamoDeadLetterStore struct {
+	dao     Dao
+	clock   clockwork.Clock
+	metrics metrics.Emitter
+	table   string
+	name    string
+	ttl     time.Duration
+}
+
+// Put implements DeadLetterStore.
+func (s *dynamoDeadLetterStore) Put(ctx context.Context, d *Delivery) error {
+	r := record{
+		Queue:    s.name,
+		Delivery: *d,
+		ExpireAt: s.clock.Now().Add(s.ttl).Unix(),
+	}
+	item, err := attributevalue.MarshalMapWithOptions(r, useJSONTagEncoding)
+	if err != nil {
+		emitError(ctx, s.metrics, "DeadLetter", "MarshalError")
+		return err
+	}
+	_, err = s.dao.PutItem(ctx, &dynamodb.PutItemInput{
+		Item:      item,
+		TableName: &s.table,
+	})
+	if err != nil {
+		emitError(ctx, s.metrics, "DeadLetter", "DDBPutItemError")
+		return err
+	}
+	return nil
+}
+
+func NewDynamoDeadLetterStore(dao Dao, clock clockwork.Clock, m metrics.Emitter, table, name string,
+	ttl time.Duration) DeadLetterStore {
+	return &dynamoDeadLetterStore{
+		dao:     dao,
+		clock:   clock,
+		metrics: m,
+		table:   table,
+		name:    name,
+		ttl:     ttl,
+	}
+}
+
+func key(deliveryID string) (map[string]types.AttributeValue, error) {
+	id, err := attributevalue.Marshal(deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]types.AttributeValue{"delivery_id": id}, nil
+}
+
+func isConditionalCheckFailed(err error) bool {
+	var ccf *types.ConditionalCheckFailedException
+	return errors.As(err, &ccf)
+}
+
+func useJSONTagEncoding(opts *attributevalue.EncoderOptions) {
+	opts.TagKey = "json"
+}
+
+func useJSONTagForDecoding(opts *attributevalue.DecoderOptions) {
+	opts.TagKey = "json"
+}
@@ -0,0 +1,108 @@
+package queue
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/jonboulle/clockwork"
+	"github.com/marqeta/pr-bot/metrics"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+)
+
+func Test_dynamoQueue_Enqueue(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		putErr  error
+		wantErr bool
+	}{
+		{
+			name:    "should enqueue delivery",
+			putErr:  nil,
+			wantErr: false,
+		},
+		{
+			name:    "should ignore redelivery of an enqueued delivery",
+			putErr:  &types.ConditionalCheckFailedException{},
+			wantErr: false,
+		},
+		{
+			name:    "should return error from ddb",
+			putErr:  &types.InternalServerError{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dao := NewMockDao(t)
+			clock := clockwork.NewFakeClock()
+			q := NewDynamoQueue(dao, clock, metrics.NewNoopEmitter(), "table", "pull_request", time.Hour, time.Minute)
+
+			dao.EXPECT().PutItem(ctx, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+				var r record
+				err := attributevalue.UnmarshalMapWithOptions(in.Item, &r, useJSONTagForDecoding)
+				return err == nil && r.DeliveryID == "d1" && r.Queue == "pull_request" &&
+					r.VisibleAt == clock.Now().Unix() && r.ExpireAt == clock.Now().Add(time.Hour).Unix() &&
+					*in.ConditionExpression != ""
+			})).Return(&dynamodb.PutItemOutput{}, tt.putErr).Once()
+
+			err := q.Enqueue(ctx, &Delivery{DeliveryID: "d1"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("dynamoQueue.Enqueue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_dynamoQueue_Dequeue(t *testing.T) {
+	ctx := context.Background()
+	clock := clockwork.NewFakeClock()
+	dao := NewMockDao(t)
+	q := NewDynamoQueue(dao, clock, metrics.NewNoopEmitter(), "table", "pull_request", time.Hour, time.Minute)
+
+	items := make([]map[string]types.AttributeValue, 0)
+	for _, id := range []string{"d1", "d2"} {
+		item, err := attributevalue.MarshalMapWithOptions(record{
+			Queue:    "pull_request",
+			Delivery: Delivery{DeliveryID: id, VisibleAt: clock.Now().Unix(), Attempts: 2},
+		}, useJSONTagEncoding)
+		assert.Nil(t, err)
+		items = append(items, item)
+	}
+	dao.EXPECT().Query(ctx, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+		return *in.IndexName == IndexName && *in.TableName == "table"
+	})).Return(&dynamodb.QueryOutput{Items: items}, nil).Once()
+
+	// d1 was claimed by another worker
+	dao.EXPECT().UpdateItem(ctx, mock.MatchedBy(isKey("d1"))).
+		Return(nil, &types.ConditionalCheckFailedException{}).Once()
+	dao.EXPECT().UpdateItem(ctx, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+		// the claim counts the attempt
+		return isKey("d2")(in) && slices.ContainsFunc(slices.Collect(maps.Values(in.ExpressionAttributeValues)),
+			func(v types.AttributeValue) bool {
+				var attempts int
+				return attributevalue.Unmarshal(v, &attempts) == nil && attempts == 3
+			})
+	})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+
+	d, err := q.Dequeue(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "d2", d.DeliveryID)
+	assert.Equal(t, clock.Now().Add(time.Minute).Unix(), d.VisibleAt)
+	assert.Equal(t, 3, d.Attempts)
+}
+
+func isKey(deliveryID string) func(in *dynamodb.UpdateItemInput) bool {
+	return func(in *dynamodb.UpdateItemInput) bool {
+		var id string
+		err := attributevalue.Unmarshal(in.Key["delivery_id"], &id)
+		return err == nil && id == deliveryID
+	}
+}
@@ -0,0 +1,102 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+)
+
+type inMemoryQueue struct {
+	mu                sync.Mutex
+	clock             clockwork.Clock
+	visibilityTimeout time.Duration
+	deliveries        []*Delivery
+}
+
+// Enqueue implements Queue.
+func (q *inMemoryQueue) Enqueue(_ context.Context, d *Delivery) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	now := q.clock.Now().Unix()
+	c := *d
+	c.EnqueuedAt = now
+	c.VisibleAt = now
+	q.deliveries = append(q.deliveries, &c)
+	return nil
+}
+
+// Dequeue implements Queue.
+func (q *inMemoryQueue) Dequeue(_ context.Context) (*Delivery, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	now := q.clock.Now()
+	for _, d := range q.deliveries {
+		if d.VisibleAt <= now.Unix() {
+			d.VisibleAt = now.Add(q.visibilityTimeout).Unix()
+			d.Attempts++
+			c := *d
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+// Ack implements Queue.
+func (q *inMemoryQueue) Ack(_ context.Context, d *Delivery) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, e := range q.deliveries {
+		if e.DeliveryID == d.DeliveryID {
+			q.deliveries = append(q.deliveries[:i], q.deliveries[i+1:]...)
+			return nil
+		}
+	}
+	return ErrDeliveryNotFound
+}
+
+// Nack implements Queue.
+func (q *inMemoryQueue) Nack(_ context.Context, d *Delivery, delay time.Duration) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, e := range q.deliveries {
+		if e.DeliveryID == d.DeliveryID {
+			e.Attempts = d.Attempts
+			e.LastError = d.LastError
+			e.VisibleAt = q.clock.Now().Add(delay).Unix()
+			return nil
+		}
+	}
+	return ErrDeliveryNotFound
+}
+
+// NewInMemoryQueue returns a non durable queue, used for tests and local runs.
+func NewInMemoryQueue(clock clockwork.Clock, visibilityTimeout time.Duration) Queue {
+	return &inMemoryQueue{
+		clock:             clock,
+		visibilityTimeout: visibilityTimeout,
+		deliveries:        make([]*Delivery, 0),
+	}
+}
+
+type inMemoryDeadLetterStore struct {
+	mu         sync.Mutex
+	deliveries []*Delivery
+}
+
+// Put implements DeadLetterStore.
+func (s *inMemoryDeadLetterStore) Put(_ context.Context, d *Delivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := *d
+	s.deliveries = append(s.deliveries, &c)
+	return nil
+}
+
+// NewInMemoryDeadLetterStore returns a non durable dead letter store, used for tests and local runs.
+func NewInMemoryDeadLetterStore() DeadLetterStore {
+	return &inMemoryDeadLetterStore{
+		deliveries: make([]*Delivery, 0),
+	}
+}
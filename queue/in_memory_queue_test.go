@@ -0,0 +1,64 @@
+package queue_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/marqeta/pr-bot/queue"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_inMemoryQueue(t *testing.T) {
+	ctx := context.Background()
+	clock := clockwork.NewFakeClock()
+	q := queue.NewInMemoryQueue(clock, time.Minute)
+
+	d, err := q.Dequeue(ctx)
+	assert.Nil(t, err)
+	assert.Nil(t, d, "empty queue should not return a delivery")
+
+	assert.Nil(t, q.Enqueue(ctx, &queue.Delivery{DeliveryID: "d1"}))
+	assert.Nil(t, q.Enqueue(ctx, &queue.Delivery{DeliveryID: "d2"}))
+
+	d1, err := q.Dequeue(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "d1", d1.DeliveryID)
+	assert.Equal(t, clock.Now().Unix(), d1.EnqueuedAt)
+	assert.Equal(t, 1, d1.Attempts, "dequeue should count the attempt")
+
+	d2, err := q.Dequeue(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "d2", d2.DeliveryID)
+
+	d, err = q.Dequeue(ctx)
+	assert.Nil(t, err)
+	assert.Nil(t, d, "in flight deliveries should not be visible")
+
+	d1.LastError = "random error"
+	assert.Nil(t, q.Nack(ctx, d1, 10*time.Second))
+	assert.Nil(t, q.Ack(ctx, d2))
+
+	clock.Advance(5 * time.Second)
+	d, err = q.Dequeue(ctx)
+	assert.Nil(t, err)
+	assert.Nil(t, d, "nacked delivery should not be visible before delay")
+
+	clock.Advance(5 * time.Second)
+	d, err = q.Dequeue(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "d1", d.DeliveryID)
+	assert.Equal(t, 2, d.Attempts)
+	assert.Equal(t, "random error", d.LastError)
+
+	clock.Advance(time.Minute)
+	d, err = q.Dequeue(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "d1", d.DeliveryID, "delivery should be visible after visibility timeout")
+	assert.Equal(t, 3, d.Attempts, "attempts which time out should be counted")
+
+	assert.Nil(t, q.Ack(ctx, d))
+	assert.ErrorIs(t, q.Ack(ctx, d), queue.ErrDeliveryNotFound)
+	assert.ErrorIs(t, q.Nack(ctx, d, time.Second), queue.ErrDeliveryNotFound)
+}
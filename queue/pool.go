@@ -0,0 +1,189 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/httplog"
+	"github.com/google/go-github/v50/github"
+	"github.com/jonboulle/clockwork"
+	pe "github.com/marqeta/pr-bot/errors"
+	"github.com/marqeta/pr-bot/metrics"
+	"github.com/rs/zerolog/log"
+)
+
+type PoolConfig struct {
+	// Workers is the number of deliveries processed concurrently.
+	Workers int
+	// MaxAttempts after which a delivery is moved to the dead letter store.
+	MaxAttempts int
+	// PollInterval is how long an idle worker waits before polling the queue again.
+	PollInterval time.Duration
+	// BaseBackoff is the delay before the first retry, doubled on every attempt.
+	BaseBackoff time.Duration
+	// MaxBackoff caps the delay between retries.
+	MaxBackoff time.Duration
+	// Timeout for processing a single delivery.
+	Timeout time.Duration
+}
+
+// Pool drains the queue with a bounded number of workers.
+type Pool struct {
+	queue      Queue
+	deadLetter DeadLetterStore
+	handler    Handler
+	cfg        PoolConfig
+	clock      clockwork.Clock
+	metrics    metrics.Emitter
+	done       chan struct{}
+	wg         sync.WaitGroup
+}
+
+func NewPool(q Queue, dlq DeadLetterStore, h Handler, cfg PoolConfig,
+	clock clockwork.Clock, m metrics.Emitter) *Pool {
+	return &Pool{
+		queue:      q,
+		deadLetter: dlq,
+		handler:    h,
+		cfg:        cfg,
+		clock:      clock,
+		metrics:    m,
+		done:       make(chan struct{}),
+	}
+}
+
+// Start starts the workers in the background.
+func (p *Pool) Start() {
+	log.Info().Msgf("Starting %d queue workers", p.cfg.Workers)
+	for i := 0; i < p.cfg.Workers; i++ {
+		p.wg.Add(1)
+		go p.work()
+	}
+}
+
+// Close stops polling the queue and waits for in flight deliveries to finish.
+func (p *Pool) Close() {
+	close(p.done)
+	p.wg.Wait()
+	log.Info().Msg("Queue workers stopped")
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+	for {
+		select {
+		case <-p.done:
+			return
+		default:
+		}
+		processed, err := p.ProcessOne(context.Background())
+		if err != nil {
+			log.Err(err).Msg("error processing delivery from queue")
+		}
+		if processed && err == nil {
+			continue
+		}
+		select {
+		case <-p.done:
+			return
+		case <-p.clock.After(p.cfg.PollInterval):
+		}
+	}
+}
+
+// ProcessOne dequeues and processes a single delivery,
+// returns false if the queue had no deliveries ready to be processed.
+func (p *Pool) ProcessOne(ctx context.Context) (bool, error) {
+	d, err := p.queue.Dequeue(ctx)
+	if err != nil {
+		p.metrics.EmitDist(ctx, "queue.dequeue.error", 1, nil)
+		return false, err
+	}
+	if d == nil {
+		return false, nil
+	}
+
+	ctx, cancel := context.WithTimeout(p.newContext(ctx, d), p.cfg.Timeout)
+	defer cancel()
+	oplog := httplog.LogEntry(ctx)
+	tags := []string{fmt.Sprintf("eventName:%s", d.EventName)}
+	p.metrics.EmitDist(ctx, "queue.latency", float64(p.clock.Now().Unix()-d.EnqueuedAt), tags)
+
+	if d.Attempts > p.cfg.MaxAttempts {
+		// the last attempt did not finish, the worker crashed or hung
+		oplog.Error().Msgf("moving delivery to dead letter store after %d unfinished attempts", d.Attempts-1)
+		p.metrics.EmitDist(ctx, "queue.deadLettered", 1, tags)
+		if err = p.deadLetter.Put(ctx, d); err != nil {
+			return true, err
+		}
+		return true, p.queue.Ack(ctx, d)
+	}
+
+	err = p.handler.Handle(ctx, d)
+	if err == nil {
+		p.metrics.EmitDist(ctx, "queue.processed", 1, tags)
+		return true, p.queue.Ack(ctx, d)
+	}
+
+	oplog.Err(err).Msgf("attempt %d to process delivery failed", d.Attempts)
+	d.LastError = err.Error()
+	if !isRetryable(err) || d.Attempts >= p.cfg.MaxAttempts {
+		oplog.Error().Msgf("moving delivery to dead letter store after %d attempts", d.Attempts)
+		p.metrics.EmitDist(ctx, "queue.deadLettered", 1, tags)
+		err = p.deadLetter.Put(ctx, d)
+		if err != nil {
+			// leave the delivery in the queue, it becomes visible after the visibility timeout
+			return true, err
+		}
+		return true, p.queue.Ack(ctx, d)
+	}
+
+	p.metrics.EmitDist(ctx, "queue.retried", 1, tags)
+	return true, p.queue.Nack(ctx, d, p.backoff(d.Attempts))
+}
+
+// backoff returns the delay before the next attempt.
+func (p *Pool) backoff(attempts int) time.Duration {
+	delay := p.cfg.BaseBackoff
+	for i := 1; i < attempts && delay < p.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > p.cfg.MaxBackoff {
+		return p.cfg.MaxBackoff
+	}
+	return delay
+}
+
+// newContext recreates the request scoped values the handlers expect
+// i.e. request id and a logger entry.
+func (p *Pool) newContext(ctx context.Context, d *Delivery) context.Context {
+	ctx = context.WithValue(ctx, middleware.RequestIDKey, d.RequestID)
+	logger := log.Logger.With().
+		Str("requestID", d.RequestID).
+		Str("deliveryID", d.DeliveryID).
+		Str("eventName", d.EventName).
+		Int("attempt", d.Attempts).
+		Logger()
+	return context.WithValue(ctx, middleware.LogEntryCtxKey, &httplog.RequestLoggerEntry{Logger: logger})
+}
+
+// isRetryable returns false for user errors,
+// processing them again would fail with the same error.
+// GitHub rate limits, including secondary rate limits answered with 403, are retried.
+func isRetryable(err error) bool {
+	var rle *github.RateLimitError
+	var arle *github.AbuseRateLimitError
+	if errors.As(err, &rle) || errors.As(err, &arle) {
+		return true
+	}
+	var ae pe.APIError
+	if errors.As(err, &ae) {
+		return ae.IsServiceError() || ae.StatusCode == http.StatusTooManyRequests
+	}
+	return true
+}
@@ -0,0 +1,227 @@
+package queue_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v50/github"
+	"github.com/jonboulle/clockwork"
+	pe "github.com/marqeta/pr-bot/errors"
+	"github.com/marqeta/pr-bot/metrics"
+	"github.com/marqeta/pr-bot/queue"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+)
+
+var errRandom = errors.New("random error")
+
+func TestPool_ProcessOne(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name            string
+		setExpectations func(q *queue.MockQueue, dlq *queue.MockDeadLetterStore, h *queue.MockHandler)
+		wantProcessed   bool
+		wantErr         error
+	}{
+		{
+			name: "should return false when queue is empty",
+			setExpectations: func(q *queue.MockQueue, _ *queue.MockDeadLetterStore, _ *queue.MockHandler) {
+				q.EXPECT().Dequeue(ctx).Return(nil, nil).Once()
+			},
+			wantProcessed: false,
+			wantErr:       nil,
+		},
+		{
+			name: "should return error from dequeue",
+			setExpectations: func(q *queue.MockQueue, _ *queue.MockDeadLetterStore, _ *queue.MockHandler) {
+				q.EXPECT().Dequeue(ctx).Return(nil, errRandom).Once()
+			},
+			wantProcessed: false,
+			wantErr:       errRandom,
+		},
+		{
+			name: "should ack delivery after successful processing",
+			setExpectations: func(q *queue.MockQueue, _ *queue.MockDeadLetterStore, h *queue.MockHandler) {
+				q.EXPECT().Dequeue(ctx).Return(delivery(1), nil).Once()
+				h.EXPECT().Handle(mock.Anything, delivery(1)).Return(nil).Once()
+				q.EXPECT().Ack(mock.Anything, delivery(1)).Return(nil).Once()
+			},
+			wantProcessed: true,
+			wantErr:       nil,
+		},
+		{
+			name: "should nack delivery with backoff when processing fails",
+			setExpectations: func(q *queue.MockQueue, _ *queue.MockDeadLetterStore, h *queue.MockHandler) {
+				q.EXPECT().Dequeue(ctx).Return(delivery(2), nil).Once()
+				h.EXPECT().Handle(mock.Anything, delivery(2)).Return(errRandom).Once()
+				q.EXPECT().Nack(mock.Anything, failed(delivery(2), errRandom), 2*time.Second).Return(nil).Once()
+			},
+			wantProcessed: true,
+			wantErr:       nil,
+		},
+		{
+			name: "should retry throttled deliveries",
+			setExpectations: func(q *queue.MockQueue, _ *queue.MockDeadLetterStore, h *queue.MockHandler) {
+				throttled := pe.TooManyRequestError(ctx, "Error acquiring lock", errRandom)
+				q.EXPECT().Dequeue(ctx).Return(delivery(1), nil).Once()
+				h.EXPECT().Handle(mock.Anything, delivery(1)).Return(throttled).Once()
+				q.EXPECT().Nack(mock.Anything, failed(delivery(1), throttled), 1*time.Second).Return(nil).Once()
+			},
+			wantProcessed: true,
+			wantErr:       nil,
+		},
+		{
+			name: "should retry deliveries hitting the secondary rate limit of GitHub",
+			setExpectations: func(q *queue.MockQueue, _ *queue.MockDeadLetterStore, h *queue.MockHandler) {
+				limited := fmt.Errorf("error listing reviews: %w", &github.AbuseRateLimitError{
+					Response: &http.Response{StatusCode: http.StatusForbidden, Request: &http.Request{Method: http.MethodGet,
+						URL: &url.URL{}}},
+					Message: "secondary rate limit",
+				})
+				q.EXPECT().Dequeue(ctx).Return(delivery(1), nil).Once()
+				h.EXPECT().Handle(mock.Anything, delivery(1)).Return(limited).Once()
+				q.EXPECT().Nack(mock.Anything, failed(delivery(1), limited), 1*time.Second).Return(nil).Once()
+			},
+			wantProcessed: true,
+			wantErr:       nil,
+		},
+		{
+			name: "should cap backoff",
+			setExpectations: func(q *queue.MockQueue, _ *queue.MockDeadLetterStore, h *queue.MockHandler) {
+				q.EXPECT().Dequeue(ctx).Return(delivery(4), nil).Once()
+				h.EXPECT().Handle(mock.Anything, delivery(4)).Return(errRandom).Once()
+				q.EXPECT().Nack(mock.Anything, failed(delivery(4), errRandom), 5*time.Second).Return(nil).Once()
+			},
+			wantProcessed: true,
+			wantErr:       nil,
+		},
+		{
+			name: "should dead letter delivery after max attempts",
+			setExpectations: func(q *queue.MockQueue, dlq *queue.MockDeadLetterStore, h *queue.MockHandler) {
+				q.EXPECT().Dequeue(ctx).Return(delivery(5), nil).Once()
+				h.EXPECT().Handle(mock.Anything, delivery(5)).Return(errRandom).Once()
+				dlq.EXPECT().Put(mock.Anything, failed(delivery(5), errRandom)).Return(nil).Once()
+				q.EXPECT().Ack(mock.Anything, failed(delivery(5), errRandom)).Return(nil).Once()
+			},
+			wantProcessed: true,
+			wantErr:       nil,
+		},
+		{
+			name: "should dead letter delivery whose last attempt did not finish",
+			setExpectations: func(q *queue.MockQueue, dlq *queue.MockDeadLetterStore, _ *queue.MockHandler) {
+				q.EXPECT().Dequeue(ctx).Return(delivery(6), nil).Once()
+				dlq.EXPECT().Put(mock.Anything, delivery(6)).Return(nil).Once()
+				q.EXPECT().Ack(mock.Anything, delivery(6)).Return(nil).Once()
+			},
+			wantProcessed: true,
+			wantErr:       nil,
+		},
+		{
+			name: "should dead letter delivery on user error",
+			setExpectations: func(q *queue.MockQueue, dlq *queue.MockDeadLetterStore, h *queue.MockHandler) {
+				userErr := pe.InValidRequestError(ctx, "could not parse webhook", errRandom)
+				q.EXPECT().Dequeue(ctx).Return(delivery(1), nil).Once()
+				h.EXPECT().Handle(mock.Anything, delivery(1)).Return(userErr).Once()
+				dlq.EXPECT().Put(mock.Anything, failed(delivery(1), userErr)).Return(nil).Once()
+				q.EXPECT().Ack(mock.Anything, failed(delivery(1), userErr)).Return(nil).Once()
+			},
+			wantProcessed: true,
+			wantErr:       nil,
+		},
+		{
+			name: "should leave delivery in queue when dead letter store fails",
+			setExpectations: func(q *queue.MockQueue, dlq *queue.MockDeadLetterStore, h *queue.MockHandler) {
+				q.EXPECT().Dequeue(ctx).Return(delivery(5), nil).Once()
+				h.EXPECT().Handle(mock.Anything, delivery(5)).Return(errRandom).Once()
+				dlq.EXPECT().Put(mock.Anything, failed(delivery(5), errRandom)).Return(errRandom).Once()
+			},
+			wantProcessed: true,
+			wantErr:       errRandom,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := queue.NewMockQueue(t)
+			dlq := queue.NewMockDeadLetterStore(t)
+			h := queue.NewMockHandler(t)
+			tt.setExpectations(q, dlq, h)
+
+			p := queue.NewPool(q, dlq, h, poolConfig(), clockwork.NewFakeClock(), metrics.NewNoopEmitter())
+			processed, err := p.ProcessOne(ctx)
+			assert.Equal(t, tt.wantProcessed, processed)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Pool.ProcessOne() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPool_Start(t *testing.T) {
+	ctx := context.Background()
+	q := queue.NewInMemoryQueue(clockwork.NewRealClock(), time.Minute)
+	dlq := queue.NewMockDeadLetterStore(t)
+	h := queue.NewMockHandler(t)
+
+	handled := make(chan string, 2)
+	h.EXPECT().Handle(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, d *queue.Delivery) {
+			handled <- d.DeliveryID
+		}).Return(nil).Times(2)
+
+	cfg := poolConfig()
+	cfg.PollInterval = 10 * time.Millisecond
+	p := queue.NewPool(q, dlq, h, cfg, clockwork.NewRealClock(), metrics.NewNoopEmitter())
+	p.Start()
+
+	assert.Nil(t, q.Enqueue(ctx, &queue.Delivery{DeliveryID: "d1"}))
+	assert.Nil(t, q.Enqueue(ctx, &queue.Delivery{DeliveryID: "d2"}))
+
+	ids := []string{waitFor(t, handled), waitFor(t, handled)}
+	p.Close()
+
+	assert.ElementsMatch(t, []string{"d1", "d2"}, ids)
+	d, err := q.Dequeue(ctx)
+	assert.Nil(t, err)
+	assert.Nil(t, d)
+}
+
+func waitFor(t *testing.T, c chan string) string {
+	select {
+	case id := <-c:
+		return id
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for delivery to be handled")
+	}
+	return ""
+}
+
+func poolConfig() queue.PoolConfig {
+	return queue.PoolConfig{
+		Workers:      2,
+		MaxAttempts:  5,
+		PollInterval: time.Second,
+		BaseBackoff:  time.Second,
+		MaxBackoff:   5 * time.Second,
+		Timeout:      time.Minute,
+	}
+}
+
+func delivery(attempts int) *queue.Delivery {
+	return &queue.Delivery{
+		DeliveryID: "delivery1",
+		RequestID:  "request1",
+		EventName:  "pull_request",
+		Payload:    `{"action":"opened"}`,
+		Attempts:   attempts,
+	}
+}
+
+func failed(d *queue.Delivery, err error) *queue.Delivery {
+	d.LastError = err.Error()
+	return d
+}
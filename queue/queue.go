@@ -0,0 +1,61 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/marqeta/pr-bot/metrics"
+)
+
+var ErrDeliveryNotFound = errors.New("delivery not found in queue")
+
+// Delivery is a webhook delivery persisted in the queue until a worker processes it.
+type Delivery struct {
+	DeliveryID string `json:"delivery_id"`
+	RequestID  string `json:"request_id"`
+	EventName  string `json:"event_name"`
+	Payload    string `json:"payload"`
+	Attempts   int    `json:"attempts"`
+	LastError  string `json:"last_error,omitempty"`
+	EnqueuedAt int64  `json:"enqueued_at"`
+	VisibleAt  int64  `json:"visible_at"`
+}
+
+// Queue is a durable work queue of webhook deliveries.
+// Dequeue hides the returned delivery from other consumers until it is
+// acked, nacked or its visibility timeout expires, and counts the attempt,
+// so that attempts of workers which crash or hang are counted too.
+//
+//go:generate mockery --name Queue
+type Queue interface {
+	Enqueue(ctx context.Context, d *Delivery) error
+	// Dequeue returns nil when there are no deliveries ready to be processed,
+	// Attempts of the returned delivery includes the attempt it is dequeued for.
+	Dequeue(ctx context.Context) (*Delivery, error)
+	Ack(ctx context.Context, d *Delivery) error
+	// Nack makes the delivery visible again after delay.
+	Nack(ctx context.Context, d *Delivery, delay time.Duration) error
+}
+
+// DeadLetterStore keeps deliveries that could not be processed after all retries.
+//
+//go:generate mockery --name DeadLetterStore
+type DeadLetterStore interface {
+	Put(ctx context.Context, d *Delivery) error
+}
+
+// Handler processes a single delivery.
+//
+//go:generate mockery --name Handler
+type Handler interface {
+	Handle(ctx context.Context, d *Delivery) error
+}
+
+func emitError(ctx context.Context, m metrics.Emitter, name string, errCode string) {
+	m.EmitDist(ctx, "queue.dao.error", 1, []string{
+		fmt.Sprintf("call:%s", name),
+		fmt.Sprintf("code:%s", errCode),
+	})
+}
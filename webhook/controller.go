@@ -1,7 +1,7 @@
 package webhook
 
 import (
-	"context"
+	"fmt"
 	"net/http"
 
 	"github.com/go-chi/chi/v5/middleware"
@@ -10,7 +10,7 @@ import (
 	"github.com/google/go-github/v50/github"
 
 	pe "github.com/marqeta/pr-bot/errors"
-	"github.com/marqeta/pr-bot/opa/evaluation"
+	"github.com/marqeta/pr-bot/queue"
 )
 
 type EventResponse struct {
@@ -38,8 +38,8 @@ func (c *controller) HandleEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Parse event payload
-	event, err := c.parser.ParseWebHook(github.WebHookType(r), payload)
+	// Parse event payload, only to reject deliveries that can never be processed
+	_, err = c.parser.ParseWebHook(github.WebHookType(r), payload)
 	if err != nil {
 		oplog.Err(err).Msg("could not parse webhook")
 		pe.RenderError(w, r,
@@ -49,27 +49,25 @@ func (c *controller) HandleEvent(w http.ResponseWriter, r *http.Request) {
 
 	deliveryID := github.DeliveryID(r)
 	httplog.LogEntrySetField(ctx, "deliveryID", deliveryID)
-	ctx = context.WithValue(ctx, evaluation.DeliveryIDKey, deliveryID)
 
 	eventName := github.WebHookType(r)
 	httplog.LogEntrySetField(ctx, "eventName", eventName)
 	oplog = httplog.LogEntry(ctx)
 
-	switch event := event.(type) {
-
-	case *github.PullRequestEvent:
-		err = c.dispatcher.Dispatch(ctx, deliveryID, eventName, event)
-	case *github.PullRequestReviewEvent:
-		err = c.dispatcher.DispatchReview(ctx, deliveryID, eventName, event)
-
-	default:
-		oplog.Info().Msgf("No Handlers registered for Event: %s", eventName)
-	}
+	// events are processed asynchronously by queue workers,
+	// github expects a response within 10s.
+	err = c.queue.Enqueue(ctx, &queue.Delivery{
+		DeliveryID: deliveryID,
+		RequestID:  reqID,
+		EventName:  eventName,
+		Payload:    string(payload),
+	})
 
 	if err != nil {
-		oplog.Err(err).Msg("Error Handling Event")
-		pe.RenderError(w, r, err)
+		oplog.Err(err).Msg("Error Enqueuing Event")
+		pe.RenderError(w, r, pe.ServiceFault(ctx, "error enqueuing webhook event", err))
 	} else {
+		c.metrics.EmitDist(ctx, "queue.enqueued", 1, []string{fmt.Sprintf("eventName:%s", eventName)})
 		_ = render.Render(w, r, &EventResponse{
 			StatusCode: http.StatusAccepted,
 			RequestID:  reqID,
@@ -14,6 +14,7 @@ import (
 	prbot "github.com/marqeta/pr-bot"
 	"github.com/marqeta/pr-bot/metrics"
 	"github.com/marqeta/pr-bot/pullrequest"
+	"github.com/marqeta/pr-bot/queue"
 	"github.com/marqeta/pr-bot/webhook"
 	"github.com/stretchr/testify/assert"
 	mock "github.com/stretchr/testify/mock"
@@ -32,7 +33,7 @@ func Test_controller_HandleEvent(t *testing.T) {
 	type mockArgs struct {
 		ValidatePayloadErr error
 		ParseWebHookErr    error
-		DipatchErr         error
+		EnqueueErr         error
 	}
 	tests := []struct {
 		name     string
@@ -52,7 +53,7 @@ func Test_controller_HandleEvent(t *testing.T) {
 			wantCode: http.StatusAccepted,
 		},
 		{
-			name: "should handle error from dispatcher",
+			name: "should handle error from queue",
 			args: args{
 				requestID:     "request1",
 				deliveryID:    "",
@@ -63,7 +64,7 @@ func Test_controller_HandleEvent(t *testing.T) {
 			mockArgs: mockArgs{
 				ValidatePayloadErr: nil,
 				ParseWebHookErr:    nil,
-				DipatchErr:         errRandom,
+				EnqueueErr:         errRandom,
 			},
 			wantCode: http.StatusInternalServerError,
 		},
@@ -79,7 +80,7 @@ func Test_controller_HandleEvent(t *testing.T) {
 			mockArgs: mockArgs{
 				ValidatePayloadErr: nil,
 				ParseWebHookErr:    errRandom,
-				DipatchErr:         nil,
+				EnqueueErr:         nil,
 			},
 			wantCode: http.StatusBadRequest,
 		},
@@ -95,7 +96,7 @@ func Test_controller_HandleEvent(t *testing.T) {
 			mockArgs: mockArgs{
 				ValidatePayloadErr: errRandom,
 				ParseWebHookErr:    nil,
-				DipatchErr:         nil,
+				EnqueueErr:         nil,
 			},
 			wantCode: http.StatusBadRequest,
 		},
@@ -103,8 +104,8 @@ func Test_controller_HandleEvent(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			parser := webhook.NewMockParser(t)
-			dispatcher := pullrequest.NewMockDispatcher(t)
-			e := webhook.NewEndpoint(tt.args.webhookSecret, parser, dispatcher, metrics.NewNoopEmitter())
+			q := queue.NewMockQueue(t)
+			e := webhook.NewEndpoint(tt.args.webhookSecret, parser, q, metrics.NewNoopEmitter())
 
 			req, payload := NewRequest(t, tt.args.requestID, tt.args.deliveryID, tt.args.eventName, tt.args.event)
 
@@ -115,8 +116,12 @@ func Test_controller_HandleEvent(t *testing.T) {
 					Return(tt.args.event, tt.mockArgs.ParseWebHookErr).Once()
 			}
 			if tt.mockArgs.ParseWebHookErr == nil && tt.mockArgs.ValidatePayloadErr == nil {
-				dispatcher.EXPECT().Dispatch(mock.Anything, tt.args.deliveryID, tt.args.eventName, tt.args.event).
-					Return(tt.mockArgs.DipatchErr).Once()
+				q.EXPECT().Enqueue(mock.Anything, &queue.Delivery{
+					DeliveryID: tt.args.deliveryID,
+					RequestID:  tt.args.requestID,
+					EventName:  tt.args.eventName,
+					Payload:    string(payload),
+				}).Return(tt.mockArgs.EnqueueErr).Once()
 			}
 
 			res := executeRequest(req, e)
@@ -143,7 +148,7 @@ func Test_controller_HandleReviewEvent(t *testing.T) {
 	type mockArgs struct {
 		ValidatePayloadErr error
 		ParseWebHookErr    error
-		DipatchErr         error
+		EnqueueErr         error
 	}
 	tests := []struct {
 		name     string
@@ -163,7 +168,7 @@ func Test_controller_HandleReviewEvent(t *testing.T) {
 			wantCode: http.StatusAccepted,
 		},
 		{
-			name: "should handle error from review dispatcher",
+			name: "should handle error from queue for review event",
 			args: args{
 				requestID:     "request1",
 				deliveryID:    "",
@@ -174,7 +179,7 @@ func Test_controller_HandleReviewEvent(t *testing.T) {
 			mockArgs: mockArgs{
 				ValidatePayloadErr: nil,
 				ParseWebHookErr:    nil,
-				DipatchErr:         errRandom,
+				EnqueueErr:         errRandom,
 			},
 			wantCode: http.StatusInternalServerError,
 		},
@@ -190,7 +195,7 @@ func Test_controller_HandleReviewEvent(t *testing.T) {
 			mockArgs: mockArgs{
 				ValidatePayloadErr: nil,
 				ParseWebHookErr:    errRandom,
-				DipatchErr:         nil,
+				EnqueueErr:         nil,
 			},
 			wantCode: http.StatusBadRequest,
 		},
@@ -206,7 +211,7 @@ func Test_controller_HandleReviewEvent(t *testing.T) {
 			mockArgs: mockArgs{
 				ValidatePayloadErr: errRandom,
 				ParseWebHookErr:    nil,
-				DipatchErr:         nil,
+				EnqueueErr:         nil,
 			},
 			wantCode: http.StatusBadRequest,
 		},
@@ -214,8 +219,8 @@ func Test_controller_HandleReviewEvent(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			parser := webhook.NewMockParser(t)
-			dispatcher := pullrequest.NewMockDispatcher(t)
-			e := webhook.NewEndpoint(tt.args.webhookSecret, parser, dispatcher, metrics.NewNoopEmitter())
+			q := queue.NewMockQueue(t)
+			e := webhook.NewEndpoint(tt.args.webhookSecret, parser, q, metrics.NewNoopEmitter())
 
 			req, payload := NewRequest(t, tt.args.requestID, tt.args.deliveryID, tt.args.eventName, tt.args.event)
 
@@ -226,8 +231,12 @@ func Test_controller_HandleReviewEvent(t *testing.T) {
 					Return(tt.args.event, tt.mockArgs.ParseWebHookErr).Once()
 			}
 			if tt.mockArgs.ParseWebHookErr == nil && tt.mockArgs.ValidatePayloadErr == nil {
-				dispatcher.EXPECT().DispatchReview(mock.Anything, tt.args.deliveryID, tt.args.eventName, tt.args.event).
-					Return(tt.mockArgs.DipatchErr).Once()
+				q.EXPECT().Enqueue(mock.Anything, &queue.Delivery{
+					DeliveryID: tt.args.deliveryID,
+					RequestID:  tt.args.requestID,
+					EventName:  tt.args.eventName,
+					Payload:    string(payload),
+				}).Return(tt.mockArgs.EnqueueErr).Once()
 			}
 
 			res := executeRequest(req, e)
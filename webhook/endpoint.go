@@ -4,7 +4,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	prbot "github.com/marqeta/pr-bot"
 	"github.com/marqeta/pr-bot/metrics"
-	"github.com/marqeta/pr-bot/pullrequest"
+	"github.com/marqeta/pr-bot/queue"
 	"github.com/slok/go-http-metrics/middleware"
 	"github.com/slok/go-http-metrics/middleware/std"
 )
@@ -16,16 +16,16 @@ type endpoint struct {
 type controller struct {
 	webhookSecret string
 	parser        Parser
-	dispatcher    pullrequest.Dispatcher
+	queue         queue.Queue
 	metrics       metrics.Emitter
 }
 
-func NewEndpoint(ws string, p Parser, d pullrequest.Dispatcher, m metrics.Emitter) prbot.Endpoint {
+func NewEndpoint(ws string, p Parser, q queue.Queue, m metrics.Emitter) prbot.Endpoint {
 	return &endpoint{
 		controller: &controller{
 			webhookSecret: ws,
 			parser:        p,
-			dispatcher:    d,
+			queue:         q,
 			metrics:       m,
 		},
 	}
@@ -0,0 +1,66 @@
+package webhook
+
+import (
+	"context"
+
+	"github.com/go-chi/httplog"
+	"github.com/google/go-github/v50/github"
+
+	pe "github.com/marqeta/pr-bot/errors"
+	gh "github.com/marqeta/pr-bot/github"
+	"github.com/marqeta/pr-bot/opa/evaluation"
+	"github.com/marqeta/pr-bot/pullrequest"
+	"github.com/marqeta/pr-bot/queue"
+)
+
+type processor struct {
+	parser     Parser
+	dispatcher pullrequest.Dispatcher
+}
+
+// NewProcessor returns a queue.Handler which dispatches queued webhook deliveries.
+func NewProcessor(p Parser, d pullrequest.Dispatcher) queue.Handler {
+	return &processor{
+		parser:     p,
+		dispatcher: d,
+	}
+}
+
+// Handle implements queue.Handler.
+func (p *processor) Handle(ctx context.Context, d *queue.Delivery) error {
+	oplog := httplog.LogEntry(ctx)
+
+	event, err := p.parser.ParseWebHook(d.EventName, []byte(d.Payload))
+	if err != nil {
+		oplog.Err(err).Msg("could not parse webhook")
+		return pe.InValidRequestError(ctx, "could not parse webhook", err)
+	}
+
+	ctx = context.WithValue(ctx, evaluation.DeliveryIDKey, d.DeliveryID)
+	// files changed in the PR are listed once per delivery
+	ctx = gh.WithFilesChanged(ctx)
+
+	switch event := event.(type) {
+
+	case *github.PullRequestEvent:
+		err = p.dispatcher.Dispatch(ctx, d.DeliveryID, d.EventName, event)
+	case *github.PullRequestReviewEvent:
+		err = p.dispatcher.DispatchReview(ctx, d.DeliveryID, d.EventName, event)
+	case *github.CheckSuiteEvent:
+		err = p.dispatcher.DispatchCheckSuite(ctx, d.DeliveryID, d.EventName, event)
+	case *github.CheckRunEvent:
+		err = p.dispatcher.DispatchCheckRun(ctx, d.DeliveryID, d.EventName, event)
+	case *github.StatusEvent:
+		err = p.dispatcher.DispatchStatus(ctx, d.DeliveryID, d.EventName, event)
+	case *github.IssueCommentEvent:
+		err = p.dispatcher.DispatchComment(ctx, d.DeliveryID, d.EventName, event)
+
+	default:
+		oplog.Info().Msgf("No Handlers registered for Event: %s", d.EventName)
+	}
+
+	if err != nil {
+		oplog.Err(err).Msg("Error Handling Event")
+	}
+	return err
+}
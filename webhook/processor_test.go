@@ -0,0 +1,145 @@
+package webhook_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-github/v50/github"
+	"github.com/marqeta/pr-bot/pullrequest"
+	"github.com/marqeta/pr-bot/queue"
+	"github.com/marqeta/pr-bot/webhook"
+	mock "github.com/stretchr/testify/mock"
+)
+
+func Test_processor_Handle(t *testing.T) {
+	ctx := context.Background()
+	type args struct {
+		delivery *queue.Delivery
+		event    any
+	}
+	tests := []struct {
+		name            string
+		args            args
+		setExpectations func(d *queue.Delivery, event any, p *webhook.MockParser, dispatcher *pullrequest.MockDispatcher)
+		wantErr         error
+	}{
+		{
+			name: "should dispatch pull request event",
+			args: args{
+				delivery: delivery(pullrequest.EventName),
+				event:    &github.PullRequestEvent{},
+			},
+			setExpectations: func(d *queue.Delivery, event any, p *webhook.MockParser, dispatcher *pullrequest.MockDispatcher) {
+				p.EXPECT().ParseWebHook(d.EventName, []byte(d.Payload)).Return(event, nil).Once()
+				dispatcher.EXPECT().Dispatch(mock.Anything, d.DeliveryID, d.EventName, event).Return(nil).Once()
+			},
+			wantErr: nil,
+		},
+		{
+			name: "should dispatch pull request review event",
+			args: args{
+				delivery: delivery(pullrequest.EventNameReview),
+				event:    &github.PullRequestReviewEvent{},
+			},
+			setExpectations: func(d *queue.Delivery, event any, p *webhook.MockParser, dispatcher *pullrequest.MockDispatcher) {
+				p.EXPECT().ParseWebHook(d.EventName, []byte(d.Payload)).Return(event, nil).Once()
+				dispatcher.EXPECT().DispatchReview(mock.Anything, d.DeliveryID, d.EventName, event).Return(nil).Once()
+			},
+			wantErr: nil,
+		},
+		{
+			name: "should dispatch check suite event",
+			args: args{
+				delivery: delivery(pullrequest.EventNameCheckSuite),
+				event:    &github.CheckSuiteEvent{},
+			},
+			setExpectations: func(d *queue.Delivery, event any, p *webhook.MockParser, dispatcher *pullrequest.MockDispatcher) {
+				p.EXPECT().ParseWebHook(d.EventName, []byte(d.Payload)).Return(event, nil).Once()
+				dispatcher.EXPECT().DispatchCheckSuite(mock.Anything, d.DeliveryID, d.EventName, event).Return(nil).Once()
+			},
+			wantErr: nil,
+		},
+		{
+			name: "should dispatch check run event",
+			args: args{
+				delivery: delivery(pullrequest.EventNameCheckRun),
+				event:    &github.CheckRunEvent{},
+			},
+			setExpectations: func(d *queue.Delivery, event any, p *webhook.MockParser, dispatcher *pullrequest.MockDispatcher) {
+				p.EXPECT().ParseWebHook(d.EventName, []byte(d.Payload)).Return(event, nil).Once()
+				dispatcher.EXPECT().DispatchCheckRun(mock.Anything, d.DeliveryID, d.EventName, event).Return(nil).Once()
+			},
+			wantErr: nil,
+		},
+		{
+			name: "should dispatch status event",
+			args: args{
+				delivery: delivery(pullrequest.EventNameStatus),
+				event:    &github.StatusEvent{},
+			},
+			setExpectations: func(d *queue.Delivery, event any, p *webhook.MockParser, dispatcher *pullrequest.MockDispatcher) {
+				p.EXPECT().ParseWebHook(d.EventName, []byte(d.Payload)).Return(event, nil).Once()
+				dispatcher.EXPECT().DispatchStatus(mock.Anything, d.DeliveryID, d.EventName, event).Return(nil).Once()
+			},
+			wantErr: nil,
+		},
+		{
+			name: "should dispatch issue comment event",
+			args: args{
+				delivery: delivery(pullrequest.EventNameComment),
+				event:    &github.IssueCommentEvent{},
+			},
+			setExpectations: func(d *queue.Delivery, event any, p *webhook.MockParser, dispatcher *pullrequest.MockDispatcher) {
+				p.EXPECT().ParseWebHook(d.EventName, []byte(d.Payload)).Return(event, nil).Once()
+				dispatcher.EXPECT().DispatchComment(mock.Anything, d.DeliveryID, d.EventName, event).Return(nil).Once()
+			},
+			wantErr: nil,
+		},
+		{
+			name: "should ignore events without handlers",
+			args: args{
+				delivery: delivery("push"),
+				event:    &github.PushEvent{},
+			},
+			setExpectations: func(d *queue.Delivery, event any, p *webhook.MockParser, _ *pullrequest.MockDispatcher) {
+				p.EXPECT().ParseWebHook(d.EventName, []byte(d.Payload)).Return(event, nil).Once()
+			},
+			wantErr: nil,
+		},
+		{
+			name: "should return error from dispatcher",
+			args: args{
+				delivery: delivery(pullrequest.EventName),
+				event:    &github.PullRequestEvent{},
+			},
+			setExpectations: func(d *queue.Delivery, event any, p *webhook.MockParser, dispatcher *pullrequest.MockDispatcher) {
+				p.EXPECT().ParseWebHook(d.EventName, []byte(d.Payload)).Return(event, nil).Once()
+				dispatcher.EXPECT().Dispatch(mock.Anything, d.DeliveryID, d.EventName, event).Return(errRandom).Once()
+			},
+			wantErr: errRandom,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := webhook.NewMockParser(t)
+			dispatcher := pullrequest.NewMockDispatcher(t)
+			tt.setExpectations(tt.args.delivery, tt.args.event, parser, dispatcher)
+
+			p := webhook.NewProcessor(parser, dispatcher)
+			err := p.Handle(ctx, tt.args.delivery)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("processor.Handle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func delivery(eventName string) *queue.Delivery {
+	return &queue.Delivery{
+		DeliveryID: "delivery1",
+		RequestID:  "request1",
+		EventName:  eventName,
+		Payload:    `{"action":"opened"}`,
+	}
+}